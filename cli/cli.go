@@ -4,6 +4,7 @@ package cli
 import (
 	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
 	"github.com/jfrog/jfrog-cli-platform-services/commands"
+	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
 )
 
 const category = "Platform Services"
@@ -24,19 +25,36 @@ func getWorkerNamespace() components.Namespace {
 			"Each worker is defined by a manifest.json plus a worker.ts source file in a local directory. " +
 			"Typical lifecycle: 'jf worker init' to scaffold, 'jf worker test-run' to dry-run locally, 'jf worker deploy' to publish, " +
 			"'jf worker execute' to invoke a GENERIC_EVENT worker, 'jf worker undeploy' to remove. " +
-			"All commands require a JFrog Platform server configured via 'jf c add' or 'jf login' (or the JFROG_WORKER_CLI_DEV_* env vars).",
+			"All commands require a JFrog Platform server configured via 'jf c add' or 'jf login' (or the JFROG_WORKER_CLI_DEV_* env vars). " +
+			"Failed server calls exit with a code per error class: 10 unauthorized, 11 forbidden, 12 not found, 13 conflict, 14 invalid request, 15 timeout, 16 server error, 17 network error, 18 feature not supported by the server, 1 otherwise; " +
+			"with '--format json' the error is also printed as JSON on stdout. " +
+			"The commands printing results share the same output options: '--format json|yaml|csv|table|template|text' (each command lists the formats it supports, text being a human-readable report), " +
+			"'--template' with a Go template executed per item, and '--query' with a jq filter such as '.workers[] | select(.enabled == true) | .key'. " +
+			"Pass '--trace-http <file.har>' to record the HTTP exchanges with the server, with the token and secret values redacted, e.g. to attach them to a support case.",
 		Category: category,
-		Commands: []components.Command{
-			commands.GetInitCommand(),
-			commands.GetDryRunCommand(),
-			commands.GetDeployCommand(),
-			commands.GetExecuteCommand(),
-			commands.GetRemoveCommand(),
-			commands.GetListCommand(),
-			commands.GetAddSecretCommand(),
-			commands.GetListEventsCommand(),
-			commands.GetEditScheduleCommand(),
-			commands.GetShowExecutionHistoryCommand(),
-		},
+		Commands: GetWorkerCommands(),
 	}
 }
+
+// GetWorkerCommands returns the commands of the worker namespace, shared with the QA plugin.
+func GetWorkerCommands() []components.Command {
+	return common.WithErrorOutput(
+		commands.GetInitCommand(),
+		commands.GetDryRunCommand(),
+		commands.GetDeployCommand(),
+		commands.GetExecuteCommand(),
+		commands.GetWaitCommand(),
+		commands.GetRemoveCommand(),
+		commands.GetCopyCommand(),
+		commands.GetRenameCommand(),
+		commands.GetListCommand(),
+		commands.GetAddSecretCommand(),
+		commands.GetListEventsCommand(),
+		commands.GetExportMetadataCommand(),
+		commands.GetEditScheduleCommand(),
+		commands.GetShowExecutionHistoryCommand(),
+		commands.GetDoctorCommand(),
+		commands.GetSamplePayloadCommand(),
+		commands.GetBenchCommand(),
+	)
+}
@@ -0,0 +1,551 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	plugins_common "github.com/jfrog/jfrog-cli-core/v2/plugins/common"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+
+	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+	"github.com/jfrog/jfrog-cli-platform-services/workerclient"
+)
+
+const (
+	flagBenchIterations = "iterations"
+	flagBenchExecute    = "execute"
+	flagBenchBaseline   = "baseline"
+	flagBenchThreshold  = "threshold"
+	benchIterations     = 20
+	benchConcurrency    = 1
+	benchThreshold      = 10
+	benchModeTestRun    = "test-run"
+	benchModeExecute    = "execute"
+	// The tolerance on the start times of the execution history, set by the clock of the server
+	benchClockSkew = time.Minute
+)
+
+// The percentiles compared with a baseline, max is too sensitive to a single slow request
+var benchComparedMetrics = []string{"p50", "p95", "p99"}
+
+// benchStats are the statistics of durations, in milliseconds.
+type benchStats struct {
+	Min  float64 `json:"minMillis"`
+	P50  float64 `json:"p50Millis"`
+	P95  float64 `json:"p95Millis"`
+	P99  float64 `json:"p99Millis"`
+	Max  float64 `json:"maxMillis"`
+	Mean float64 `json:"meanMillis"`
+}
+
+type benchMetricChange struct {
+	Metric        string  `json:"metric"`
+	Baseline      float64 `json:"baselineMillis"`
+	Current       float64 `json:"currentMillis"`
+	ChangePercent float64 `json:"changePercent"`
+	Regression    bool    `json:"regression"`
+}
+
+type benchComparison struct {
+	File             string               `json:"file"`
+	ThresholdPercent float64              `json:"thresholdPercent"`
+	Changes          []*benchMetricChange `json:"changes"`
+}
+
+type benchReport struct {
+	Worker      string `json:"worker"`
+	Mode        string `json:"mode"`
+	Iterations  int    `json:"iterations"`
+	Concurrency int    `json:"concurrency"`
+	// Errors are the requests that failed, Failures the executions that returned a status other than a success
+	Errors        int            `json:"errors"`
+	Failures      int            `json:"failures"`
+	ErrorMessages map[string]int `json:"errorMessages,omitempty"`
+	// Latency is the round trip of the requests that got a response
+	Latency *benchStats `json:"latency,omitempty"`
+	// ServerTime is the execution time recorded by the server in the execution history, when it records the executions of the run
+	ServerTime *benchStats      `json:"serverTime,omitempty"`
+	Baseline   *benchComparison `json:"baseline,omitempty"`
+}
+
+type benchSample struct {
+	latency time.Duration
+	failed  bool
+	err     error
+}
+
+type benchCommandHandler struct {
+	ctx *components.Context
+	// The project of the measured worker
+	projectKey string
+}
+
+func GetBenchCommand() components.Command {
+	return components.Command{
+		Name:        "bench",
+		Description: "Measure the latency of a worker.",
+		AIDescription: `Send the same payload many times to the sandbox (test-run with the local source) or to the deployed worker (--execute), and report the latency percentiles, the errors and the execution time recorded by the server. A saved report can be used as a baseline, so that a change making the worker slower fails the command.
+
+When to use:
+- Checking the cost of a change on a worker in a hot path, e.g. a BEFORE_DOWNLOAD worker that delays every download.
+- Gating a CI pipeline on the latency of a worker with --baseline.
+- Measuring a deployed GENERIC_EVENT worker under concurrent calls.
+
+Prerequisites:
+- Configured server (jf c add or jf login).
+- For test-runs, a manifest.json and its source code in the current directory; for --execute, a deployed GENERIC_EVENT worker.
+
+Common patterns:
+  $ jf worker bench @payloads/sample.json
+  $ jf worker bench --iterations 100 --concurrency 4 @payloads/sample.json
+  $ jf worker bench --iterations 100 --format json @payloads/sample.json > baseline.json
+  $ jf worker bench --iterations 100 --baseline baseline.json --threshold 15 @payloads/sample.json
+  $ jf worker bench --execute my-worker '{"hello":"world"}'
+  $ jf worker bench --format table @payloads/sample.json
+
+Gotchas:
+- The latency is the round trip of the requests, network included. The measured requests are not retried, so that the latency is the one of a single attempt: a request failing with a retryable status is counted as an error, --retries only applies to the other requests (metadata, worker details).
+- The server time is the execution time of the executions of the run in the execution history (end time minus start time), read once the requests are done; the responses carry no execution time. It is omitted when the history is disabled or cannot be read, and it may miss the executions not yet recorded or include the ones of another client running the same worker meanwhile. It is not compared with the baseline.
+- The requests that fail are counted as errors and are not part of the latency; the executions returning a status other than a success are counted as failures and are part of it.
+- A baseline is the output of 'jf worker bench --format json'. The p50, p95 and p99 latencies are compared with it, and the command fails when one of them grew by more than --threshold percent (10 by default); p99 is sensitive to a few slow requests, measure with enough iterations. max is not compared.
+- Run the baseline and the new measure with the same iterations, concurrency and payload, from the same machine, or the comparison is meaningless.
+- The test-runs decrypt the secrets of manifest.json once, pass --no-secrets to omit them; --execute uses the secrets of the deployed worker.
+- The command fails when every request fails.
+
+Related: jf worker test-run, jf worker execute, jf worker execution-history`,
+		SupportedFormats: common.TextOutputFormats,
+		DefaultFormat:    common.FormatText,
+		Flags: slices.Concat([]components.Flag{
+			plugins_common.GetServerIdFlag(),
+			model.GetTimeoutFlag(),
+			model.GetProjectKeyFlag(),
+			model.GetNoSecretsFlag(),
+			components.NewStringFlag(flagBenchIterations, "The number of requests sent.", components.WithIntDefaultValue(benchIterations)),
+			model.GetConcurrencyFlag("The number of requests sent at the same time.", benchConcurrency),
+			components.NewBoolFlag(flagBenchExecute, "Execute the deployed worker instead of test-running the local source.", components.WithBoolDefaultValue(false)),
+			components.NewStringFlag(flagBenchBaseline, "A report of 'jf worker bench --format json' to compare with, the command fails on a regression.", components.WithStrDefaultValue("")),
+			components.NewStringFlag(flagBenchThreshold, "The growth of the p50, p95 or p99 latency over the baseline, in percent, above which the command fails.", components.WithIntDefaultValue(benchThreshold)),
+			model.GetTemplateFlag(),
+			model.GetQueryFlag(),
+		}, model.GetMetadataFlags(), model.GetHTTPClientFlags()),
+		Arguments: []components.Argument{
+			{
+				Name:        "worker-key",
+				Optional:    true,
+				Description: "The key of the worker executed with --execute. If not provided it will be read from the `manifest.json` in the current directory.",
+			},
+			model.GetJSONPayloadArgument(),
+		},
+		Action: func(c *components.Context) error {
+			h := &benchCommandHandler{ctx: c}
+			return h.run()
+		},
+	}
+}
+
+func (h *benchCommandHandler) run() error {
+	output, err := common.NewOutput(h.ctx)
+	if err != nil {
+		return err
+	}
+
+	iterations, err := h.ctx.GetIntFlagValue(flagBenchIterations)
+	if err != nil || iterations < 1 {
+		return fmt.Errorf("invalid --%s provided, expected a positive number", flagBenchIterations)
+	}
+
+	concurrency, err := model.GetConcurrencyParameter(h.ctx)
+	if err != nil {
+		return err
+	}
+
+	threshold, err := h.ctx.GetIntFlagValue(flagBenchThreshold)
+	if err != nil || threshold < 0 {
+		return fmt.Errorf("invalid --%s provided, expected a percentage", flagBenchThreshold)
+	}
+
+	var baseline *benchReport
+	if file := h.ctx.GetStringFlagValue(flagBenchBaseline); file != "" {
+		if baseline, err = readBenchBaseline(file); err != nil {
+			return err
+		}
+	}
+
+	server, err := common.GetServerDetails(h.ctx)
+	if err != nil {
+		return err
+	}
+
+	report := &benchReport{Iterations: iterations, Concurrency: concurrency}
+	var send func() benchSample
+	if h.ctx.GetBoolFlagValue(flagBenchExecute) {
+		report.Mode = benchModeExecute
+		report.Worker, send, err = h.prepareExecute(server)
+	} else {
+		report.Mode = benchModeTestRun
+		report.Worker, send, err = h.prepareTestRun(server)
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Info(fmt.Sprintf("Sending %d requests to %s (%s), %d at a time", iterations, report.Worker, report.Mode, concurrency))
+	start := time.Now()
+
+	samples := make([]benchSample, iterations)
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, concurrency)
+	for i := range samples {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			samples[i] = send()
+		}()
+	}
+	wg.Wait()
+
+	report.summarize(samples)
+	if report.Latency != nil {
+		report.ServerTime = h.fetchServerTime(server, report, start)
+	}
+
+	if baseline != nil {
+		report.Baseline = compareBenchReports(baseline, report, h.ctx.GetStringFlagValue(flagBenchBaseline), float64(threshold))
+	}
+
+	if err = output.Print(&common.Result{
+		Value: report,
+		Table: newBenchTable(report),
+		Text:  func() error { return printBenchReport(report) },
+	}); err != nil {
+		return err
+	}
+
+	if report.Errors == report.Iterations {
+		return common.ErrorAlreadyReported(fmt.Errorf("all the %d requests failed", report.Iterations))
+	}
+
+	if report.Baseline != nil {
+		var regressions []string
+		for _, change := range report.Baseline.Changes {
+			if change.Regression {
+				regressions = append(regressions, fmt.Sprintf("the %s latency grew by %.1f%%", change.Metric, change.ChangePercent))
+			}
+		}
+		if len(regressions) > 0 {
+			return common.ErrorAlreadyReported(fmt.Errorf("%s over the baseline, above the %.0f%% threshold", strings.Join(regressions, ", "), report.Baseline.ThresholdPercent))
+		}
+	}
+	return nil
+}
+
+// prepareTestRun returns the function test-running the local source of the current directory, the payload and the secrets are read once.
+func (h *benchCommandHandler) prepareTestRun(server *common.Server) (string, func() benchSample, error) {
+	manifest, err := common.ReadManifest()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err = common.CheckProjectSupport(h.ctx, server, manifest.ProjectKey); err != nil {
+		return "", nil, err
+	}
+	h.projectKey = manifest.ProjectKey
+
+	actionsMeta, err := common.FetchActions(h.ctx, server, manifest.ProjectKey)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err = common.ValidateManifest(manifest, actionsMeta); err != nil {
+		return "", nil, err
+	}
+
+	data, err := common.NewInputReader(h.ctx).ReadData()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if !h.ctx.GetBoolFlagValue(model.FlagNoSecrets) {
+		if err = common.DecryptManifestSecrets(manifest); err != nil {
+			return "", nil, err
+		}
+	}
+
+	dryRun := &dryRunHandler{ctx: h.ctx}
+	payload, err := dryRun.preparePayload(manifest, server, data)
+	if err != nil {
+		return "", nil, err
+	}
+
+	measured := benchServer(server)
+	return manifest.Name, func() benchSample {
+		response, roundTrip, err := dryRun.testRun(manifest, measured, payload)
+		return newBenchSample(response, roundTrip, err)
+	}, nil
+}
+
+// prepareExecute returns the function executing a deployed worker.
+func (h *benchCommandHandler) prepareExecute(server *common.Server) (string, func() benchSample, error) {
+	workerKey, projectKey, err := common.ExtractProjectAndKeyFromCommandContext(h.ctx, h.ctx.Arguments, 1, true)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err = common.CheckProjectSupport(h.ctx, server, projectKey); err != nil {
+		return "", nil, err
+	}
+	h.projectKey = projectKey
+
+	data, err := common.NewInputReader(h.ctx).ReadData()
+	if err != nil {
+		return "", nil, err
+	}
+
+	measured := benchServer(server)
+	return workerKey, func() benchSample {
+		start := time.Now()
+		response, err := common.CallWorkerClient(h.ctx, measured, func(ctx context.Context, client *workerclient.Client) (json.RawMessage, error) {
+			return client.Execute(ctx, workerKey, projectKey, data)
+		})
+		return newBenchSample(response, time.Since(start), err)
+	}, nil
+}
+
+// benchServer returns the server of the measured requests, which are sent once: the attempts and the backoff of a retry would be part of the latency.
+func benchServer(server *common.Server) *common.Server {
+	measured := *server
+	measured.Retry = &model.RetryPolicy{MaxAttempts: 1}
+	return &measured
+}
+
+// fetchServerTime returns the statistics of the execution times that the execution history records for the executions of the run,
+// nil when the history cannot be read or records none of them.
+// The newest entries started since the run, a minute earlier for the clock skew, are kept up to the number of requests that got a response.
+func (h *benchCommandHandler) fetchServerTime(server *common.Server, report *benchReport, start time.Time) *benchStats {
+	testRun := report.Mode == benchModeTestRun
+	filter := &common.ExecutionHistoryFilter{Since: start.Add(-benchClockSkew)}
+	options := workerclient.ExecutionHistoryOptions{WorkerKey: report.Worker, ProjectKey: h.projectKey, ShowTestRun: testRun}
+	entries, err := common.FetchExecutionHistory(h.ctx, server, options, filter, 0)
+	if err != nil {
+		log.Debug(fmt.Sprintf("The server time is not reported, the execution history cannot be read: %s", err))
+		return nil
+	}
+
+	entries = slices.DeleteFunc(filter.Apply(entries), func(entry *model.ExecutionHistoryEntry) bool {
+		return entry.TestRun != testRun || !entry.Completed() || entry.EndTimeMillis < entry.StartTimeMillis
+	})
+	common.SortExecutionHistory(entries)
+
+	var durations []time.Duration
+	for _, entry := range entries[:min(len(entries), report.Iterations-report.Errors)] {
+		durations = append(durations, time.Duration(entry.EndTimeMillis-entry.StartTimeMillis)*time.Millisecond)
+	}
+	if len(durations) < report.Iterations-report.Errors {
+		log.Debug(fmt.Sprintf("The execution history records %d of the %d executions of the run", len(durations), report.Iterations-report.Errors))
+	}
+	return newBenchStats(durations)
+}
+
+func newBenchSample(response json.RawMessage, roundTrip time.Duration, err error) benchSample {
+	sample := benchSample{latency: roundTrip, err: err}
+	if err != nil {
+		return sample
+	}
+	if result, isExecution := common.ParseExecutionResponse(response, roundTrip); isExecution {
+		sample.failed = result.Failed()
+	}
+	return sample
+}
+
+func (r *benchReport) summarize(samples []benchSample) {
+	var latencies []time.Duration
+	for _, sample := range samples {
+		if sample.err != nil {
+			r.Errors++
+			if r.ErrorMessages == nil {
+				r.ErrorMessages = map[string]int{}
+			}
+			r.ErrorMessages[sample.err.Error()]++
+			continue
+		}
+		if sample.failed {
+			r.Failures++
+		}
+		latencies = append(latencies, sample.latency)
+	}
+	r.Latency = newBenchStats(latencies)
+}
+
+// newBenchStats returns the statistics of durations with the nearest-rank percentiles, nil without durations.
+func newBenchStats(durations []time.Duration) *benchStats {
+	if len(durations) == 0 {
+		return nil
+	}
+	slices.Sort(durations)
+
+	percentile := func(p float64) float64 {
+		rank := int(math.Ceil(p / 100 * float64(len(durations))))
+		return toMillis(durations[max(rank, 1)-1])
+	}
+
+	var total time.Duration
+	for _, duration := range durations {
+		total += duration
+	}
+
+	return &benchStats{
+		Min:  toMillis(durations[0]),
+		P50:  percentile(50),
+		P95:  percentile(95),
+		P99:  percentile(99),
+		Max:  toMillis(durations[len(durations)-1]),
+		Mean: toMillis(total / time.Duration(len(durations))),
+	}
+}
+
+// toMillis returns a duration in milliseconds, rounded to the tenth.
+func toMillis(duration time.Duration) float64 {
+	return math.Round(float64(duration)/float64(time.Millisecond)*10) / 10
+}
+
+func (s *benchStats) metric(name string) float64 {
+	switch name {
+	case "p50":
+		return s.P50
+	case "p95":
+		return s.P95
+	case "p99":
+		return s.P99
+	default:
+		return s.Max
+	}
+}
+
+func readBenchBaseline(file string) (*benchReport, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the baseline: %w", err)
+	}
+	var baseline benchReport
+	if err = json.Unmarshal(content, &baseline); err != nil || baseline.Latency == nil {
+		return nil, fmt.Errorf("invalid baseline %s, expected the output of 'jf worker bench --format json'", file)
+	}
+	return &baseline, nil
+}
+
+// compareBenchReports compares the latencies with the ones of a baseline, a growth above the threshold is a regression.
+func compareBenchReports(baseline *benchReport, current *benchReport, file string, threshold float64) *benchComparison {
+	comparison := &benchComparison{File: file, ThresholdPercent: threshold}
+	if current.Latency == nil {
+		return comparison
+	}
+
+	if baseline.Iterations != current.Iterations || baseline.Concurrency != current.Concurrency || baseline.Mode != current.Mode {
+		log.Warn(fmt.Sprintf("The baseline was measured with %d iterations, a concurrency of %d and %s, the comparison may not be meaningful",
+			baseline.Iterations, baseline.Concurrency, baseline.Mode))
+	}
+
+	for _, metric := range benchComparedMetrics {
+		change := &benchMetricChange{Metric: metric, Baseline: baseline.Latency.metric(metric), Current: current.Latency.metric(metric)}
+		if change.Baseline > 0 {
+			change.ChangePercent = math.Round((change.Current-change.Baseline)/change.Baseline*1000) / 10
+		}
+		change.Regression = change.ChangePercent > threshold
+		comparison.Changes = append(comparison.Changes, change)
+	}
+	return comparison
+}
+
+func newBenchTable(report *benchReport) *common.Table {
+	table := &common.Table{Headers: []string{"METRIC", "MIN", "P50", "P95", "P99", "MAX", "MEAN"}}
+	if report.Latency != nil {
+		table.Rows = append(table.Rows, report.Latency.row("latency"))
+	}
+	if report.ServerTime != nil {
+		table.Rows = append(table.Rows, report.ServerTime.row("server time"))
+	}
+	return table
+}
+
+// row returns the statistics as a table row, after the name of the metric.
+func (s *benchStats) row(name string) []string {
+	return []string{name, formatMillis(s.Min), formatMillis(s.P50), formatMillis(s.P95), formatMillis(s.P99), formatMillis(s.Max), formatMillis(s.Mean)}
+}
+
+func formatMillis(millis float64) string {
+	return strconv.FormatFloat(millis, 'f', -1, 64) + "ms"
+}
+
+func printBenchReport(report *benchReport) error {
+	if err := common.Print("%s (%s): %d requests, %d at a time\n\n", report.Worker, report.Mode, report.Iterations, report.Concurrency); err != nil {
+		return err
+	}
+
+	if table := newBenchTable(report); len(table.Rows) > 0 {
+		writer := common.NewTableWriter()
+		if err := common.WriteTableRow(writer, table.Headers...); err != nil {
+			return err
+		}
+		for _, row := range table.Rows {
+			if err := common.WriteTableRow(writer, row...); err != nil {
+				return err
+			}
+		}
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+	}
+
+	requestErrors := fmt.Sprintf("%d errors", report.Errors)
+	if report.Errors > 0 {
+		requestErrors = common.Colorize(requestErrors, common.ColorRed)
+	}
+	failures := fmt.Sprintf("%d failed executions", report.Failures)
+	if report.Failures > 0 {
+		failures = common.Colorize(failures, common.ColorYellow)
+	}
+	if err := common.Print("\n%s, %s\n", requestErrors, failures); err != nil {
+		return err
+	}
+
+	messages := make([]string, 0, len(report.ErrorMessages))
+	for message := range report.ErrorMessages {
+		messages = append(messages, message)
+	}
+	slices.Sort(messages)
+	for _, message := range messages {
+		if err := common.Print("    %dx %s\n", report.ErrorMessages[message], message); err != nil {
+			return err
+		}
+	}
+
+	if report.Baseline == nil {
+		return nil
+	}
+
+	if err := common.Print("\nBaseline %s (threshold %.0f%%):\n", report.Baseline.File, report.Baseline.ThresholdPercent); err != nil {
+		return err
+	}
+	for _, change := range report.Baseline.Changes {
+		status := common.Colorize("ok", common.ColorGreen)
+		if change.Regression {
+			status = common.Colorize("REGRESSION", common.ColorRed)
+		}
+		if err := common.Print("    %-4s %s -> %s (%+.1f%%) %s\n", change.Metric, formatMillis(change.Baseline), formatMillis(change.Current), change.ChangePercent, status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+//go:build test
+// +build test
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/common/format"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+)
+
+func TestBenchCommand(t *testing.T) {
+	// The server reports 1ms to 10ms of execution, and every fifth execution fails
+	var calls atomic.Int64
+	serverStub := common.NewServerStub(t).
+		WithWorkers(&model.WorkerDetails{Key: workerKeyForDryRunTest}).
+		WithDefaultActionsMetadataEndpoint().
+		WithGetOneEndpoint().
+		WithTestEndpoint(validateTestPayloadData(map[string]any{"n": 1.0}), common.ResponseBodyFunc(func(t *testing.T, requestBody []byte) any {
+			call := calls.Add(1)
+			status := "STATUS_SUCCESS"
+			if call%5 == 0 {
+				status = "STATUS_FAIL"
+			}
+			return map[string]any{"beforeDownload": map[string]any{
+				"data":            map[string]any{"status": 1, "message": "ok"},
+				"executionStatus": status,
+			}}
+		}))
+	common.NewMockWorkerServer(t, serverStub)
+	dir, _ := common.PrepareWorkerDirForTest(t)
+
+	runCmd := common.CreateCliRunner(t, GetInitCommand(), GetBenchCommand())
+	require.NoError(t, runCmd("worker", "init", "BEFORE_DOWNLOAD", workerKeyForDryRunTest))
+
+	var out bytes.Buffer
+	common.SetCliOut(&out)
+	t.Cleanup(func() { common.SetCliOut(os.Stdout) })
+
+	require.NoError(t, runCmd("worker", "bench", "--"+flagBenchIterations, "10", "--"+model.FlagConcurrency, "3", "--"+format.FlagName, "json", `{"n":1}`))
+
+	var report benchReport
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	assert.Equal(t, workerKeyForDryRunTest, report.Worker)
+	assert.Equal(t, benchModeTestRun, report.Mode)
+	assert.Equal(t, []int{10, 3, 0, 2}, []int{report.Iterations, report.Concurrency, report.Errors, report.Failures})
+	require.NotNil(t, report.Latency)
+
+	baselineFile := filepath.Join(dir, "baseline.json")
+	writeBaseline := func(p50 float64) {
+		baseline := &benchReport{Mode: benchModeTestRun, Iterations: 2, Concurrency: 1, Latency: &benchStats{P50: p50, P95: p50}}
+		require.NoError(t, os.WriteFile(baselineFile, []byte(common.MustJsonMarshal(t, baseline)), 0644))
+	}
+
+	writeBaseline(60000)
+	out.Reset()
+	require.NoError(t, runCmd("worker", "bench", "--"+flagBenchIterations, "2", "--"+flagBenchBaseline, baselineFile, `{"n":1}`))
+	assert.Contains(t, out.String(), workerKeyForDryRunTest+" (test-run): 2 requests, 1 at a time\n")
+	assert.Contains(t, out.String(), "Baseline "+baselineFile+" (threshold 10%):\n    p50  60000ms -> ")
+
+	writeBaseline(0.01)
+	out.Reset()
+	err := runCmd("worker", "bench", "--"+flagBenchIterations, "2", "--"+flagBenchBaseline, baselineFile, `{"n":1}`)
+	assert.ErrorContains(t, err, "the p50 latency grew by")
+	assert.Contains(t, out.String(), "REGRESSION")
+
+	// Only the p99 latency of this baseline is below the measured one
+	baseline := &benchReport{Mode: benchModeTestRun, Iterations: 2, Concurrency: 1, Latency: &benchStats{P50: 60000, P95: 60000, P99: 0.01}}
+	require.NoError(t, os.WriteFile(baselineFile, []byte(common.MustJsonMarshal(t, baseline)), 0644))
+	err = runCmd("worker", "bench", "--"+flagBenchIterations, "2", "--"+flagBenchBaseline, baselineFile, `{"n":1}`)
+	assert.ErrorContains(t, err, "the p99 latency grew by")
+	assert.NotContains(t, err.Error(), "p50")
+
+	require.NoError(t, os.WriteFile(baselineFile, []byte(`{}`), 0644))
+	err = runCmd("worker", "bench", "--"+flagBenchBaseline, baselineFile, `{"n":1}`)
+	assert.EqualError(t, err, "invalid baseline "+baselineFile+", expected the output of 'jf worker bench --format json'")
+
+	err = runCmd("worker", "bench", "--"+flagBenchIterations, "0", `{"n":1}`)
+	assert.EqualError(t, err, "invalid --iterations provided, expected a positive number")
+}
+
+func TestBenchCommand_Execute(t *testing.T) {
+	// The history records three executions of the run, an older execution and a test-run
+	startedAt := time.Now().UnixMilli()
+	newEntry := func(traceID string, startTimeMillis int64, durationMillis int64, testRun bool) *common.ExecutionHistoryEntryStub {
+		return &common.ExecutionHistoryEntryStub{WorkerKey: "my-worker", ExecutionStatus: "STATUS_SUCCESS", StartTimeMillis: startTimeMillis, EndTimeMillis: startTimeMillis + durationMillis, TestRun: testRun, TraceID: traceID}
+	}
+	serverStub := common.NewServerStub(t).
+		WithExecuteEndpoint(nil, map[string]any{"data": "done", "executionStatus": "STATUS_SUCCESS"}).
+		WithWorkerExecutionHistory("my-worker", common.ExecutionHistoryStub{
+			newEntry("a", startedAt, 10, false),
+			newEntry("b", startedAt+1, 30, false),
+			newEntry("c", startedAt+2, 20, false),
+			newEntry("old", startedAt-time.Hour.Milliseconds(), 1000, false),
+			newEntry("test-run", startedAt+3, 1000, true),
+		}).
+		WithGetExecutionHistoryEndpoint()
+	common.NewMockWorkerServer(t, serverStub)
+	common.PrepareWorkerDirForTest(t)
+
+	runCmd := common.CreateCliRunner(t, GetBenchCommand())
+
+	var out bytes.Buffer
+	common.SetCliOut(&out)
+	t.Cleanup(func() { common.SetCliOut(os.Stdout) })
+
+	require.NoError(t, runCmd("worker", "bench", "--"+flagBenchExecute, "--"+flagBenchIterations, "4", "--"+format.FlagName, "json", "my-worker", `{}`))
+
+	var report benchReport
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	assert.Equal(t, "my-worker", report.Worker)
+	assert.Equal(t, benchModeExecute, report.Mode)
+	assert.Equal(t, 0, report.Errors)
+	assert.NotNil(t, report.Latency)
+	require.NotNil(t, report.ServerTime)
+	assert.Equal(t, []float64{10, 20, 30}, []float64{report.ServerTime.Min, report.ServerTime.P50, report.ServerTime.Max})
+
+	out.Reset()
+	// Only the two newest executions of the history belong to a run of two requests
+	require.NoError(t, runCmd("worker", "bench", "--"+flagBenchExecute, "--"+flagBenchIterations, "2", "my-worker", `{}`))
+	assert.Regexp(t, `server time +20ms +20ms +30ms +30ms +30ms +25ms`, out.String())
+}
+
+func TestBenchCommand_NoRetries(t *testing.T) {
+	var calls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if strings.HasSuffix(req.URL.Path, "/execute/my-worker") {
+			calls.Add(1)
+		}
+		res.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	common.TestSetEnv(t, model.EnvKeyServerURL, server.URL)
+	common.TestSetEnv(t, model.EnvKeyAccessToken, "a-token")
+	common.TestSetEnv(t, coreutils.HomeDir, t.TempDir())
+
+	var out bytes.Buffer
+	common.SetCliOut(&out)
+	t.Cleanup(func() { common.SetCliOut(os.Stdout) })
+
+	runCmd := common.CreateCliRunner(t, GetBenchCommand())
+
+	err := runCmd("worker", "bench", "--"+flagBenchExecute, "--"+flagBenchIterations, "3", "--"+model.FlagRetries, "3", "--"+model.FlagRetryBackoff, "1", "--"+format.FlagName, "json", "my-worker", `{}`)
+	assert.EqualError(t, err, "all the 3 requests failed")
+	assert.Equal(t, int64(3), calls.Load(), "the measured requests are sent once whatever --retries")
+
+	var report benchReport
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	assert.Equal(t, 3, report.Errors)
+}
+
+func TestCompareBenchReports(t *testing.T) {
+	baseline := &benchReport{Mode: benchModeTestRun, Iterations: 100, Concurrency: 1, Latency: &benchStats{P50: 10, P95: 20, P99: 40, Max: 50}}
+	current := &benchReport{Mode: benchModeTestRun, Iterations: 100, Concurrency: 1, Latency: &benchStats{P50: 10.5, P95: 19, P99: 50, Max: 500}}
+
+	comparison := compareBenchReports(baseline, current, "baseline.json", 10)
+
+	assert.Equal(t, &benchComparison{File: "baseline.json", ThresholdPercent: 10, Changes: []*benchMetricChange{
+		{Metric: "p50", Baseline: 10, Current: 10.5, ChangePercent: 5},
+		{Metric: "p95", Baseline: 20, Current: 19, ChangePercent: -5},
+		{Metric: "p99", Baseline: 40, Current: 50, ChangePercent: 25, Regression: true},
+	}}, comparison, "max is not compared")
+}
+
+func TestNewBenchStats(t *testing.T) {
+	var durations []time.Duration
+	for i := 100; i > 0; i-- {
+		durations = append(durations, time.Duration(i)*time.Millisecond)
+	}
+	assert.Equal(t, &benchStats{Min: 1, P50: 50, P95: 95, P99: 99, Max: 100, Mean: 50.5}, newBenchStats(durations))
+	assert.Nil(t, newBenchStats(nil))
+}
@@ -0,0 +1,168 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/jfrog/gofrog/version"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+	"github.com/jfrog/jfrog-cli-platform-services/workerclient"
+)
+
+// Capabilities tells which features of the Worker Service are supported by a server.
+// A feature whose support cannot be determined, e.g. because the server is too old to expose its options, is considered supported:
+// the server remains the judge, and reports the error itself.
+type Capabilities struct {
+	// The version of Artifactory, empty when it cannot be determined
+	ArtifactoryVersion string
+	// The options of the Worker Service, nil when the server does not expose them
+	Options *model.OptionsMetadata
+}
+
+// Requirement checks that a feature needed by a command is supported. A nil requirement is always met.
+type Requirement func(capabilities *Capabilities) error
+
+// Check fails when the requirement is not met by the capabilities.
+func (r Requirement) Check(capabilities *Capabilities) error {
+	if r == nil {
+		return nil
+	}
+	return r(capabilities)
+}
+
+// ProbeCapabilities collects the options of the Worker Service and the version of Artifactory.
+// Both are cached like the other metadata, and the options can be provided with --options-file.
+// Only the errors of the options endpoint are returned, a server without this endpoint results in capabilities without options.
+func ProbeCapabilities(c model.IntFlagProvider, server *Server) (*Capabilities, error) {
+	capabilities := &Capabilities{}
+
+	options, err := FetchOptions(c, server)
+	switch {
+	case err == nil:
+		capabilities.Options = options
+	case ExitCodeOf(err) == ExitCodeNotFound:
+		log.Debug("The server does not expose the options of the Worker Service")
+	default:
+		return nil, err
+	}
+
+	capabilities.ArtifactoryVersion = fetchArtifactoryVersion(c, server)
+
+	return capabilities, nil
+}
+
+// CheckCapabilities fails when one of the requirements is not met by the server.
+// The server is not probed when every requirement is nil, i.e. always met.
+// As the check is only meant to give a clear error early, a failure of the probe is ignored.
+func CheckCapabilities(c model.IntFlagProvider, server *Server, requirements ...Requirement) error {
+	requirements = slices.DeleteFunc(slices.Clone(requirements), func(requirement Requirement) bool { return requirement == nil })
+	if server == nil || server.ServerDetails == nil || server.GetUrl() == "" || len(requirements) == 0 {
+		return nil
+	}
+
+	capabilities, err := ProbeCapabilities(c, server)
+	if err != nil {
+		log.Debug(fmt.Sprintf("Cannot determine the capabilities of the server: %+v", err))
+		return nil
+	}
+
+	for _, requirement := range requirements {
+		if err = requirement.Check(capabilities); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CheckProjectSupport fails when a project key is provided to a server that does not support projects.
+func CheckProjectSupport(c model.IntFlagProvider, server *Server, projectKey string) error {
+	return CheckCapabilities(c, server, RequireProjectSupport(projectKey))
+}
+
+// SupportsBase64SourceCode tells whether the source code can be sent encoded in base64.
+func (c *Capabilities) SupportsBase64SourceCode() bool {
+	return c.Options != nil && c.Options.ShouldEncodeSourceCodeInBase64 != nil
+}
+
+// ShouldEncodeSourceCodeInBase64 tells whether the server expects the source code encoded in base64 by default.
+func (c *Capabilities) ShouldEncodeSourceCodeInBase64() bool {
+	return c.SupportsBase64SourceCode() && *c.Options.ShouldEncodeSourceCodeInBase64
+}
+
+// SupportsProjects tells whether workers can be scoped to a project, i.e. whether Artifactory is at least MinArtifactoryVersionForProjectSupport.
+func (c *Capabilities) SupportsProjects() bool {
+	if c.Options == nil || c.Options.MinArtifactoryVersionForProjectSupport == "" || c.ArtifactoryVersion == "" {
+		return true
+	}
+	return version.NewVersion(c.ArtifactoryVersion).AtLeast(c.Options.MinArtifactoryVersionForProjectSupport)
+}
+
+// SupportsExecutionHistory tells whether the executions of the workers are recorded.
+func (c *Capabilities) SupportsExecutionHistory() bool {
+	return c.Options == nil || c.Options.IsHistoryEnabled
+}
+
+// RequireProjectSupport is met when projectKey is empty, the requirement is then nil, or when the server supports projects.
+func RequireProjectSupport(projectKey string) Requirement {
+	if projectKey == "" {
+		return nil
+	}
+	return func(capabilities *Capabilities) error {
+		if capabilities.SupportsProjects() {
+			return nil
+		}
+		err := unsupportedError("the server does not support projects, Artifactory %s or later is required (found %s)",
+			capabilities.Options.MinArtifactoryVersionForProjectSupport, capabilities.ArtifactoryVersion)
+		err.Hint = fmt.Sprintf("Upgrade Artifactory to %s or later, or run the command without --%s to use global workers.",
+			capabilities.Options.MinArtifactoryVersionForProjectSupport, model.FlagProjectKey)
+		return err
+	}
+}
+
+// RequireExecutionHistory is met when the server records the executions of the workers.
+func RequireExecutionHistory() Requirement {
+	return func(capabilities *Capabilities) error {
+		if capabilities.SupportsExecutionHistory() {
+			return nil
+		}
+		err := unsupportedError("the execution history is not enabled on this server")
+		if capabilities.ArtifactoryVersion != "" {
+			err.Message += fmt.Sprintf(" (Artifactory %s)", capabilities.ArtifactoryVersion)
+		}
+		err.Hint = "Upgrade the server to a version of the Worker Service that records the executions, or ask your administrator to enable the history ('isHistoryEnabled' in the output of 'jf worker export-metadata')."
+		return err
+	}
+}
+
+func unsupportedError(message string, args ...any) *APIError {
+	err := localError(message, args...)
+	err.exitCode = ExitCodeUnsupported
+	return err
+}
+
+// fetchArtifactoryVersion returns the version of Artifactory, or an empty string if it cannot be read, e.g. with a token not allowed to read it.
+func fetchArtifactoryVersion(c model.IntFlagProvider, server *Server) string {
+	content, err := fetchCachedMetadata(c, server, "artifactory-version", &workerclient.Request{
+		Method:   http.MethodGet,
+		Endpoint: workerclient.ArtifactoryVersionEndpoint,
+	})
+	if err != nil {
+		log.Debug(fmt.Sprintf("Cannot read the version of Artifactory: %+v", err))
+		return ""
+	}
+
+	var artifactoryVersion struct {
+		Version string `json:"version"`
+	}
+	if err = json.Unmarshal(content, &artifactoryVersion); err != nil {
+		log.Debug(fmt.Sprintf("Cannot read the version of Artifactory: %+v", err))
+		return ""
+	}
+
+	return artifactoryVersion.Version
+}
@@ -0,0 +1,120 @@
+//go:build test
+// +build test
+
+package common
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+)
+
+func TestCapabilities(t *testing.T) {
+	withBase64 := true
+
+	tests := []struct {
+		name                 string
+		capabilities         *Capabilities
+		wantBase64           bool
+		wantProjects         bool
+		wantExecutionHistory bool
+	}{
+		{
+			name:                 "unknown",
+			capabilities:         &Capabilities{},
+			wantProjects:         true,
+			wantExecutionHistory: true,
+		},
+		{
+			name: "recent server",
+			capabilities: &Capabilities{
+				ArtifactoryVersion: "7.104.2",
+				Options: &model.OptionsMetadata{
+					MinArtifactoryVersionForProjectSupport: "7.95.0",
+					IsHistoryEnabled:                       true,
+					ShouldEncodeSourceCodeInBase64:         &withBase64,
+				},
+			},
+			wantBase64:           true,
+			wantProjects:         true,
+			wantExecutionHistory: true,
+		},
+		{
+			name: "old server",
+			capabilities: &Capabilities{
+				ArtifactoryVersion: "7.90.1",
+				Options:            &model.OptionsMetadata{MinArtifactoryVersionForProjectSupport: "7.95.0"},
+			},
+		},
+		{
+			name: "unknown Artifactory version",
+			capabilities: &Capabilities{
+				Options: &model.OptionsMetadata{MinArtifactoryVersionForProjectSupport: "7.95.0"},
+			},
+			wantProjects: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantBase64, tt.capabilities.SupportsBase64SourceCode())
+			assert.Equal(t, tt.wantProjects, tt.capabilities.SupportsProjects())
+			assert.Equal(t, tt.wantExecutionHistory, tt.capabilities.SupportsExecutionHistory())
+
+			assert.NoError(t, RequireProjectSupport("").Check(tt.capabilities))
+			if tt.wantProjects {
+				assert.NoError(t, RequireProjectSupport("proj").Check(tt.capabilities))
+			} else {
+				err := RequireProjectSupport("proj").Check(tt.capabilities)
+				require.Error(t, err)
+				assert.Equal(t, ExitCodeUnsupported, ExitCodeOf(err))
+			}
+		})
+	}
+}
+
+func TestProbeCapabilities(t *testing.T) {
+	t.Run("probe", func(t *testing.T) {
+		server, token := NewMockWorkerServer(t, NewServerStub(t).WithOptionsEndpoint().WithArtifactoryVersionEndpoint("7.104.2"))
+
+		capabilities, err := ProbeCapabilities(IntFlagMap{}, TestServer(server.BaseUrl(), token))
+		require.NoError(t, err)
+
+		assert.Equal(t, "7.104.2", capabilities.ArtifactoryVersion)
+		assert.Equal(t, LoadSampleOptions(t), capabilities.Options)
+	})
+
+	t.Run("server without options", func(t *testing.T) {
+		server, token := NewMockWorkerServer(t, NewServerStub(t))
+
+		capabilities, err := ProbeCapabilities(IntFlagMap{}, TestServer(server.BaseUrl(), token))
+		require.NoError(t, err)
+
+		assert.Equal(t, &Capabilities{}, capabilities)
+	})
+
+	t.Run("check ignores probe errors", func(t *testing.T) {
+		server, _ := NewMockWorkerServer(t, NewServerStub(t).WithOptionsEndpoint())
+
+		assert.NoError(t, CheckCapabilities(IntFlagMap{}, TestServer(server.BaseUrl(), "invalid-token"), RequireExecutionHistory()))
+	})
+
+	t.Run("check without requirement to meet", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			calls.Add(1)
+			res.WriteHeader(http.StatusInternalServerError)
+		}))
+		t.Cleanup(server.Close)
+
+		assert.NoError(t, CheckCapabilities(IntFlagMap{}, TestServer(server.URL, "token"), RequireProjectSupport("")))
+		assert.NoError(t, CheckProjectSupport(IntFlagMap{}, TestServer(server.URL, "token"), ""))
+		assert.Zero(t, calls.Load(), "the server should not be probed")
+	})
+}
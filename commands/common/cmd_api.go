@@ -2,143 +2,175 @@
 package common
 
 import (
-	"bytes"
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
-	"slices"
 	"strings"
+	"time"
 
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-cli-platform-services/model"
-	"github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-cli-platform-services/workerclient"
+	"github.com/jfrog/jfrog-client-go/utils/log"
 )
 
-type apiVersion int
-
 const (
-	APIVersionV1 apiVersion = iota + 1
-	APIVersionV2 apiVersion = 2
+	APIVersionV1 = workerclient.APIVersionV1
+	APIVersionV2 = workerclient.APIVersionV2
 )
 
 type APIContentHandler func(content []byte) error
 
-type APIError struct {
-	StatusCode int
-	Message    string
-}
-
-func (e *APIError) Error() string {
-	return e.Message
-}
-
-func apiError(status int, message string, args ...any) *APIError {
-	return &APIError{
-		StatusCode: status,
-		Message:    fmt.Sprintf(message, args...),
-	}
-}
-
 type APICallParams struct {
-	Method        string
-	ServerURL     string
-	ServerToken   string
+	Method      string
+	ServerURL   string
+	ServerToken string
+	// HTTPClient is the client of the server, as configured by GetServerDetails, http.DefaultClient when nil
+	HTTPClient    *http.Client
 	Body          []byte
 	Query         map[string]string
 	Path          []string
 	ProjectKey    string
-	APIVersion    apiVersion
+	APIVersion    workerclient.APIVersion
 	OkStatuses    []int
 	OnContent     APIContentHandler
 	CaptureStatus *int
+	// Retry overrides the retry policy provided by the command flags.
+	Retry *model.RetryPolicy
+	// Idempotent marks a call using a non-idempotent method (e.g. POST) as safe to retry.
+	Idempotent bool
 }
 
 func CallWorkerAPI(c model.IntFlagProvider, params APICallParams) error {
-	timeout, err := model.GetTimeoutParameter(c)
+	server := &Server{ServerDetails: &config.ServerDetails{Url: params.ServerURL, AccessToken: params.ServerToken}, HTTPClient: params.HTTPClient}
+	res, err := callWorkerClient(c, server, params.Retry, func(ctx context.Context, client *workerclient.Client) (*workerclient.Response, error) {
+		return client.Do(ctx, &workerclient.Request{
+			Method:     params.Method,
+			Path:       params.Path,
+			Query:      params.Query,
+			ProjectKey: params.ProjectKey,
+			APIVersion: params.APIVersion,
+			Body:       params.Body,
+			OkStatuses: params.OkStatuses,
+			Idempotent: params.Idempotent,
+		})
+	})
 	if err != nil {
-		return apiError(http.StatusInternalServerError, "%+v", err)
+		return err
 	}
 
-	apiVersion := APIVersionV1
-	if params.APIVersion != 0 {
-		apiVersion = params.APIVersion
+	if params.CaptureStatus != nil {
+		*params.CaptureStatus = res.StatusCode
 	}
 
-	apiEndpoint := fmt.Sprintf("%sworker/api/v%d/%s", utils.AddTrailingSlashIfNeeded(params.ServerURL), apiVersion, strings.Join(params.Path, "/"))
+	if params.OnContent == nil {
+		return nil
+	}
 
-	q := url.Values{}
+	return params.OnContent(res.Body)
+}
 
-	if params.ProjectKey != "" {
-		q.Set("projectKey", params.ProjectKey)
-	}
+// CallWorkerClient calls the server with a workerclient.Client configured from the command flags and the HTTP client of the server.
+// The call is bound to the timeout of the command, and its errors are converted to *APIError.
+func CallWorkerClient[T any](c model.IntFlagProvider, server *Server, call func(ctx context.Context, client *workerclient.Client) (T, error)) (T, error) {
+	return callWorkerClient(c, server, nil, call)
+}
 
-	for key, value := range params.Query {
-		q.Set(key, value)
-	}
+// RunWorkerClient is like CallWorkerClient, for the calls that do not return a result.
+func RunWorkerClient(c model.IntFlagProvider, server *Server, call func(ctx context.Context, client *workerclient.Client) error) error {
+	_, err := CallWorkerClient(c, server, func(ctx context.Context, client *workerclient.Client) (struct{}, error) {
+		return struct{}{}, call(ctx, client)
+	})
+	return err
+}
 
-	reqCtx, cancelReq := context.WithTimeout(context.Background(), timeout)
-	defer cancelReq()
+func callWorkerClient[T any](c model.IntFlagProvider, server *Server, retryPolicy *model.RetryPolicy, call func(ctx context.Context, client *workerclient.Client) (T, error)) (T, error) {
+	var zero T
 
-	var bodyReader io.Reader
-	if params.Body != nil {
-		bodyReader = bytes.NewBuffer(params.Body)
+	timeout, err := model.GetTimeoutParameter(c)
+	if err != nil {
+		return zero, localError("%+v", err)
 	}
 
-	if len(q) > 0 {
-		apiEndpoint += "?" + q.Encode()
+	if retryPolicy == nil {
+		retryPolicy = server.Retry
+	}
+	if retryPolicy == nil {
+		retryPolicy, err = model.GetRetryPolicy(c)
+		if err != nil {
+			return zero, localError("%+v", err)
+		}
 	}
 
-	req, err := http.NewRequestWithContext(reqCtx, params.Method, apiEndpoint, bodyReader)
+	client, err := workerclient.New(server.GetUrl(), server.GetAccessToken(),
+		workerclient.WithHTTPClient(cmp.Or(server.HTTPClient, http.DefaultClient)),
+		workerclient.WithRetryPolicy(retryPolicy),
+		workerclient.WithUserAgent(coreutils.GetCliUserAgent()),
+	)
 	if err != nil {
-		return apiError(http.StatusInternalServerError, "failed to create request: %+v", err)
+		return zero, localError("%+v", err)
 	}
 
-	req.Header.Add("Authorization", "Bearer "+strings.TrimSpace(params.ServerToken))
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("User-Agent", coreutils.GetCliUserAgent())
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
 
-	res, err := http.DefaultClient.Do(req)
+	result, err := call(ctx, client)
 	if err != nil {
-		if errors.Is(err, context.DeadlineExceeded) {
-			return apiError(http.StatusRequestTimeout, "request timed out after %s", timeout)
-		}
-		return apiError(http.StatusInternalServerError, "unexpected error: %+v", err)
+		return zero, toAPIError(err, timeout)
 	}
 
-	if slices.Index(params.OkStatuses, res.StatusCode) == -1 {
-		// We the response contains json content, we will print it
-		_ = processAPIResponse(res, printJSONOrLogError)
-		return apiError(res.StatusCode, "command %s %s returned an unexpected status code %d", params.Method, apiEndpoint, res.StatusCode)
-	}
+	return result, nil
+}
 
-	if params.CaptureStatus != nil {
-		*params.CaptureStatus = res.StatusCode
+func toAPIError(err error, timeout time.Duration) error {
+	var clientErr *workerclient.Error
+	if errors.As(err, &clientErr) {
+		if len(clientErr.Body) > 0 {
+			log.Debug(fmt.Sprintf("%s %s response: %s", clientErr.Method, clientErr.URL, string(clientErr.Body)))
+		}
+		apiErr := apiError(clientErr.StatusCode, "command %s %s returned an unexpected status code %d", clientErr.Method, clientErr.URL, clientErr.StatusCode)
+		apiErr.Code = clientErr.Code
+		apiErr.ServerMessage = clientErr.Message
+		if apiErr.ServerMessage == "" {
+			// The body is not an error document, it is shown as-is as it is the only explanation the server gave
+			apiErr.ServerMessage = serverResponseSummary(clientErr.Body)
+		}
+		apiErr.Details = clientErr.Details
+		apiErr.TraceID = clientErr.TraceID
+		apiErr.Hint = hintForStatus(clientErr.StatusCode)
+		return apiErr
 	}
 
-	return processAPIResponse(res, params.OnContent)
-}
-
-func processAPIResponse(res *http.Response, doWithContent func(content []byte) error) error {
-	var err error
-	var responseBytes []byte
+	if errors.Is(err, context.DeadlineExceeded) {
+		return apiError(http.StatusRequestTimeout, "request timed out after %s", timeout)
+	}
 
-	defer CloseQuietly(res.Body)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return err
+	}
 
-	if res.ContentLength == 0 {
-		_, _ = io.Copy(io.Discard, res.Body)
+	apiErr = apiError(http.StatusInternalServerError, "unexpected error: %+v", err)
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		apiErr.exitCode = ExitCodeNetworkError
 	} else {
-		responseBytes, err = io.ReadAll(res.Body)
-		if err != nil {
-			return fmt.Errorf("cannot read response content: %+v", err)
-		}
+		apiErr.exitCode = ExitCodeError
 	}
+	return apiErr
+}
 
-	if doWithContent == nil {
-		return nil
-	}
+// maxServerResponseSummary is the number of characters of a response body shown in an error message.
+const maxServerResponseSummary = 512
 
-	return doWithContent(responseBytes)
+func serverResponseSummary(body []byte) string {
+	summary := strings.Join(strings.Fields(string(body)), " ")
+	if len(summary) > maxServerResponseSummary {
+		summary = summary[:maxServerResponseSummary] + "..."
+	}
+	return summary
 }
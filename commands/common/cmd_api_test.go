@@ -6,6 +6,8 @@ package common
 import (
 	"encoding/json"
 	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -137,3 +139,134 @@ func TestCallWorkerAPI(t *testing.T) {
 		})
 	}
 }
+
+func TestCallWorkerAPI_Retry(t *testing.T) {
+	fastRetry := &model.RetryPolicy{
+		MaxAttempts:       3,
+		InitialBackoff:    time.Millisecond,
+		MaxBackoff:        5 * time.Millisecond,
+		RetryableStatuses: model.DefaultRetryableStatuses,
+	}
+
+	tests := []struct {
+		name         string
+		method       string
+		idempotent   bool
+		retry        *model.RetryPolicy
+		ctx          model.IntFlagProvider
+		statuses     []int
+		retryAfter   string
+		wantAttempts int32
+		wantErr      string
+	}{
+		{
+			name:         "retry GET until success",
+			method:       http.MethodGet,
+			retry:        fastRetry,
+			statuses:     []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK},
+			wantAttempts: 3,
+		},
+		{
+			name:         "retry DELETE",
+			method:       http.MethodDelete,
+			retry:        fastRetry,
+			statuses:     []int{http.StatusBadGateway, http.StatusOK},
+			wantAttempts: 2,
+		},
+		{
+			name:         "give up after max attempts",
+			method:       http.MethodPut,
+			retry:        fastRetry,
+			statuses:     []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusOK},
+			wantAttempts: 3,
+			wantErr:      "returned an unexpected status code 502",
+		},
+		{
+			name:         "do not retry POST by default",
+			method:       http.MethodPost,
+			retry:        fastRetry,
+			statuses:     []int{http.StatusBadGateway, http.StatusOK},
+			wantAttempts: 1,
+			wantErr:      "returned an unexpected status code 502",
+		},
+		{
+			name:         "retry POST marked as idempotent",
+			method:       http.MethodPost,
+			idempotent:   true,
+			retry:        fastRetry,
+			statuses:     []int{http.StatusBadGateway, http.StatusOK},
+			wantAttempts: 2,
+		},
+		{
+			name:         "do not retry non retryable status",
+			method:       http.MethodGet,
+			retry:        fastRetry,
+			statuses:     []int{http.StatusBadRequest, http.StatusOK},
+			wantAttempts: 1,
+			wantErr:      "returned an unexpected status code 400",
+		},
+		{
+			name:         "retries disabled from flags",
+			method:       http.MethodGet,
+			ctx:          IntFlagMap{model.FlagRetries: 0},
+			statuses:     []int{http.StatusBadGateway, http.StatusOK},
+			wantAttempts: 1,
+			wantErr:      "returned an unexpected status code 502",
+		},
+		{
+			name:         "honor Retry-After",
+			method:       http.MethodGet,
+			retry:        fastRetry,
+			retryAfter:   "1",
+			statuses:     []int{http.StatusTooManyRequests, http.StatusOK},
+			wantAttempts: 2,
+		},
+		{
+			name:         "stop when Retry-After exceeds the deadline",
+			method:       http.MethodGet,
+			retry:        fastRetry,
+			ctx:          IntFlagMap{model.FlagTimeout: 500},
+			retryAfter:   "2",
+			statuses:     []int{http.StatusTooManyRequests, http.StatusOK},
+			wantAttempts: 1,
+			wantErr:      "returned an unexpected status code 429",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				attempt := attempts.Add(1)
+				status := tt.statuses[min(int(attempt), len(tt.statuses))-1]
+				if tt.retryAfter != "" {
+					res.Header().Set("Retry-After", tt.retryAfter)
+				}
+				res.WriteHeader(status)
+			}))
+			t.Cleanup(server.Close)
+
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = IntFlagMap{}
+			}
+
+			err := CallWorkerAPI(ctx, APICallParams{
+				Method:     tt.method,
+				ServerURL:  server.URL,
+				Path:       []string{"workers"},
+				OkStatuses: []int{http.StatusOK},
+				Retry:      tt.retry,
+				Idempotent: tt.idempotent,
+			})
+
+			assert.Equal(t, tt.wantAttempts, attempts.Load())
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,17 +1,49 @@
 package common
 
 import (
+	"bufio"
 	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"reflect"
+	"slices"
+	"strconv"
 	"strings"
+	"text/tabwriter"
+	"text/template"
 
 	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
 
+	"github.com/jfrog/jfrog-cli-core/v2/common/format"
 	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+)
+
+// The output formats added to the json and table formats of the JFrog CLI.
+const (
+	// FormatCsv prints comma-separated values, where format.Table prints an aligned table
+	FormatCsv      format.OutputFormat = "csv"
+	FormatYaml     format.OutputFormat = "yaml"
+	FormatTemplate format.OutputFormat = "template"
+	// FormatAligned prints an aligned table, for the commands whose table format prints comma-separated values since before the csv format existed
+	FormatAligned format.OutputFormat = "aligned"
+	// FormatText prints the human-readable view of a result, which is not meant to be parsed
+	FormatText format.OutputFormat = "text"
+)
+
+var (
+	// OutputFormats are the formats of the commands printing lists or documents that can be shown as tables.
+	OutputFormats = []format.OutputFormat{format.Json, FormatYaml, FormatCsv, format.Table, FormatTemplate}
+	// DocumentFormats are the formats of the commands printing a document that is not tabular.
+	DocumentFormats = []format.OutputFormat{format.Json, FormatYaml, FormatTemplate}
+	// TextOutputFormats are the OutputFormats with the text format, for the commands having a human-readable view.
+	TextOutputFormats = append([]format.OutputFormat{FormatText}, OutputFormats...)
 )
 
 // Useful to capture output in tests
@@ -20,6 +52,72 @@ var (
 	cliIn  io.Reader = os.Stdin
 )
 
+// isInteractive tells whether a user can answer the prompts, the tests simulate one.
+var isInteractive = func() bool {
+	in, isFile := cliIn.(*os.File)
+	return isFile && term.IsTerminal(int(in.Fd()))
+}
+
+// IsInteractive tells whether the standard input is a terminal, i.e. whether Confirm can be used.
+func IsInteractive() bool {
+	return isInteractive()
+}
+
+// Color is the ANSI code of a text color.
+type Color string
+
+const (
+	ColorRed    Color = "31"
+	ColorGreen  Color = "32"
+	ColorYellow Color = "33"
+	ColorGray   Color = "90"
+)
+
+// isOutputTerminal tells whether the output is a terminal rather than a file or a pipe.
+func isOutputTerminal() bool {
+	out, isFile := cliOut.(*os.File)
+	return isFile && term.IsTerminal(int(out.Fd()))
+}
+
+// colorsEnabled tells whether the output is a terminal supporting colors, the tests force it.
+var colorsEnabled = func() bool {
+	return isOutputTerminal() && log.IsColorsSupported()
+}
+
+// ClearScreen clears the terminal and moves the cursor to its top, nothing is done when the output is not a terminal.
+func ClearScreen() error {
+	if !isOutputTerminal() {
+		return nil
+	}
+	return Print("\x1b[H\x1b[2J")
+}
+
+// Colorize returns the text in a color when the output is a terminal supporting colors (see NO_COLOR), else the text as is.
+func Colorize(text string, color Color) string {
+	if text == "" || !colorsEnabled() {
+		return text
+	}
+	return "\x1b[" + string(color) + "m" + text + "\x1b[0m"
+}
+
+// Confirm asks a yes/no question on the standard error, so that it does not pollute the output, and reads the answer from the standard input.
+// The default answer is no.
+func Confirm(question string) (bool, error) {
+	if !isInteractive() {
+		return false, errors.New("cannot ask for a confirmation, the input is not a terminal")
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "%s (y/n) [n]? ", question)
+
+	answer, err := bufio.NewReader(cliIn).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, fmt.Errorf("failed to read the answer: %w", err)
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
 type InputReader struct {
 	ctx *components.Context
 }
@@ -32,6 +130,21 @@ func NewCsvWriter() *csv.Writer {
 	return csv.NewWriter(cliOut)
 }
 
+// NewTableWriter returns a writer aligning the tab-separated cells in columns, it must be flushed.
+func NewTableWriter() *tabwriter.Writer {
+	return tabwriter.NewWriter(cliOut, 0, 0, 2, ' ', 0)
+}
+
+// WriteTableRow writes a row of cells to a table writer, the tabs and line breaks in the cells are replaced by spaces.
+func WriteTableRow(writer io.Writer, cells ...string) error {
+	sanitized := make([]string, len(cells))
+	for i, cell := range cells {
+		sanitized[i] = strings.Join(strings.Fields(cell), " ")
+	}
+	_, err := fmt.Fprintln(writer, strings.Join(sanitized, "\t"))
+	return err
+}
+
 func Print(message string, args ...any) error {
 	_, err := fmt.Fprintf(cliOut, message, args...)
 	return err
@@ -53,28 +166,371 @@ func PrintJSONValue(v any) error {
 	return err
 }
 
+// StatusContent is printed for the responses without a JSON body.
+type StatusContent struct {
+	StatusCode int    `json:"status_code"`
+	Content    string `json:"content"`
+}
+
 // PrintJSONOrStatus prints contentBytes as JSON when it is valid JSON,
 // otherwise prints {"status_code": statusCode, "content": "<contentBytes>"}.
 func PrintJSONOrStatus(statusCode int, contentBytes []byte) error {
 	if len(contentBytes) > 0 && json.Valid(contentBytes) {
 		return PrintJSON(contentBytes)
 	}
-	return PrintJSONValue(struct {
-		StatusCode int    `json:"status_code"`
-		Content    string `json:"content"`
-	}{
+	return PrintJSONValue(StatusContent{
 		StatusCode: statusCode,
 		Content:    string(contentBytes),
 	})
 }
 
-func printJSONOrLogError(data []byte) error {
-	if _, writeErr := cliOut.Write(PrettifyJSON(data)); writeErr != nil {
-		log.Warn(fmt.Sprintf("Write error: %+v (data:%s)", writeErr, string(data)))
+// Table is the tabular view of a result, printed with the csv and table formats.
+type Table struct {
+	Headers []string
+	Rows    [][]string
+	// NoCsvHeaders omits the headers with the csv format, for the outputs that scripts parse without them
+	NoCsvHeaders bool
+	// CsvAsTable prints the table format as comma-separated values, for the outputs that scripts parse, the aligned table is then printed with FormatAligned
+	CsvAsTable bool
+}
+
+// Result is what a command prints, the Output picks the view matching the format.
+type Result struct {
+	// Value is printed with the json and yaml formats, and is the input of --query.
+	// A json.RawMessage is printed as is when it is not valid JSON.
+	Value any
+	// Items are given one by one to --template, they default to the elements of Value when it is a slice, else to Value
+	Items any
+	// Table is the view of Value with the csv and table formats, a generic view of the JSON document is used when nil
+	Table *Table
+	// Text is the human-readable view printed with the text format, the value is printed as text when nil
+	Text func() error
+}
+
+// Output prints the results of a command according to --format, --template and --query.
+type Output struct {
+	Format   format.OutputFormat
+	template *template.Template
+	query    *Query
+}
+
+// NewOutput reads the output flags of a command. The format is format.None when the command has no default format and --format is not used,
+// unless --template or --query is used, which imply the template and json formats.
+func NewOutput(c *components.Context) (*Output, error) {
+	output := &Output{}
+
+	formatUsed := slices.Contains(c.FlagsUsed, format.FlagName)
+	if formatUsed || c.GetStringFlagValue(format.FlagName) != "" {
+		var err error
+		if output.Format, err = c.GetOutputFormat(); err != nil {
+			return nil, err
+		}
+	}
+
+	if source := c.GetStringFlagValue(model.FlagQuery); source != "" {
+		query, err := ParseQuery(source)
+		if err != nil {
+			return nil, err
+		}
+		output.query = query
+		if output.Format == format.None {
+			output.Format = format.Json
+		}
+	}
+
+	if text := c.GetStringFlagValue(model.FlagTemplate); text != "" {
+		if formatUsed && output.Format != FormatTemplate {
+			return nil, fmt.Errorf("--%s cannot be used with --%s %s", model.FlagTemplate, format.FlagName, output.Format)
+		}
+		tmpl, err := template.New("output").Funcs(templateFuncs).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --%s: %w", model.FlagTemplate, err)
+		}
+		output.template = tmpl
+		output.Format = FormatTemplate
+	} else if output.Format == FormatTemplate {
+		return nil, fmt.Errorf("--%s %s requires --%s", format.FlagName, FormatTemplate, model.FlagTemplate)
+	}
+
+	return output, nil
+}
+
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		content, err := json.Marshal(v)
+		return string(content), err
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"join": func(separator string, values []string) string {
+		return strings.Join(values, separator)
+	},
+}
+
+// Print prints a result, nothing is printed with format.None.
+func (o *Output) Print(result *Result) error {
+	if o.Format == format.None {
+		return nil
+	}
+
+	value, items, table := result.Value, result.Items, result.Table
+	if o.query != nil {
+		queried, err := o.query.Apply(value)
+		if err != nil {
+			return err
+		}
+		// The views of the command do not apply to the queried document
+		value, items, table = queried, nil, nil
+	}
+
+	switch o.Format {
+	case FormatYaml:
+		return printYAML(value)
+	case FormatTemplate:
+		if items == nil {
+			items = value
+		}
+		return o.printTemplate(items)
+	case FormatText:
+		if o.query == nil && result.Text != nil {
+			return result.Text()
+		}
+		return printText(value)
+	case FormatCsv, format.Table, FormatAligned:
+		if table == nil {
+			var err error
+			if table, err = newGenericTable(value); err != nil {
+				return err
+			}
+		}
+		if o.Format == FormatCsv || (o.Format == format.Table && table.CsvAsTable) {
+			return printCsvTable(table)
+		}
+		return printAlignedTable(table)
+	default:
+		if raw, isRaw := value.(json.RawMessage); isRaw {
+			return PrintJSON(raw)
+		}
+		return PrintJSONValue(value)
+	}
+}
+
+// LineFormats are the formats that PrintLine supports, the others need the whole result to print it.
+var LineFormats = []format.OutputFormat{format.Json, FormatText, FormatCsv, FormatTemplate}
+
+// PrintLine prints a result on a single line, for the commands streaming a result per input.
+// The json format prints compact JSON, i.e. JSON Lines, and the csv format prints the rows of the table without its headers.
+func (o *Output) PrintLine(result *Result) error {
+	if o.Format == format.None {
+		return nil
+	}
+
+	value, items, table := result.Value, result.Items, result.Table
+	if o.query != nil {
+		queried, err := o.query.Apply(value)
+		if err != nil {
+			return err
+		}
+		value, items, table = queried, nil, nil
+	}
+
+	switch o.Format {
+	case FormatTemplate:
+		if items == nil {
+			items = value
+		}
+		return o.printTemplate(items)
+	case FormatText:
+		if o.query == nil && result.Text != nil {
+			return result.Text()
+		}
+	case FormatCsv:
+		if table == nil {
+			var err error
+			if table, err = newGenericTable(value); err != nil {
+				return err
+			}
+		}
+		withoutHeaders := *table
+		withoutHeaders.NoCsvHeaders = true
+		return printCsvTable(&withoutHeaders)
+	case format.Json:
+	default:
+		return fmt.Errorf("the %s format cannot print a result per line, use one of %s", o.Format, format.Join(LineFormats))
+	}
+
+	document, err := toJSONDocument(value)
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(document)
+	if err != nil {
+		return err
+	}
+	return Print("%s\n", line)
+}
+
+func printYAML(value any) error {
+	document, err := toJSONDocument(value)
+	if err != nil {
+		return err
+	}
+	content, err := yaml.Marshal(document)
+	if err != nil {
+		return err
+	}
+	_, err = cliOut.Write(content)
+	return err
+}
+
+// printText prints a string as is and the other values as indented JSON.
+func printText(value any) error {
+	document, err := toJSONDocument(value)
+	if err != nil {
+		return err
+	}
+	if text, isString := document.(string); isString {
+		return Print("%s\n", text)
+	}
+	if err = PrintJSONValue(document); err != nil {
+		return err
+	}
+	return Print("\n")
+}
+
+func (o *Output) printTemplate(items any) error {
+	if raw, isRaw := items.(json.RawMessage); isRaw {
+		document, err := toJSONDocument(raw)
+		if err != nil {
+			return err
+		}
+		items = document
+	}
+
+	itemsValue := reflect.ValueOf(items)
+	if itemsValue.Kind() != reflect.Slice {
+		return o.executeTemplate(items)
+	}
+
+	for i := 0; i < itemsValue.Len(); i++ {
+		if err := o.executeTemplate(itemsValue.Index(i).Interface()); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+func (o *Output) executeTemplate(item any) error {
+	if err := o.template.Execute(cliOut, item); err != nil {
+		return fmt.Errorf("cannot print the template: %w", err)
+	}
+	_, err := fmt.Fprintln(cliOut)
+	return err
+}
+
+// newGenericTable shows a JSON document as a table: the arrays of objects with a column per field,
+// the other arrays with a single column, the objects with a row per field.
+func newGenericTable(value any) (*Table, error) {
+	document, err := toJSONDocument(value)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := document.(type) {
+	case []any:
+		var fields []string
+		for _, element := range v {
+			object, isObject := element.(map[string]any)
+			if !isObject {
+				fields = nil
+				break
+			}
+			for field := range object {
+				if !slices.Contains(fields, field) {
+					fields = append(fields, field)
+				}
+			}
+		}
+
+		if fields == nil {
+			table := &Table{Headers: []string{"VALUE"}, NoCsvHeaders: true}
+			for _, element := range v {
+				table.Rows = append(table.Rows, []string{FormatCell(element)})
+			}
+			return table, nil
+		}
+
+		slices.Sort(fields)
+		table := &Table{Headers: fields}
+		for _, element := range v {
+			object := element.(map[string]any)
+			row := make([]string, len(fields))
+			for i, field := range fields {
+				row[i] = FormatCell(object[field])
+			}
+			table.Rows = append(table.Rows, row)
+		}
+		return table, nil
+	case map[string]any:
+		table := &Table{Headers: []string{"KEY", "VALUE"}, NoCsvHeaders: true}
+		for _, key := range sortedKeys(v) {
+			table.Rows = append(table.Rows, []string{key, FormatCell(v[key])})
+		}
+		return table, nil
+	default:
+		return &Table{Headers: []string{"VALUE"}, NoCsvHeaders: true, Rows: [][]string{{FormatCell(v)}}}, nil
+	}
+}
+
+// FormatCell formats a JSON value for a table: the scalars as is, null as an empty cell, the arrays and objects as compact JSON.
+func FormatCell(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		content, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(content)
+	}
+}
+
+func printCsvTable(table *Table) error {
+	writer := NewCsvWriter()
+	if !table.NoCsvHeaders {
+		if err := writer.Write(table.Headers); err != nil {
+			return err
+		}
+	}
+	for _, row := range table.Rows {
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func printAlignedTable(table *Table) error {
+	writer := NewTableWriter()
+	if err := WriteTableRow(writer, table.Headers...); err != nil {
+		return err
+	}
+	for _, row := range table.Rows {
+		if err := WriteTableRow(writer, row...); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
 func (c *InputReader) ReadData() (map[string]any, error) {
 	if len(c.ctx.Arguments) == 0 {
 		return nil, fmt.Errorf("missing json payload argument")
@@ -94,6 +550,18 @@ func (c *InputReader) ReadData() (map[string]any, error) {
 	return c.unmarshalData([]byte(jsonPayload))
 }
 
+// Open opens a file read as a stream, "-" is the standard input.
+func (c *InputReader) Open(filePath string) (io.ReadCloser, error) {
+	if filePath == "-" {
+		return io.NopCloser(cliIn), nil
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", filePath, err)
+	}
+	return file, nil
+}
+
 func (c *InputReader) ReadDataFromStdin() (map[string]any, error) {
 	data := map[string]any{}
 
@@ -0,0 +1,230 @@
+//go:build test
+// +build test
+
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+	"text/template"
+
+	"github.com/jfrog/jfrog-cli-core/v2/common/format"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type outputTestItem struct {
+	Key     string `json:"key"`
+	Enabled bool   `json:"enabled"`
+}
+
+func TestOutput_Print(t *testing.T) {
+	items := []*outputTestItem{{Key: "wk-1", Enabled: true}, {Key: "wk-2"}}
+
+	tests := []struct {
+		name       string
+		format     format.OutputFormat
+		template   string
+		query      string
+		result     *Result
+		wantOutput string
+	}{
+		{
+			name:       "none",
+			format:     format.None,
+			result:     &Result{Value: items},
+			wantOutput: "",
+		},
+		{
+			name:       "json",
+			format:     format.Json,
+			result:     &Result{Value: items[0]},
+			wantOutput: "{\n  \"key\": \"wk-1\",\n  \"enabled\": true\n}",
+		},
+		{
+			name:       "raw json",
+			format:     format.Json,
+			result:     &Result{Value: json.RawMessage(`{"b":1,"a":[1]}`)},
+			wantOutput: "{\n  \"b\": 1,\n  \"a\": [\n    1\n  ]\n}",
+		},
+		{
+			name:       "yaml",
+			format:     FormatYaml,
+			result:     &Result{Value: items},
+			wantOutput: "- enabled: true\n  key: wk-1\n- enabled: false\n  key: wk-2\n",
+		},
+		{
+			name:       "template per item",
+			format:     FormatTemplate,
+			template:   "{{.Key}} {{.Enabled}}",
+			result:     &Result{Value: map[string]any{"workers": items}, Items: items},
+			wantOutput: "wk-1 true\nwk-2 false\n",
+		},
+		{
+			name:       "template on a raw document",
+			format:     FormatTemplate,
+			template:   "{{.status}} {{json .data}}",
+			result:     &Result{Value: json.RawMessage(`{"status":"OK","data":{"a":1}}`)},
+			wantOutput: "OK {\"a\":1}\n",
+		},
+		{
+			name:       "csv",
+			format:     FormatCsv,
+			result:     &Result{Value: items, Table: &Table{Headers: []string{"KEY"}, Rows: [][]string{{"wk-1"}, {"wk-2"}}}},
+			wantOutput: "KEY\nwk-1\nwk-2\n",
+		},
+		{
+			name:       "csv without headers",
+			format:     FormatCsv,
+			result:     &Result{Value: items, Table: &Table{Headers: []string{"KEY"}, Rows: [][]string{{"wk-1"}}, NoCsvHeaders: true}},
+			wantOutput: "wk-1\n",
+		},
+		{
+			name:       "aligned table",
+			format:     format.Table,
+			result:     &Result{Value: items, Table: &Table{Headers: []string{"KEY", "ENABLED"}, Rows: [][]string{{"worker-1", "true"}, {"wk-2", "false"}}}},
+			wantOutput: "KEY       ENABLED\nworker-1  true\nwk-2      false\n",
+		},
+		{
+			name:       "text view",
+			format:     FormatText,
+			result:     &Result{Value: items, Text: func() error { return Print("custom\n") }},
+			wantOutput: "custom\n",
+		},
+		{
+			name:       "text of a queried string",
+			format:     FormatText,
+			query:      ".[0].key",
+			result:     &Result{Value: items, Text: func() error { return Print("custom\n") }},
+			wantOutput: "wk-1\n",
+		},
+		{
+			name:       "generic table of objects",
+			format:     format.Table,
+			result:     &Result{Value: items},
+			wantOutput: "enabled  key\ntrue     wk-1\nfalse    wk-2\n",
+		},
+		{
+			name:       "generic csv of an object",
+			format:     FormatCsv,
+			result:     &Result{Value: json.RawMessage(`{"status":"OK","data":{"a":1},"count":2.5}`)},
+			wantOutput: "count,2.5\ndata,\"{\"\"a\"\":1}\"\nstatus,OK\n",
+		},
+		{
+			name:       "query",
+			format:     format.Json,
+			query:      "[.[] | select(.enabled) | .key]",
+			result:     &Result{Value: items},
+			wantOutput: "[\n  \"wk-1\"\n]",
+		},
+		{
+			name:       "query replaces the views",
+			format:     FormatCsv,
+			query:      ".[].key",
+			result:     &Result{Value: items, Table: &Table{Headers: []string{"KEY", "ENABLED"}}, Text: func() error { return Print("custom\n") }},
+			wantOutput: "wk-1\nwk-2\n",
+		},
+		{
+			name:       "query and template",
+			format:     FormatTemplate,
+			template:   "{{.key}}",
+			query:      ".[] | select(.enabled == false)",
+			result:     &Result{Value: items, Items: []string{"ignored"}},
+			wantOutput: "wk-2\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			SetCliOut(&out)
+			t.Cleanup(func() { SetCliOut(os.Stdout) })
+
+			output := &Output{Format: tt.format}
+			if tt.template != "" {
+				output.template = template.Must(template.New("test").Funcs(templateFuncs).Parse(tt.template))
+			}
+			if tt.query != "" {
+				var err error
+				output.query, err = ParseQuery(tt.query)
+				require.NoError(t, err)
+			}
+
+			require.NoError(t, output.Print(tt.result))
+			assert.Equal(t, tt.wantOutput, out.String())
+		})
+	}
+}
+
+func TestOutput_PrintLine(t *testing.T) {
+	item := &outputTestItem{Key: "wk-1", Enabled: true}
+	table := &Table{Headers: []string{"KEY", "ENABLED"}, Rows: [][]string{{"wk-1", "true"}}}
+
+	tests := []struct {
+		name       string
+		format     format.OutputFormat
+		query      string
+		result     *Result
+		wantOutput string
+		wantErr    string
+	}{
+		{
+			name:       "json line",
+			format:     format.Json,
+			result:     &Result{Value: item},
+			wantOutput: "{\"enabled\":true,\"key\":\"wk-1\"}\n",
+		},
+		{
+			name:       "queried json line",
+			format:     format.Json,
+			query:      ".key",
+			result:     &Result{Value: item},
+			wantOutput: "\"wk-1\"\n",
+		},
+		{
+			name:       "csv rows without headers",
+			format:     FormatCsv,
+			result:     &Result{Value: item, Table: table},
+			wantOutput: "wk-1,true\n",
+		},
+		{
+			name:   "text view",
+			format: FormatText,
+			result: &Result{Value: item, Text: func() error {
+				return Print("wk-1 enabled\n")
+			}},
+			wantOutput: "wk-1 enabled\n",
+		},
+		{
+			name:    "table not supported",
+			format:  format.Table,
+			result:  &Result{Value: item},
+			wantErr: "the table format cannot print a result per line, use one of json, text, csv, template",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			SetCliOut(&out)
+			t.Cleanup(func() { SetCliOut(os.Stdout) })
+
+			output := &Output{Format: tt.format}
+			if tt.query != "" {
+				var err error
+				output.query, err = ParseQuery(tt.query)
+				require.NoError(t, err)
+			}
+
+			err := output.PrintLine(tt.result)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOutput, out.String())
+		})
+	}
+}
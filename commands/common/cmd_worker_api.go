@@ -1,89 +1,94 @@
 package common
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 
 	"github.com/jfrog/jfrog-cli-platform-services/model"
+	"github.com/jfrog/jfrog-cli-platform-services/workerclient"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 )
 
 // FetchWorkerDetails Fetch a worker by its name. Returns nil if the worker does not exist (statusCode=404). Any other statusCode other than 200 will result as an error.
-func FetchWorkerDetails(c model.IntFlagProvider, serverURL string, accessToken string, workerKey string, projectKey string) (*model.WorkerDetails, error) {
-	details := new(model.WorkerDetails)
-	err := CallWorkerAPI(c, APICallParams{
-		Method:      http.MethodGet,
-		ServerURL:   serverURL,
-		ServerToken: accessToken,
-		OkStatuses:  []int{http.StatusOK, http.StatusNotFound},
-		ProjectKey:  projectKey,
-		Path:        []string{"workers", workerKey},
-		OnContent: func(content []byte) error {
-			if len(content) == 0 {
-				return nil
-			}
-			log.Info(fmt.Sprintf("Worker %s details returned from the server", details.Key))
-			return json.Unmarshal(content, details)
-		},
+func FetchWorkerDetails(c model.IntFlagProvider, server *Server, workerKey string, projectKey string) (*model.WorkerDetails, error) {
+	details, err := CallWorkerClient(c, server, func(ctx context.Context, client *workerclient.Client) (*model.WorkerDetails, error) {
+		details, err := client.GetWorker(ctx, workerKey, projectKey)
+		if workerclient.IsNotFound(err) {
+			return nil, nil
+		}
+		return details, err
 	})
 	if err != nil {
 		return nil, fmt.Errorf("cannot fetch worker details: %w", err)
 	}
 
-	if details.Key == "" {
+	if details == nil || details.Key == "" {
 		log.Info(fmt.Sprintf("Worker %s does not exist", workerKey))
 		return nil, nil
 	}
+
+	log.Info(fmt.Sprintf("Worker %s details returned from the server", details.Key))
+
 	return details, nil
 }
 
-func FetchActions(c model.IntFlagProvider, serverURL string, accessToken string, projectKey string) (ActionsMetadata, error) {
-	metadata := make(ActionsMetadata, 0)
+func FetchActions(c model.IntFlagProvider, server *Server, projectKey string) (ActionsMetadata, error) {
+	if server.Metadata.ActionsFile != "" {
+		return LoadActionsFile(server.Metadata.ActionsFile)
+	}
+
+	cacheName := "actions"
+	if projectKey != "" {
+		cacheName += "-" + projectKey
+	}
 
-	err := CallWorkerAPI(c, APICallParams{
-		Method:      http.MethodGet,
-		ServerURL:   serverURL,
-		ServerToken: accessToken,
-		OkStatuses:  []int{http.StatusOK},
-		ProjectKey:  projectKey,
-		APIVersion:  APIVersionV2,
-		Path:        []string{"actions"},
-		OnContent: func(content []byte) error {
-			if len(content) == 0 {
-				log.Debug("No actions returned from the server")
-				return nil
-			}
-			return json.Unmarshal(content, &metadata)
-		},
+	content, err := fetchCachedMetadata(c, server, cacheName, &workerclient.Request{
+		Method:     http.MethodGet,
+		Path:       []string{"actions"},
+		ProjectKey: projectKey,
+		APIVersion: APIVersionV2,
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	metadata := make(ActionsMetadata, 0)
+	if len(content) == 0 {
+		log.Debug("No actions returned from the server")
+		return metadata, nil
+	}
+
+	if err = json.Unmarshal(content, &metadata); err != nil {
+		return nil, err
+	}
+
 	return metadata, nil
 }
 
-func FetchOptions(c model.IntFlagProvider, serverURL string, accessToken string) (*OptionsMetadata, error) {
-	metadata := new(OptionsMetadata)
-
-	err := CallWorkerAPI(c, APICallParams{
-		Method:      http.MethodGet,
-		ServerURL:   serverURL,
-		ServerToken: accessToken,
-		OkStatuses:  []int{http.StatusOK},
-		APIVersion:  APIVersionV1,
-		Path:        []string{"options"},
-		OnContent: func(content []byte) error {
-			if len(content) == 0 {
-				log.Debug("No options returned from the server")
-				return nil
-			}
-			return json.Unmarshal(content, &metadata)
-		},
+func FetchOptions(c model.IntFlagProvider, server *Server) (*model.OptionsMetadata, error) {
+	if server.Metadata.OptionsFile != "" {
+		return LoadOptionsFile(server.Metadata.OptionsFile)
+	}
+
+	content, err := fetchCachedMetadata(c, server, "options", &workerclient.Request{
+		Method: http.MethodGet,
+		Path:   []string{"options"},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("cannot fetch options: %w", err)
 	}
+
+	metadata := new(model.OptionsMetadata)
+	if len(content) == 0 {
+		log.Debug("No options returned from the server")
+		return metadata, nil
+	}
+
+	if err = json.Unmarshal(content, metadata); err != nil {
+		return nil, fmt.Errorf("cannot fetch options: %w", err)
+	}
+
 	return metadata, nil
 }
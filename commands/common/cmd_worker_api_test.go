@@ -52,7 +52,7 @@ func TestFetchWorkerDetails(t *testing.T) {
 				tt.ctx = IntFlagMap{}
 			}
 
-			got, err := FetchWorkerDetails(tt.ctx, s.BaseUrl(), token, tt.workerKey, tt.projectKey)
+			got, err := FetchWorkerDetails(tt.ctx, TestServer(s.BaseUrl(), token), tt.workerKey, tt.projectKey)
 
 			if tt.wantErr != "" {
 				require.Error(t, err)
@@ -92,7 +92,7 @@ func TestFetchActions(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			s, token := NewMockWorkerServer(t, tt.stub.WithT(t))
 
-			got, err := FetchActions(IntFlagMap{}, s.BaseUrl(), token, tt.projectKey)
+			got, err := FetchActions(IntFlagMap{}, TestServer(s.BaseUrl(), token), tt.projectKey)
 
 			if tt.wantErr != "" {
 				require.Error(t, err)
@@ -109,7 +109,7 @@ func TestFetchActions(t *testing.T) {
 func TestFetchOptions(t *testing.T) {
 	samples := LoadSampleOptions(t)
 	s, token := NewMockWorkerServer(t, NewServerStub(t).WithOptionsEndpoint().WithT(t))
-	got, err := FetchOptions(IntFlagMap{}, s.BaseUrl(), token)
+	got, err := FetchOptions(IntFlagMap{}, TestServer(s.BaseUrl(), token))
 	require.NoError(t, err)
 	assert.Equal(t, got, samples)
 }
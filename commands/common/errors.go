@@ -1,12 +1,234 @@
 package common
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/jfrog/jfrog-cli-core/v2/common/format"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+)
+
+// The exit codes of the worker commands, by class of error.
+// The codes 2 and 3 are reserved by the JFrog CLI.
+const (
+	// ExitCodeError Any error that does not fall in another class, e.g. an invalid manifest.
+	ExitCodeError = 1
+	// ExitCodeUnauthorized The server rejected the credentials (401).
+	ExitCodeUnauthorized = 10
+	// ExitCodeForbidden The credentials do not grant the permission required by the command (403).
+	ExitCodeForbidden = 11
+	// ExitCodeNotFound The worker, or the project, does not exist (404).
+	ExitCodeNotFound = 12
+	// ExitCodeConflict The worker conflicts with an existing one (409).
+	ExitCodeConflict = 13
+	// ExitCodeInvalidRequest The server rejected the request content (400, 422 and the other 4xx statuses).
+	ExitCodeInvalidRequest = 14
+	// ExitCodeTimeout The request did not complete before the timeout.
+	ExitCodeTimeout = 15
+	// ExitCodeServerError The server failed to process the request (5xx, 429).
+	ExitCodeServerError = 16
+	// ExitCodeNetworkError The server could not be reached.
+	ExitCodeNetworkError = 17
+	// ExitCodeUnsupported The server does not support a feature required by the command, e.g. it is too old.
+	ExitCodeUnsupported = 18
 )
 
+type APIError struct {
+	StatusCode int
+	Message    string
+	// The error code sent by the server, if any
+	Code string
+	// The error message sent by the server, if any
+	ServerMessage string
+	Details       []string
+	TraceID       string
+	// An advice on how to solve the error
+	Hint string
+	// Overrides the exit code derived from the status, for the errors that do not come from the server
+	exitCode int
+}
+
+func (e *APIError) Error() string {
+	var sb strings.Builder
+	sb.WriteString(e.Message)
+	if e.ServerMessage != "" {
+		sb.WriteString(": ")
+		sb.WriteString(e.ServerMessage)
+	}
+	if e.TraceID != "" {
+		sb.WriteString(fmt.Sprintf(" (trace ID: %s)", e.TraceID))
+	}
+	for _, detail := range e.Details {
+		sb.WriteString("\n  - ")
+		sb.WriteString(detail)
+	}
+	if e.Hint != "" {
+		sb.WriteString("\nHint: ")
+		sb.WriteString(e.Hint)
+	}
+	return sb.String()
+}
+
+// ExitStatus returns the process exit code matching the class of the error.
+func (e *APIError) ExitStatus() int {
+	switch {
+	case e.exitCode != 0:
+		return e.exitCode
+	case e.StatusCode == http.StatusRequestTimeout:
+		return ExitCodeTimeout
+	case e.StatusCode == http.StatusUnauthorized:
+		return ExitCodeUnauthorized
+	case e.StatusCode == http.StatusForbidden:
+		return ExitCodeForbidden
+	case e.StatusCode == http.StatusNotFound:
+		return ExitCodeNotFound
+	case e.StatusCode == http.StatusConflict:
+		return ExitCodeConflict
+	case e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500:
+		return ExitCodeServerError
+	case e.StatusCode >= 400:
+		return ExitCodeInvalidRequest
+	default:
+		return ExitCodeError
+	}
+}
+
+// As lets the JFrog CLI find the exit code of the error, as it looks for a coreutils.CliError in the error chain.
+func (e *APIError) As(target any) bool {
+	if cliErr, isCliErr := target.(*coreutils.CliError); isCliErr {
+		*cliErr = coreutils.CliError{ExitCode: coreutils.ExitCode{Code: e.ExitStatus()}, ErrorMsg: e.Error()}
+		return true
+	}
+	return false
+}
+
+func apiError(status int, message string, args ...any) *APIError {
+	return &APIError{
+		StatusCode: status,
+		Message:    fmt.Sprintf(message, args...),
+	}
+}
+
+// localError denotes an error raised before the request was sent, e.g. an invalid flag.
+func localError(message string, args ...any) *APIError {
+	err := apiError(http.StatusInternalServerError, message, args...)
+	err.exitCode = ExitCodeError
+	return err
+}
+
+// TimeoutError denotes an operation that did not complete in time, e.g. a wait for the end of an execution, it exits with ExitCodeTimeout.
+func TimeoutError(message string, args ...any) error {
+	return apiError(http.StatusRequestTimeout, message, args...)
+}
+
+func hintForStatus(status int) string {
+	switch status {
+	case http.StatusUnauthorized:
+		return "The access token was rejected, re-run 'jf login' (or 'jf c add') to refresh the credentials of the server."
+	case http.StatusForbidden:
+		return "The access token does not have the permission required by this command. If the worker belongs to a project, make sure you have the permission to manage workers in that project."
+	case http.StatusNotFound:
+		return "The worker does not exist. If it belongs to a project, make sure the right project key is provided."
+	case http.StatusConflict:
+		return "A worker with this key already exists, choose another key or update the existing worker with 'jf worker deploy'."
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return fmt.Sprintf("The server is temporarily unavailable, try again later or increase the number of retries with --%s.", model.FlagRetries)
+	default:
+		return ""
+	}
+}
+
+// ExitCodeOf returns the process exit code matching the class of err.
+func ExitCodeOf(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ExitStatus()
+	}
+	return ExitCodeError
+}
+
+type errorOutput struct {
+	Message    string   `json:"message"`
+	ExitCode   int      `json:"exit_code"`
+	StatusCode int      `json:"status_code,omitempty"`
+	Code       string   `json:"code,omitempty"`
+	Details    []string `json:"details,omitempty"`
+	TraceID    string   `json:"trace_id,omitempty"`
+	Hint       string   `json:"hint,omitempty"`
+}
+
+// PrintErrorAsJSON writes {"error": {...}} to the CLI output, with the details of the server error when err is an *APIError.
+func PrintErrorAsJSON(err error) error {
+	out := errorOutput{Message: err.Error(), ExitCode: ExitCodeError}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		out.Message = apiErr.Message
+		if apiErr.ServerMessage != "" {
+			out.Message += ": " + apiErr.ServerMessage
+		}
+		out.ExitCode = apiErr.ExitStatus()
+		out.StatusCode = apiErr.StatusCode
+		out.Code = apiErr.Code
+		out.Details = apiErr.Details
+		out.TraceID = apiErr.TraceID
+		out.Hint = apiErr.Hint
+	}
+
+	return PrintJSONValue(map[string]any{"error": out})
+}
+
+// reportedError is an error whose details were already printed by the command.
+type reportedError struct {
+	error
+}
+
+func (e *reportedError) Unwrap() error {
+	return e.error
+}
+
+// ErrorAlreadyReported marks err as already part of the command output, so that WithErrorOutput does not print it again.
+func ErrorAlreadyReported(err error) error {
+	return &reportedError{err}
+}
+
+// WithErrorOutput makes the commands print their errors as JSON when they are run with '--format json'.
+// The error is still returned, so that the process exits with the code of the error.
+func WithErrorOutput(cmds ...components.Command) []components.Command {
+	for i, cmd := range cmds {
+		action := cmd.Action
+		if action == nil {
+			continue
+		}
+		cmds[i].Action = func(c *components.Context) error {
+			err := action(c)
+			var reported *reportedError
+			if err != nil && !errors.As(err, &reported) && isJSONFormatRequested(c) {
+				if printErr := PrintErrorAsJSON(err); printErr != nil {
+					log.Warn(fmt.Sprintf("Cannot print the error: %+v", printErr))
+				}
+			}
+			return err
+		}
+	}
+	return cmds
+}
+
+func isJSONFormatRequested(c *components.Context) bool {
+	if !slices.Contains(c.FlagsUsed, format.FlagName) {
+		return false
+	}
+	outputFormat, err := c.GetOutputFormat()
+	return err == nil && outputFormat == format.Json
+}
+
 func ErrUnsupportedFormat(format format.OutputFormat, supportedFormats ...format.OutputFormat) error {
 	supportedFormatsStr := make([]string, len(supportedFormats))
 	for i, f := range supportedFormats {
@@ -0,0 +1,202 @@
+//go:build test
+// +build test
+
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/common/format"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+)
+
+func TestCallWorkerAPI_StructuredErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		wantCode     string
+		wantMessage  string
+		wantDetails  []string
+		wantTraceID  string
+		wantHint     string
+		wantExitCode int
+	}{
+		{
+			name:         "unauthorized",
+			status:       http.StatusUnauthorized,
+			body:         `{"errors":[{"code":"UNAUTHORIZED","message":"Bad credentials"}]}`,
+			wantCode:     "UNAUTHORIZED",
+			wantMessage:  "Bad credentials",
+			wantHint:     "jf login",
+			wantExitCode: ExitCodeUnauthorized,
+		},
+		{
+			name:         "forbidden",
+			status:       http.StatusForbidden,
+			body:         `{"code":"FORBIDDEN","message":"Missing permission","traceId":"abc123"}`,
+			wantCode:     "FORBIDDEN",
+			wantMessage:  "Missing permission",
+			wantTraceID:  "abc123",
+			wantHint:     "permission to manage workers in that project",
+			wantExitCode: ExitCodeForbidden,
+		},
+		{
+			name:         "conflict",
+			status:       http.StatusConflict,
+			body:         `{"message":"Worker already exists","details":["key: wk-1"]}`,
+			wantMessage:  "Worker already exists",
+			wantDetails:  []string{"key: wk-1"},
+			wantHint:     "already exists",
+			wantExitCode: ExitCodeConflict,
+		},
+		{
+			name:         "bad request",
+			status:       http.StatusBadRequest,
+			body:         `{"error":"Bad Request","message":"Invalid cron"}`,
+			wantCode:     "Bad Request",
+			wantMessage:  "Invalid cron",
+			wantExitCode: ExitCodeInvalidRequest,
+		},
+		{
+			name:         "server error without body",
+			status:       http.StatusInternalServerError,
+			wantExitCode: ExitCodeServerError,
+		},
+		{
+			name:         "json body without message",
+			status:       http.StatusUnprocessableEntity,
+			body:         "{\n  \"reason\": \"invalid action\"\n}",
+			wantMessage:  `{ "reason": "invalid action" }`,
+			wantExitCode: ExitCodeInvalidRequest,
+		},
+		{
+			name:         "non json body",
+			status:       http.StatusNotFound,
+			body:         `<html>Not found</html>`,
+			wantMessage:  "<html>Not found</html>",
+			wantHint:     "project key",
+			wantExitCode: ExitCodeNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				res.WriteHeader(tt.status)
+				_, _ = res.Write([]byte(tt.body))
+			}))
+			t.Cleanup(server.Close)
+
+			err := CallWorkerAPI(IntFlagMap{model.FlagRetries: 0}, APICallParams{
+				Method:     http.MethodGet,
+				ServerURL:  server.URL,
+				Path:       []string{"workers"},
+				OkStatuses: []int{http.StatusOK},
+			})
+			require.Error(t, err)
+
+			var apiErr *APIError
+			require.True(t, errors.As(err, &apiErr))
+
+			assert.Equal(t, tt.status, apiErr.StatusCode)
+			assert.Equal(t, tt.wantCode, apiErr.Code)
+			assert.Equal(t, tt.wantMessage, apiErr.ServerMessage)
+			assert.Equal(t, tt.wantDetails, apiErr.Details)
+			assert.Equal(t, tt.wantTraceID, apiErr.TraceID)
+			assert.Contains(t, apiErr.Hint, tt.wantHint)
+			assert.Contains(t, err.Error(), fmt.Sprintf("returned an unexpected status code %d", tt.status))
+			if tt.wantMessage != "" {
+				assert.Contains(t, err.Error(), tt.wantMessage)
+			}
+
+			// The JFrog CLI finds the exit code through errors.As
+			var cliErr coreutils.CliError
+			require.True(t, errors.As(fmt.Errorf("wrapped: %w", err), &cliErr))
+			assert.Equal(t, tt.wantExitCode, cliErr.ExitCode.Code)
+			assert.Equal(t, tt.wantExitCode, ExitCodeOf(err))
+		})
+	}
+}
+
+func TestCallWorkerAPI_ErrorExitCodes(t *testing.T) {
+	err := CallWorkerAPI(IntFlagMap{model.FlagRetries: -1}, APICallParams{Method: http.MethodGet, ServerURL: "http://localhost"})
+	assert.Equal(t, ExitCodeError, ExitCodeOf(err))
+
+	err = CallWorkerAPI(IntFlagMap{model.FlagRetries: 0}, APICallParams{Method: http.MethodGet, ServerURL: "http://127.0.0.1:1", OkStatuses: []int{http.StatusOK}})
+	assert.Equal(t, ExitCodeNetworkError, ExitCodeOf(err))
+
+	assert.Equal(t, ExitCodeTimeout, ExitCodeOf(apiError(http.StatusRequestTimeout, "request timed out")))
+	assert.Equal(t, ExitCodeError, ExitCodeOf(errors.New("invalid manifest")))
+}
+
+func TestWithErrorOutput(t *testing.T) {
+	failure := &APIError{
+		StatusCode:    http.StatusConflict,
+		Message:       "command POST http://localhost/worker/api/v2/workers returned an unexpected status code 409",
+		Code:          "CONFLICT",
+		ServerMessage: "Worker already exists",
+		TraceID:       "abc123",
+		Hint:          hintForStatus(http.StatusConflict),
+	}
+
+	cmds := WithErrorOutput(components.Command{
+		Name:             "fail",
+		SupportedFormats: []format.OutputFormat{format.Json, format.Table},
+		DefaultFormat:    format.Json,
+		Action: func(c *components.Context) error {
+			return failure
+		},
+	})
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantJSON bool
+	}{
+		{name: "json format", args: []string{"--" + format.FlagName, "json"}, wantJSON: true},
+		{name: "default format", args: nil},
+		{name: "table format", args: []string{"--" + format.FlagName, "table"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			SetCliOut(&out)
+			t.Cleanup(func() { SetCliOut(os.Stdout) })
+
+			runCmd := CreateCliRunner(t, cmds...)
+
+			err := runCmd(append([]string{"worker", "fail"}, tt.args...)...)
+			require.ErrorIs(t, err, failure)
+
+			if !tt.wantJSON {
+				assert.Empty(t, out.String())
+				return
+			}
+
+			var got map[string]errorOutput
+			require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+			assert.Equal(t, errorOutput{
+				Message:    failure.Message + ": Worker already exists",
+				ExitCode:   ExitCodeConflict,
+				StatusCode: http.StatusConflict,
+				Code:       "CONFLICT",
+				TraceID:    "abc123",
+				Hint:       failure.Hint,
+			}, got["error"])
+		})
+	}
+}
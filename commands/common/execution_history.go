@@ -0,0 +1,211 @@
+package common
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/utils/log"
+
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+	"github.com/jfrog/jfrog-cli-platform-services/workerclient"
+)
+
+const (
+	// ExecutionHistoryPageSize is the number of entries requested at once from the servers paginating the history
+	ExecutionHistoryPageSize = 100
+	// The number of workers of which the history is fetched at the same time
+	executionHistoryConcurrency = 4
+	executionStatusPrefix       = "STATUS_"
+)
+
+// ExecutionHistoryFilter selects the entries of an execution history, the empty criteria match every entry.
+type ExecutionHistoryFilter struct {
+	// Statuses are matched ignoring the case and the STATUS_ prefix, e.g. fail matches STATUS_FAIL
+	Statuses []string
+	// Since and Until bound the start time of the executions, Until excluded
+	Since       time.Time
+	Until       time.Time
+	Version     string
+	TriggeredBy string
+}
+
+// Matches tells whether an entry meets all the criteria of the filter.
+func (f *ExecutionHistoryFilter) Matches(entry *model.ExecutionHistoryEntry) bool {
+	if len(f.Statuses) > 0 && !slices.ContainsFunc(f.Statuses, func(status string) bool { return sameExecutionStatus(status, entry.ExecutionStatus) }) {
+		return false
+	}
+	startedAt := time.UnixMilli(entry.StartTimeMillis)
+	if !f.Since.IsZero() && startedAt.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !startedAt.Before(f.Until) {
+		return false
+	}
+	if f.Version != "" && entry.ExecutedVersion != f.Version {
+		return false
+	}
+	return f.TriggeredBy == "" || strings.EqualFold(entry.TriggeredBy, f.TriggeredBy)
+}
+
+// Apply returns the entries matching the filter, in their order.
+func (f *ExecutionHistoryFilter) Apply(entries []*model.ExecutionHistoryEntry) []*model.ExecutionHistoryEntry {
+	matching := make([]*model.ExecutionHistoryEntry, 0, len(entries))
+	for _, entry := range entries {
+		if f.Matches(entry) {
+			matching = append(matching, entry)
+		}
+	}
+	return matching
+}
+
+// serverStatuses returns the statuses with their STATUS_ prefix, as the servers filtering the history by status expect them.
+func (f *ExecutionHistoryFilter) serverStatuses() []string {
+	var statuses []string
+	for _, status := range f.Statuses {
+		status = strings.ToUpper(strings.TrimSpace(status))
+		if !strings.HasPrefix(status, executionStatusPrefix) {
+			status = executionStatusPrefix + status
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+func sameExecutionStatus(expected string, actual string) bool {
+	expected, actual = strings.ToUpper(strings.TrimSpace(expected)), strings.ToUpper(actual)
+	return expected == actual || executionStatusPrefix+expected == actual
+}
+
+// ParseHistoryTime reads a time given as RFC 3339 (2024-01-15T10:00:00Z), as a date in UTC (2024-01-15),
+// or as a duration before now, e.g. 30m, 12h or 7d.
+func ParseHistoryTime(value string, now time.Time) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	if parsed, err := time.Parse(time.DateOnly, value); err == nil {
+		return parsed, nil
+	}
+	if days, isDays := strings.CutSuffix(value, "d"); isDays {
+		if count, err := strconv.Atoi(days); err == nil && count >= 0 {
+			return now.AddDate(0, 0, -count), nil
+		}
+	}
+	if duration, err := time.ParseDuration(value); err == nil && duration >= 0 {
+		return now.Add(-duration), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %s, expected a RFC 3339 time (2024-01-15T10:00:00Z), a date (2024-01-15) or a duration such as 30m, 12h or 7d", value)
+}
+
+// SortExecutionHistory sorts the entries from the newest execution to the oldest one.
+func SortExecutionHistory(entries []*model.ExecutionHistoryEntry) {
+	slices.SortStableFunc(entries, func(a, b *model.ExecutionHistoryEntry) int {
+		return cmp.Compare(b.StartTimeMillis, a.StartTimeMillis)
+	})
+}
+
+// FetchExecutionHistory returns the history of a worker, read page by page from the servers paginating it.
+// A server without pagination returns its whole history with the first page, which is then the only one requested.
+// The criteria of the filter are sent to the server, which may ignore them: the caller still applies the filter to the entries.
+// While the pages go from the newest execution to the oldest one, the paging stops once an execution started before filter.Since
+// is reached, or once limit entries match the filter (0 for no limit); the history is read entirely otherwise.
+func FetchExecutionHistory(c model.IntFlagProvider, server *Server, options workerclient.ExecutionHistoryOptions, filter *ExecutionHistoryFilter, limit int) ([]*model.ExecutionHistoryEntry, error) {
+	if filter == nil {
+		filter = &ExecutionHistoryFilter{}
+	}
+	options.Since, options.Until, options.Statuses = filter.Since, filter.Until, filter.serverStatuses()
+
+	seen := map[string]bool{}
+	history := make([]*model.ExecutionHistoryEntry, 0)
+	newestFirst, matching := true, 0
+
+	for offset := 0; ; offset += ExecutionHistoryPageSize {
+		options.Limit, options.Offset = ExecutionHistoryPageSize, offset
+		page, err := CallWorkerClient(c, server, func(ctx context.Context, client *workerclient.Client) ([]*model.ExecutionHistoryEntry, error) {
+			return client.ExecutionHistory(ctx, options)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		added := 0
+		for _, entry := range page {
+			key := ExecutionHistoryKey(entry)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			if len(history) > 0 && entry.StartTimeMillis > history[len(history)-1].StartTimeMillis {
+				newestFirst = false
+			}
+			history = append(history, entry)
+			if filter.Matches(entry) {
+				matching++
+			}
+			added++
+		}
+
+		// A page of another size is the last one, or the whole history. A server ignoring the offset returns the same page again.
+		lastPage := len(page) != ExecutionHistoryPageSize || added == 0
+		if !lastPage && newestFirst && len(history) > 0 {
+			oldest := time.UnixMilli(history[len(history)-1].StartTimeMillis)
+			lastPage = (limit > 0 && matching >= limit) || (!filter.Since.IsZero() && oldest.Before(filter.Since))
+		}
+		if lastPage {
+			if offset > 0 {
+				log.Debug(fmt.Sprintf("Read %d pages of the execution history of %s", offset/ExecutionHistoryPageSize+1, options.WorkerKey))
+			}
+			return history, nil
+		}
+	}
+}
+
+// FetchExecutionHistories returns the histories of several workers as a single one, in no particular order.
+// The filter and the limit stop the paging of each history like with FetchExecutionHistory.
+func FetchExecutionHistories(c model.IntFlagProvider, server *Server, workerKeys []string, options workerclient.ExecutionHistoryOptions, filter *ExecutionHistoryFilter, limit int) ([]*model.ExecutionHistoryEntry, error) {
+	if len(workerKeys) == 1 {
+		options.WorkerKey = workerKeys[0]
+		return FetchExecutionHistory(c, server, options, filter, limit)
+	}
+
+	histories := make([][]*model.ExecutionHistoryEntry, len(workerKeys))
+	errs := make([]error, len(workerKeys))
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, executionHistoryConcurrency)
+	for i, workerKey := range workerKeys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			workerOptions := options
+			workerOptions.WorkerKey = workerKey
+			histories[i], errs[i] = FetchExecutionHistory(c, server, workerOptions, filter, limit)
+		}()
+	}
+	wg.Wait()
+
+	history := make([]*model.ExecutionHistoryEntry, 0)
+	for i, workerHistory := range histories {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("cannot fetch the execution history of %s: %w", workerKeys[i], errs[i])
+		}
+		history = append(history, workerHistory...)
+	}
+	return history, nil
+}
+
+// ExecutionHistoryKey identifies an execution, by its trace ID when it has one.
+func ExecutionHistoryKey(entry *model.ExecutionHistoryEntry) string {
+	if entry.TraceID != "" {
+		return entry.TraceID
+	}
+	return fmt.Sprintf("%s/%d/%d/%s", entry.WorkerKey, entry.StartTimeMillis, entry.EndTimeMillis, entry.ExecutionStatus)
+}
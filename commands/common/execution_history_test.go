@@ -0,0 +1,169 @@
+//go:build test
+// +build test
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+	"github.com/jfrog/jfrog-cli-platform-services/workerclient"
+)
+
+func TestExecutionHistoryFilter_Matches(t *testing.T) {
+	startedAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	entry := &model.ExecutionHistoryEntry{
+		ExecutionStatus: "STATUS_FAIL",
+		StartTimeMillis: startedAt.UnixMilli(),
+		ExecutedVersion: "1.2.0",
+		TriggeredBy:     "Admin",
+	}
+
+	tests := []struct {
+		name   string
+		filter ExecutionHistoryFilter
+		want   bool
+	}{
+		{name: "no criteria", want: true},
+		{name: "status", filter: ExecutionHistoryFilter{Statuses: []string{"STATUS_FAIL"}}, want: true},
+		{name: "status without prefix", filter: ExecutionHistoryFilter{Statuses: []string{"success", "fail"}}, want: true},
+		{name: "other status", filter: ExecutionHistoryFilter{Statuses: []string{"success"}}},
+		{name: "since", filter: ExecutionHistoryFilter{Since: startedAt}, want: true},
+		{name: "since after", filter: ExecutionHistoryFilter{Since: startedAt.Add(time.Millisecond)}},
+		{name: "until", filter: ExecutionHistoryFilter{Until: startedAt.Add(time.Millisecond)}, want: true},
+		{name: "until excluded", filter: ExecutionHistoryFilter{Until: startedAt}},
+		{name: "version", filter: ExecutionHistoryFilter{Version: "1.2.0"}, want: true},
+		{name: "other version", filter: ExecutionHistoryFilter{Version: "1.2"}},
+		{name: "triggered by ignoring the case", filter: ExecutionHistoryFilter{TriggeredBy: "admin"}, want: true},
+		{name: "triggered by another user", filter: ExecutionHistoryFilter{TriggeredBy: "bob"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.Matches(entry))
+		})
+	}
+}
+
+func TestParseHistoryTime(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{value: "2024-01-10T08:30:00Z", want: time.Date(2024, 1, 10, 8, 30, 0, 0, time.UTC)},
+		{value: "2024-01-10", want: time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)},
+		{value: "90m", want: now.Add(-90 * time.Minute)},
+		{value: "7d", want: time.Date(2024, 1, 8, 10, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseHistoryTime(tt.value, now)
+		require.NoError(t, err, tt.value)
+		assert.True(t, tt.want.Equal(got), "%s: want %s, got %s", tt.value, tt.want, got)
+	}
+
+	for _, invalid := range []string{"yesterday", "-2h", "2024-13-01"} {
+		_, err := ParseHistoryTime(invalid, now)
+		assert.ErrorContains(t, err, "invalid time "+invalid, invalid)
+	}
+}
+
+func TestSortExecutionHistory(t *testing.T) {
+	entries := []*model.ExecutionHistoryEntry{{TraceID: "old", StartTimeMillis: 1}, {TraceID: "new", StartTimeMillis: 3}, {TraceID: "middle", StartTimeMillis: 2}}
+	SortExecutionHistory(entries)
+	assert.Equal(t, []string{"new", "middle", "old"}, []string{entries[0].TraceID, entries[1].TraceID, entries[2].TraceID})
+}
+
+func TestFetchExecutionHistory(t *testing.T) {
+	const entriesCount = 3 * ExecutionHistoryPageSize
+	startedAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		oldestFirst   bool
+		filter        *ExecutionHistoryFilter
+		limit         int
+		wantPages     int
+		wantEntries   int
+		wantQueryKeys map[string]string
+	}{
+		{
+			name:        "whole history",
+			wantPages:   4,
+			wantEntries: entriesCount,
+		},
+		{
+			name:          "stop once the limit is reached",
+			filter:        &ExecutionHistoryFilter{Statuses: []string{"success"}},
+			limit:         ExecutionHistoryPageSize + 1,
+			wantPages:     3,
+			wantEntries:   3 * ExecutionHistoryPageSize,
+			wantQueryKeys: map[string]string{"status": "STATUS_SUCCESS"},
+		},
+		{
+			name:        "stop once an execution started before since is reached",
+			filter:      &ExecutionHistoryFilter{Since: startedAt.Add(-150 * time.Minute), Until: startedAt},
+			wantPages:   2,
+			wantEntries: 2 * ExecutionHistoryPageSize,
+			wantQueryKeys: map[string]string{
+				"since": strconv.FormatInt(startedAt.Add(-150*time.Minute).UnixMilli(), 10),
+				"until": strconv.FormatInt(startedAt.UnixMilli(), 10),
+			},
+		},
+		{
+			name:        "read the whole history when the pages are not ordered",
+			oldestFirst: true,
+			filter:      &ExecutionHistoryFilter{Since: startedAt.Add(-150 * time.Minute)},
+			limit:       1,
+			wantPages:   4,
+			wantEntries: entriesCount,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The entries start a minute apart, every other one failed
+			entries := make([]*model.ExecutionHistoryEntry, entriesCount)
+			for i := range entries {
+				entries[i] = &model.ExecutionHistoryEntry{
+					TraceID:         fmt.Sprintf("trace-%d", i),
+					ExecutionStatus: []string{"STATUS_SUCCESS", "STATUS_FAIL"}[i%2],
+					StartTimeMillis: startedAt.Add(-time.Duration(i) * time.Minute).UnixMilli(),
+				}
+			}
+			if tt.oldestFirst {
+				slices.Reverse(entries)
+			}
+
+			pages := 0
+			server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				pages++
+				for key, value := range tt.wantQueryKeys {
+					assert.Equal(t, value, req.URL.Query().Get(key), key)
+				}
+				offset, _ := strconv.Atoi(req.URL.Query().Get("offset"))
+				limit, _ := strconv.Atoi(req.URL.Query().Get("limit"))
+				_ = json.NewEncoder(res).Encode(entries[min(offset, len(entries)):min(offset+limit, len(entries))])
+			}))
+			t.Cleanup(server.Close)
+
+			history, err := FetchExecutionHistory(IntFlagMap{}, &Server{ServerDetails: &config.ServerDetails{Url: server.URL + "/"}},
+				workerclient.ExecutionHistoryOptions{WorkerKey: "wk-1"}, tt.filter, tt.limit)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPages, pages)
+			assert.Len(t, history, tt.wantEntries)
+		})
+	}
+}
@@ -0,0 +1,310 @@
+package common
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The fields of an execution response, the test-run responses have them under the name of the event.
+const (
+	executionFieldData   = "data"
+	executionFieldStatus = "executionStatus"
+	executionFieldLogs   = "logs"
+)
+
+var logLevelPrefix = regexp.MustCompile(`^\[?(?i:(TRACE|DEBUG|INFO|WARN|WARNING|ERROR|FATAL))\]?(:|\s|$)\s*`)
+
+// ExecutionLog is a line logged by a worker during an execution.
+type ExecutionLog struct {
+	Level     string `json:"level,omitempty"`
+	Message   string `json:"message"`
+	Timestamp string `json:"timestamp,omitempty"`
+}
+
+// ExecutionResult is the response of the execute and test-run endpoints, read tolerantly as the shape depends on the server version.
+type ExecutionResult struct {
+	// Event is the name of the event wrapping the response of a test-run, e.g. genericEvent
+	Event  string
+	Status string
+	Data   any
+	Logs   []*ExecutionLog
+	// Duration is the round trip of the request
+	Duration time.Duration
+	// Extra are the other fields of the response
+	Extra map[string]any
+}
+
+// ParseExecutionResponse reads an execution response, the boolean is false when the response does not have the fields of an execution.
+// The roundTrip is used as duration when the server does not report one.
+func ParseExecutionResponse(response json.RawMessage, roundTrip time.Duration) (*ExecutionResult, bool) {
+	var fields map[string]any
+	if err := json.Unmarshal(response, &fields); err != nil || fields == nil {
+		return nil, false
+	}
+
+	result := &ExecutionResult{Duration: roundTrip, Extra: map[string]any{}}
+
+	if event, envelope, isEnvelope := unwrapEventEnvelope(fields); isEnvelope {
+		result.Event = event
+		fields = envelope
+	}
+
+	_, hasData := fields[executionFieldData]
+	_, hasStatus := fields[executionFieldStatus]
+	if !hasData && !hasStatus {
+		return nil, false
+	}
+
+	for name, value := range fields {
+		switch {
+		case name == executionFieldData:
+			result.Data = value
+		case name == executionFieldStatus:
+			result.Status = FormatCell(value)
+		case name == executionFieldLogs:
+			result.Logs = parseExecutionLogs(value)
+		default:
+			result.Extra[name] = value
+		}
+	}
+
+	return result, true
+}
+
+// unwrapEventEnvelope returns the content of a response made of a single event, as returned by test-run.
+func unwrapEventEnvelope(fields map[string]any) (string, map[string]any, bool) {
+	if len(fields) != 1 {
+		return "", nil, false
+	}
+	for event, value := range fields {
+		envelope, isObject := value.(map[string]any)
+		if !isObject {
+			return "", nil, false
+		}
+		_, hasData := envelope[executionFieldData]
+		_, hasStatus := envelope[executionFieldStatus]
+		if hasData || hasStatus {
+			return event, envelope, true
+		}
+	}
+	return "", nil, false
+}
+
+// parseExecutionLogs reads the logs given as objects, as lines or as a single text, in their order.
+func parseExecutionLogs(value any) []*ExecutionLog {
+	var logs []*ExecutionLog
+	switch v := value.(type) {
+	case string:
+		for _, line := range strings.Split(strings.TrimRight(v, "\n"), "\n") {
+			logs = append(logs, parseLogLine(line))
+		}
+	case []any:
+		for _, entry := range v {
+			object, isObject := entry.(map[string]any)
+			if !isObject {
+				logs = append(logs, parseLogLine(FormatCell(entry)))
+				continue
+			}
+			logs = append(logs, &ExecutionLog{
+				Level:     strings.ToUpper(firstField(object, "level", "severity", "logLevel")),
+				Message:   firstField(object, "message", "msg", "text"),
+				Timestamp: firstField(object, "timestamp", "time", "date"),
+			})
+		}
+	case nil:
+	default:
+		logs = append(logs, &ExecutionLog{Message: FormatCell(v)})
+	}
+	return logs
+}
+
+func parseLogLine(line string) *ExecutionLog {
+	match := logLevelPrefix.FindStringSubmatchIndex(line)
+	if match == nil {
+		return &ExecutionLog{Message: line}
+	}
+	return &ExecutionLog{Level: strings.ToUpper(line[match[2]:match[3]]), Message: line[match[1]:]}
+}
+
+func firstField(object map[string]any, names ...string) string {
+	for _, name := range names {
+		if value, found := object[name]; found && value != nil {
+			return FormatCell(value)
+		}
+	}
+	return ""
+}
+
+// Failed tells whether the execution status is not a success, an execution without status is not considered failed.
+func (r *ExecutionResult) Failed() bool {
+	return r.Status != "" && !strings.HasSuffix(strings.ToUpper(r.Status), "SUCCESS")
+}
+
+// Table returns the dotted paths of the result and their values, in a deterministic order:
+// the event, the status and the duration first, then the data, the logs and the other fields.
+func (r *ExecutionResult) Table() *Table {
+	table := &Table{Headers: []string{"FIELD", "VALUE"}, NoCsvHeaders: true}
+	if r.Event != "" {
+		table.Rows = append(table.Rows, []string{"event", r.Event})
+	}
+	if r.Status != "" {
+		table.Rows = append(table.Rows, []string{executionFieldStatus, r.Status})
+	}
+	table.Rows = append(table.Rows, []string{"durationMillis", strconv.FormatInt(r.Duration.Milliseconds(), 10)})
+	table.Rows = append(table.Rows, FlattenJSON(executionFieldData, r.Data)...)
+	for i, entry := range r.Logs {
+		path := fmt.Sprintf("%s[%d]", executionFieldLogs, i)
+		if entry.Timestamp != "" {
+			table.Rows = append(table.Rows, []string{path + ".timestamp", entry.Timestamp})
+		}
+		if entry.Level != "" {
+			table.Rows = append(table.Rows, []string{path + ".level", entry.Level})
+		}
+		table.Rows = append(table.Rows, []string{path + ".message", entry.Message})
+	}
+	for _, name := range sortedKeys(r.Extra) {
+		table.Rows = append(table.Rows, FlattenJSON(jsonPathKey("", name), r.Extra[name])...)
+	}
+	return table
+}
+
+// PrintText prints the status, the duration, the returned value as indented JSON and the logs, the errors are highlighted in a terminal.
+func (r *ExecutionResult) PrintText() error {
+	if r.Event != "" {
+		if err := Print("Event:    %s\n", r.Event); err != nil {
+			return err
+		}
+	}
+	if r.Status != "" {
+		color := ColorGreen
+		if r.Failed() {
+			color = ColorRed
+		}
+		if err := Print("Status:   %s\n", Colorize(r.Status, color)); err != nil {
+			return err
+		}
+	}
+
+	if err := Print("Duration: %s (round trip)\n\nResult:\n", r.Duration.Round(time.Millisecond)); err != nil {
+		return err
+	}
+	if err := printIndentedJSON(r.Data); err != nil {
+		return err
+	}
+
+	if len(r.Extra) > 0 {
+		if err := Print("\nDetails:\n"); err != nil {
+			return err
+		}
+		if err := printIndentedJSON(r.Extra); err != nil {
+			return err
+		}
+	}
+
+	if len(r.Logs) == 0 {
+		return nil
+	}
+	if err := Print("\nLogs:\n"); err != nil {
+		return err
+	}
+	for _, entry := range r.Logs {
+		if err := Print("%s\n", entry.format()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printIndentedJSON(value any) error {
+	if err := PrintJSONValue(value); err != nil {
+		return err
+	}
+	return Print("\n")
+}
+
+func (l *ExecutionLog) format() string {
+	var line strings.Builder
+	if l.Timestamp != "" {
+		line.WriteString(l.Timestamp + " ")
+	}
+	if l.Level != "" {
+		line.WriteString(fmt.Sprintf("%-5s ", l.Level))
+	}
+	line.WriteString(l.Message)
+
+	switch l.Level {
+	case "ERROR", "FATAL":
+		return Colorize(line.String(), ColorRed)
+	case "WARN", "WARNING":
+		return Colorize(line.String(), ColorYellow)
+	case "DEBUG", "TRACE":
+		return Colorize(line.String(), ColorGray)
+	default:
+		return line.String()
+	}
+}
+
+// FlattenJSON returns the scalar values of a JSON document with their dotted paths, under a root path.
+// The fields of the objects are sorted and the elements of the arrays are in their order, the empty objects and arrays are kept as {} and [].
+func FlattenJSON(root string, value any) [][]string {
+	document, err := toJSONDocument(value)
+	if err != nil {
+		return [][]string{{cmp.Or(root, "value"), fmt.Sprint(value)}}
+	}
+
+	var rows [][]string
+	var flatten func(path string, value any)
+	flatten = func(path string, value any) {
+		switch v := value.(type) {
+		case map[string]any:
+			if len(v) == 0 {
+				rows = append(rows, []string{path, "{}"})
+			}
+			for _, key := range sortedKeys(v) {
+				flatten(jsonPathKey(path, key), v[key])
+			}
+		case []any:
+			if len(v) == 0 {
+				rows = append(rows, []string{path, "[]"})
+			}
+			for i, element := range v {
+				flatten(fmt.Sprintf("%s[%d]", path, i), element)
+			}
+		default:
+			rows = append(rows, []string{cmp.Or(path, "value"), FormatCell(v)})
+		}
+	}
+	flatten(root, document)
+	return rows
+}
+
+var simpleJSONKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// jsonPathKey appends a field to a path, quoted as in --query when it is not a simple identifier.
+func jsonPathKey(path string, key string) string {
+	if !simpleJSONKey.MatchString(key) {
+		return path + "[" + strconv.Quote(key) + "]"
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// NewExecutionOutput returns the views of an execution response: the response as is with the json and yaml formats,
+// the dotted paths of its fields with the csv and table formats, and the status, result and logs with the text format.
+func NewExecutionOutput(response json.RawMessage, roundTrip time.Duration) *Result {
+	result, isExecution := ParseExecutionResponse(response, roundTrip)
+	if !isExecution {
+		return &Result{
+			Value: response,
+			Table: &Table{Headers: []string{"FIELD", "VALUE"}, NoCsvHeaders: true, Rows: FlattenJSON("", response)},
+		}
+	}
+	return &Result{Value: response, Table: result.Table(), Text: result.PrintText}
+}
@@ -0,0 +1,154 @@
+//go:build test
+// +build test
+
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExecutionResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		response    string
+		notExecuted bool
+		want        *ExecutionResult
+	}{
+		{
+			name:     "execute",
+			response: `{"data":{"ok":true},"executionStatus":"STATUS_SUCCESS"}`,
+			want: &ExecutionResult{
+				Status:   "STATUS_SUCCESS",
+				Data:     map[string]any{"ok": true},
+				Duration: time.Second,
+				Extra:    map[string]any{},
+			},
+		},
+		{
+			name:     "test-run event",
+			response: `{"genericEvent":{"data":"done","executionStatus":"STATUS_FAIL","executionDurationMillis":12,"errors":["boom"]}}`,
+			want: &ExecutionResult{
+				Event:    "genericEvent",
+				Status:   "STATUS_FAIL",
+				Data:     "done",
+				Duration: time.Second,
+				Extra:    map[string]any{"errors": []any{"boom"}, "executionDurationMillis": float64(12)},
+			},
+		},
+		{
+			name:     "logs as objects",
+			response: `{"data":null,"logs":[{"level":"info","message":"first","timestamp":"10:00"},{"severity":"ERROR","msg":"second"}]}`,
+			want: &ExecutionResult{
+				Duration: time.Second,
+				Logs:     []*ExecutionLog{{Level: "INFO", Message: "first", Timestamp: "10:00"}, {Level: "ERROR", Message: "second"}},
+				Extra:    map[string]any{},
+			},
+		},
+		{
+			name:     "logs as lines",
+			response: `{"data":null,"logs":["[WARN] first","error: second","third"]}`,
+			want: &ExecutionResult{
+				Duration: time.Second,
+				Logs:     []*ExecutionLog{{Level: "WARN", Message: "first"}, {Level: "ERROR", Message: "second"}, {Message: "third"}},
+				Extra:    map[string]any{},
+			},
+		},
+		{
+			name:     "logs as text",
+			response: `{"data":null,"logs":"DEBUG first\nsecond\n"}`,
+			want: &ExecutionResult{
+				Duration: time.Second,
+				Logs:     []*ExecutionLog{{Level: "DEBUG", Message: "first"}, {Message: "second"}},
+				Extra:    map[string]any{},
+			},
+		},
+		{
+			name:        "not an execution",
+			response:    `{"status":"OK"}`,
+			notExecuted: true,
+		},
+		{
+			name:        "not an object",
+			response:    `["OK"]`,
+			notExecuted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, isExecution := ParseExecutionResponse(json.RawMessage(tt.response), time.Second)
+			assert.Equal(t, !tt.notExecuted, isExecution)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExecutionResult_Table(t *testing.T) {
+	result, isExecution := ParseExecutionResponse(json.RawMessage(`{"genericEvent":{
+		"executionStatus":"STATUS_SUCCESS",
+		"data":{"zeta":1,"user":{"name":"me","roles":["a","b"]},"alpha":{},"dotted.key":[]},
+		"logs":[{"level":"INFO","message":"hello"}],
+		"traceId":"t-1"
+	}}`), 1500*time.Millisecond)
+	require.True(t, isExecution)
+
+	assert.Equal(t, [][]string{
+		{"event", "genericEvent"},
+		{"executionStatus", "STATUS_SUCCESS"},
+		{"durationMillis", "1500"},
+		{"data.alpha", "{}"},
+		{`data["dotted.key"]`, "[]"},
+		{"data.user.name", "me"},
+		{"data.user.roles[0]", "a"},
+		{"data.user.roles[1]", "b"},
+		{"data.zeta", "1"},
+		{"logs[0].level", "INFO"},
+		{"logs[0].message", "hello"},
+		{"traceId", "t-1"},
+	}, result.Table().Rows)
+}
+
+func TestExecutionResult_PrintText(t *testing.T) {
+	var out bytes.Buffer
+	SetCliOut(&out)
+	t.Cleanup(func() { SetCliOut(os.Stdout) })
+
+	forcedColors := true
+	previousColorsEnabled := colorsEnabled
+	colorsEnabled = func() bool { return forcedColors }
+	t.Cleanup(func() { colorsEnabled = previousColorsEnabled })
+
+	result, isExecution := ParseExecutionResponse(json.RawMessage(`{
+		"executionStatus":"STATUS_FAIL",
+		"data":{"b":2,"a":1},
+		"logs":[{"level":"INFO","message":"started"},{"level":"ERROR","message":"failed"}]
+	}`), 42*time.Millisecond)
+	require.True(t, isExecution)
+
+	require.NoError(t, result.PrintText())
+	assert.Equal(t, "Status:   \x1b[31mSTATUS_FAIL\x1b[0m\n"+
+		"Duration: 42ms (round trip)\n\n"+
+		"Result:\n{\n  \"a\": 1,\n  \"b\": 2\n}\n\n"+
+		"Logs:\n"+
+		"INFO  started\n"+
+		"\x1b[31mERROR failed\x1b[0m\n", out.String())
+
+	out.Reset()
+	forcedColors = false
+	require.NoError(t, result.PrintText())
+	assert.Contains(t, out.String(), "Status:   STATUS_FAIL\n")
+	assert.Contains(t, out.String(), "ERROR failed\n")
+}
+
+func TestNewExecutionOutput_UnknownResponse(t *testing.T) {
+	result := NewExecutionOutput(json.RawMessage(`{"status":"OK","nested":{"a":[1]}}`), time.Second)
+	assert.Nil(t, result.Text)
+	assert.Equal(t, [][]string{{"nested.a[0]", "1"}, {"status", "OK"}}, result.Table.Rows)
+}
@@ -0,0 +1,408 @@
+package common
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	redactedValue = "***"
+	// The number of characters of the source code kept in the trace when --trace-include-source is not set
+	maxTracedSourceChars = 100
+)
+
+// harRecorder records HTTP exchanges in a HAR 1.2 file, which is rewritten after every exchange
+// so that the file is complete even if the command fails.
+type harRecorder struct {
+	mu            sync.Mutex
+	path          string
+	includeSource bool
+	log           harLog
+}
+
+type harFile struct {
+	Log *harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	Comment         string      `json:"comment,omitempty"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+// harTimings are in milliseconds, -1 means that the phase does not apply to the request (e.g. a reused connection).
+type harTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	SSL     float64 `json:"ssl"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+func newHARRecorder(path string, includeSource bool) *harRecorder {
+	return &harRecorder{
+		path:          path,
+		includeSource: includeSource,
+		log: harLog{
+			Version: "1.2",
+			Creator: harCreator{Name: "jfrog-cli-platform-services", Version: coreutils.GetCliUserAgent()},
+			Entries: []harEntry{},
+		},
+	}
+}
+
+// withHTTPTrace returns a copy of the client recording its calls with the recorder.
+func withHTTPTrace(client *http.Client, recorder *harRecorder) *http.Client {
+	next := client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	traced := *client
+	traced.Transport = &recordingTransport{next: next, recorder: recorder}
+
+	return &traced
+}
+
+type recordingTransport struct {
+	next     http.RoundTripper
+	recorder *harRecorder
+}
+
+// phaseTimer collects the instants of the phases of a request with httptrace.
+type phaseTimer struct {
+	mu                                       sync.Mutex
+	dnsStart, dnsDone                        time.Time
+	connectStart, connectDone                time.Time
+	tlsStart, tlsDone                        time.Time
+	gotConn, wroteRequest, firstResponseByte time.Time
+}
+
+func (p *phaseTimer) mark(instant *time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	*instant = time.Now()
+}
+
+func (p *phaseTimer) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { p.mark(&p.dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { p.mark(&p.dnsDone) },
+		ConnectStart:         func(string, string) { p.mark(&p.connectStart) },
+		ConnectDone:          func(string, string, error) { p.mark(&p.connectDone) },
+		TLSHandshakeStart:    func() { p.mark(&p.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { p.mark(&p.tlsDone) },
+		GotConn:              func(httptrace.GotConnInfo) { p.mark(&p.gotConn) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { p.mark(&p.wroteRequest) },
+		GotFirstResponseByte: func() { p.mark(&p.firstResponseByte) },
+	}
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var requestBody []byte
+	if req.Body != nil && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err == nil {
+			requestBody, _ = io.ReadAll(body)
+			CloseQuietly(body)
+		}
+	}
+
+	timer := &phaseTimer{}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), timer.clientTrace()))
+
+	start := time.Now()
+	res, err := t.next.RoundTrip(req)
+	if err != nil {
+		t.recorder.record(req, requestBody, nil, nil, start, time.Now(), timer, err)
+		return nil, err
+	}
+
+	responseBody, readErr := io.ReadAll(res.Body)
+	CloseQuietly(res.Body)
+	end := time.Now()
+	res.Body = io.NopCloser(bytes.NewReader(responseBody))
+
+	t.recorder.record(req, requestBody, res, responseBody, start, end, timer, readErr)
+
+	if readErr != nil {
+		return nil, readErr
+	}
+
+	return res, nil
+}
+
+func (r *harRecorder) record(req *http.Request, requestBody []byte, res *http.Response, responseBody []byte, start, end time.Time, timer *phaseTimer, failure error) {
+	timer.mu.Lock()
+	timings := computeTimings(start, end, timer)
+	timer.mu.Unlock()
+
+	entry := harEntry{
+		StartedDateTime: start.Format(time.RFC3339Nano),
+		Time:            millis(end.Sub(start)),
+		Request: harRequest{
+			Method:      req.Method,
+			URL:         req.URL.String(),
+			HTTPVersion: req.Proto,
+			Cookies:     []harNameValue{},
+			Headers:     harHeaders(req.Header),
+			QueryString: []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    len(requestBody),
+		},
+		Timings: timings,
+	}
+
+	for name, values := range req.URL.Query() {
+		for _, value := range values {
+			entry.Request.QueryString = append(entry.Request.QueryString, harNameValue{Name: name, Value: value})
+		}
+	}
+
+	if len(requestBody) > 0 {
+		entry.Request.PostData = &harPostData{
+			MimeType: req.Header.Get("Content-Type"),
+			Text:     r.sanitizeBody(requestBody, true),
+		}
+	}
+
+	if res != nil {
+		entry.Response = harResponse{
+			Status:      res.StatusCode,
+			StatusText:  http.StatusText(res.StatusCode),
+			HTTPVersion: res.Proto,
+			Cookies:     []harNameValue{},
+			Headers:     harHeaders(res.Header),
+			Content: harContent{
+				Size:     len(responseBody),
+				MimeType: res.Header.Get("Content-Type"),
+				Text:     r.sanitizeBody(responseBody, false),
+			},
+			RedirectURL: res.Header.Get("Location"),
+			HeadersSize: -1,
+			BodySize:    len(responseBody),
+		}
+	} else {
+		// HAR expects a response, we record an empty one as browsers do for aborted requests
+		entry.Response = harResponse{Cookies: []harNameValue{}, Headers: []harNameValue{}, HeadersSize: -1, BodySize: -1}
+	}
+
+	if failure != nil {
+		entry.Comment = failure.Error()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.log.Entries = append(r.log.Entries, entry)
+
+	if err := r.save(); err != nil {
+		// Tracing must not break the command
+		log.Warn(fmt.Sprintf("Cannot write the HTTP trace to %s: %+v", r.path, err))
+	}
+}
+
+func (r *harRecorder) save() error {
+	content, err := json.MarshalIndent(harFile{Log: &r.log}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, content, 0o600)
+}
+
+func computeTimings(start, end time.Time, timer *phaseTimer) harTimings {
+	timings := harTimings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1}
+
+	if !timer.dnsStart.IsZero() && !timer.dnsDone.IsZero() {
+		timings.DNS = millis(timer.dnsDone.Sub(timer.dnsStart))
+	}
+	if !timer.connectStart.IsZero() && !timer.connectDone.IsZero() {
+		timings.Connect = millis(timer.connectDone.Sub(timer.connectStart))
+	}
+	if !timer.tlsStart.IsZero() && !timer.tlsDone.IsZero() {
+		timings.SSL = millis(timer.tlsDone.Sub(timer.tlsStart))
+	}
+
+	sendStart := start
+	if !timer.gotConn.IsZero() {
+		sendStart = timer.gotConn
+		timings.Blocked = millis(timer.gotConn.Sub(start))
+		// HAR counts dns and connect apart from blocked
+		for _, phase := range []float64{timings.DNS, timings.Connect} {
+			if phase > 0 {
+				timings.Blocked -= phase
+			}
+		}
+		timings.Blocked = max(timings.Blocked, 0)
+	}
+
+	wroteRequest := sendStart
+	if !timer.wroteRequest.IsZero() {
+		wroteRequest = timer.wroteRequest
+	}
+	timings.Send = millis(wroteRequest.Sub(sendStart))
+
+	firstByte := end
+	if !timer.firstResponseByte.IsZero() {
+		firstByte = timer.firstResponseByte
+	}
+	timings.Wait = millis(firstByte.Sub(wroteRequest))
+	timings.Receive = millis(end.Sub(firstByte))
+
+	return timings
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func harHeaders(header http.Header) []harNameValue {
+	headers := make([]harNameValue, 0, len(header))
+	for name, values := range header {
+		for _, value := range values {
+			if strings.EqualFold(name, "Authorization") {
+				scheme, _, _ := strings.Cut(value, " ")
+				value = scheme + " " + redactedValue
+			}
+			headers = append(headers, harNameValue{Name: name, Value: value})
+		}
+	}
+	return headers
+}
+
+// sanitizeBody redacts the secret values, and truncates the source code unless requested otherwise.
+// Bodies that are not JSON are recorded as is.
+func (r *harRecorder) sanitizeBody(body []byte, isRequest bool) string {
+	var content any
+	if err := json.Unmarshal(body, &content); err != nil {
+		return string(body)
+	}
+
+	// The test-run request carries the source code under 'code', which denotes an error code in responses
+	if obj, isObj := content.(map[string]any); isObj && isRequest {
+		r.truncateSource(obj, "code")
+	}
+
+	r.sanitizeValue(content)
+
+	sanitized, err := json.Marshal(content)
+	if err != nil {
+		return string(body)
+	}
+	return string(sanitized)
+}
+
+func (r *harRecorder) sanitizeValue(value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			switch key {
+			case "secrets", "stagedSecrets":
+				redactSecrets(child)
+			case "sourceCode":
+				r.truncateSource(v, key)
+			default:
+				r.sanitizeValue(child)
+			}
+		}
+	case []any:
+		for _, child := range v {
+			r.sanitizeValue(child)
+		}
+	}
+}
+
+func (r *harRecorder) truncateSource(obj map[string]any, key string) {
+	source, isString := obj[key].(string)
+	if r.includeSource || !isString {
+		return
+	}
+	runes := []rune(source)
+	if len(runes) <= maxTracedSourceChars {
+		return
+	}
+	obj[key] = fmt.Sprintf("%s... (truncated, %d characters)", string(runes[:maxTracedSourceChars]), len(runes))
+}
+
+func redactSecrets(value any) {
+	secrets, isArray := value.([]any)
+	if !isArray {
+		return
+	}
+	for _, secret := range secrets {
+		if obj, isObj := secret.(map[string]any); isObj {
+			if _, hasValue := obj["value"]; hasValue {
+				obj["value"] = redactedValue
+			}
+		}
+	}
+}
@@ -0,0 +1,59 @@
+//go:build test
+// +build test
+
+package common
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHARRecorder_SanitizeBody(t *testing.T) {
+	longSource := strings.Repeat("a", maxTracedSourceChars+10)
+
+	tests := []struct {
+		name          string
+		body          string
+		isRequest     bool
+		includeSource bool
+		want          string
+	}{
+		{
+			name:      "redact staged secrets and truncate test-run code",
+			body:      `{"code":"` + longSource + `","stagedSecrets":[{"key":"k","value":"v"}]}`,
+			isRequest: true,
+			want:      `{"code":"` + longSource[:maxTracedSourceChars] + `... (truncated, 110 characters)","stagedSecrets":[{"key":"k","value":"***"}]}`,
+		},
+		{
+			name: "redact nested secrets in responses",
+			body: `{"workers":[{"key":"wk","sourceCode":"short","secrets":[{"key":"k","value":"v"}]}]}`,
+			want: `{"workers":[{"key":"wk","secrets":[{"key":"k","value":"***"}],"sourceCode":"short"}]}`,
+		},
+		{
+			name: "keep error code in responses",
+			body: `{"code":"` + longSource + `"}`,
+			want: `{"code":"` + longSource + `"}`,
+		},
+		{
+			name:          "include source",
+			body:          `{"sourceCode":"` + longSource + `"}`,
+			isRequest:     true,
+			includeSource: true,
+			want:          `{"sourceCode":"` + longSource + `"}`,
+		},
+		{
+			name: "not json",
+			body: `<html>bad gateway</html>`,
+			want: `<html>bad gateway</html>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := newHARRecorder("", tt.includeSource)
+			assert.Equal(t, tt.want, recorder.sanitizeBody([]byte(tt.body), tt.isRequest))
+		})
+	}
+}
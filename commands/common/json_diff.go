@@ -0,0 +1,275 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// jsonPathSegment is a field of an object, or an index of an array.
+type jsonPathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+func (s jsonPathSegment) indexString() string {
+	return "[" + strconv.Itoa(s.index) + "]"
+}
+
+// formatJSONPath formats a path as the flattened views and --query do, the root is formatted as a dot.
+func formatJSONPath(segments []jsonPathSegment) string {
+	var formatted string
+	for _, segment := range segments {
+		if segment.isIndex {
+			formatted += segment.indexString()
+		} else {
+			formatted = jsonPathKey(formatted, segment.key)
+		}
+	}
+	if formatted == "" {
+		return "."
+	}
+	return formatted
+}
+
+// JSONPathPattern matches the paths of a JSON document and their descendants, e.g. logs, data.items[*].id or **.timestamp.
+// A * segment matches any field or index and ** any number of segments, the field names can be glob patterns.
+type JSONPathPattern struct {
+	source   string
+	segments []string
+}
+
+func (p *JSONPathPattern) String() string {
+	return p.source
+}
+
+// ParseJSONPathPattern reads a path pattern, the fields are separated by dots and the indexes are between brackets, as in --query.
+func ParseJSONPathPattern(source string) (*JSONPathPattern, error) {
+	pattern := &JSONPathPattern{source: source}
+	rest := strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(source), "$"), ".")
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path pattern '%s': missing ]", source)
+			}
+			content := rest[1:end]
+			if unquoted, err := strconv.Unquote(content); err == nil {
+				pattern.segments = append(pattern.segments, unquoted)
+			} else if content == "*" {
+				pattern.segments = append(pattern.segments, "[*]")
+			} else if _, err = strconv.Atoi(content); err == nil {
+				pattern.segments = append(pattern.segments, "["+content+"]")
+			} else {
+				return nil, fmt.Errorf("invalid path pattern '%s': invalid index [%s]", source, content)
+			}
+			rest = strings.TrimPrefix(rest[end+1:], ".")
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid path pattern '%s': empty field", source)
+			}
+			if _, err := path.Match(rest[:end], ""); err != nil {
+				return nil, fmt.Errorf("invalid path pattern '%s': %w", source, err)
+			}
+			pattern.segments = append(pattern.segments, rest[:end])
+			rest = strings.TrimPrefix(rest[end:], ".")
+		}
+	}
+	if len(pattern.segments) == 0 {
+		return nil, fmt.Errorf("invalid path pattern '%s': empty path", source)
+	}
+	return pattern, nil
+}
+
+// ParseJSONPathPatterns reads a list of patterns, the blank ones are skipped.
+func ParseJSONPathPatterns(sources []string) ([]*JSONPathPattern, error) {
+	var patterns []*JSONPathPattern
+	for _, source := range sources {
+		if strings.TrimSpace(source) == "" {
+			continue
+		}
+		pattern, err := ParseJSONPathPattern(source)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+// matches tells whether the pattern matches the path, or one of its ancestors.
+func (p *JSONPathPattern) matches(segments []jsonPathSegment) bool {
+	return matchPathSegments(p.segments, segments)
+}
+
+func matchPathSegments(pattern []string, segments []jsonPathSegment) bool {
+	if len(pattern) == 0 {
+		return true
+	}
+	if pattern[0] == "**" {
+		for skipped := 0; skipped <= len(segments); skipped++ {
+			if matchPathSegments(pattern[1:], segments[skipped:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 || !matchPathSegment(pattern[0], segments[0]) {
+		return false
+	}
+	return matchPathSegments(pattern[1:], segments[1:])
+}
+
+func matchPathSegment(pattern string, segment jsonPathSegment) bool {
+	switch {
+	case pattern == "*":
+		return true
+	case segment.isIndex:
+		return pattern == "[*]" || pattern == segment.indexString()
+	default:
+		matched, _ := path.Match(pattern, segment.key)
+		return matched
+	}
+}
+
+func isIgnoredPath(ignored []*JSONPathPattern, segments []jsonPathSegment) bool {
+	return slices.ContainsFunc(ignored, func(pattern *JSONPathPattern) bool {
+		return pattern.matches(segments)
+	})
+}
+
+// JSONDifference is a value that differs between two JSON documents.
+type JSONDifference struct {
+	Path string `json:"path"`
+	// Expected is nil when the value is unexpected
+	Expected json.RawMessage `json:"expected,omitempty"`
+	// Actual is nil when the value is missing
+	Actual json.RawMessage `json:"actual,omitempty"`
+}
+
+func (d *JSONDifference) String() string {
+	switch {
+	case d.Expected == nil:
+		return fmt.Sprintf("%s: unexpected %s", d.Path, d.Actual)
+	case d.Actual == nil:
+		return fmt.Sprintf("%s: missing, expected %s", d.Path, d.Expected)
+	default:
+		return fmt.Sprintf("%s: expected %s, got %s", d.Path, d.Expected, d.Actual)
+	}
+}
+
+// DiffJSON compares two JSON documents and returns their differences, the fields of the objects in sorted order and the elements of the arrays by index.
+// The paths matching an ignored pattern, and their descendants, are not compared.
+func DiffJSON(expected any, actual any, ignored []*JSONPathPattern) ([]*JSONDifference, error) {
+	expectedDocument, err := toJSONDocument(expected)
+	if err != nil {
+		return nil, err
+	}
+	actualDocument, err := toJSONDocument(actual)
+	if err != nil {
+		return nil, err
+	}
+
+	var differences []*JSONDifference
+	var diff func(segments []jsonPathSegment, expected any, actual any, hasExpected bool, hasActual bool)
+	diff = func(segments []jsonPathSegment, expected any, actual any, hasExpected bool, hasActual bool) {
+		if isIgnoredPath(ignored, segments) {
+			return
+		}
+
+		expectedObject, isExpectedObject := expected.(map[string]any)
+		actualObject, isActualObject := actual.(map[string]any)
+		if isExpectedObject && isActualObject {
+			keys := sortedKeys(expectedObject)
+			for _, key := range sortedKeys(actualObject) {
+				if _, found := expectedObject[key]; !found {
+					keys = append(keys, key)
+				}
+			}
+			slices.Sort(keys)
+			for _, key := range keys {
+				expectedValue, hasExpectedValue := expectedObject[key]
+				actualValue, hasActualValue := actualObject[key]
+				diff(append(slices.Clone(segments), jsonPathSegment{key: key}), expectedValue, actualValue, hasExpectedValue, hasActualValue)
+			}
+			return
+		}
+
+		expectedArray, isExpectedArray := expected.([]any)
+		actualArray, isActualArray := actual.([]any)
+		if isExpectedArray && isActualArray {
+			for i := 0; i < max(len(expectedArray), len(actualArray)); i++ {
+				var expectedValue, actualValue any
+				if i < len(expectedArray) {
+					expectedValue = expectedArray[i]
+				}
+				if i < len(actualArray) {
+					actualValue = actualArray[i]
+				}
+				diff(append(slices.Clone(segments), jsonPathSegment{index: i, isIndex: true}), expectedValue, actualValue, i < len(expectedArray), i < len(actualArray))
+			}
+			return
+		}
+
+		if hasExpected && hasActual && reflect.DeepEqual(expected, actual) {
+			return
+		}
+
+		difference := &JSONDifference{Path: formatJSONPath(segments)}
+		if hasExpected {
+			difference.Expected, _ = json.Marshal(expected)
+		}
+		if hasActual {
+			difference.Actual, _ = json.Marshal(actual)
+		}
+		differences = append(differences, difference)
+	}
+	diff(nil, expectedDocument, actualDocument, true, true)
+
+	return differences, nil
+}
+
+// PruneJSON returns a copy of a JSON document without the paths matching an ignored pattern.
+func PruneJSON(value any, ignored []*JSONPathPattern) (any, error) {
+	document, err := toJSONDocument(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var prune func(segments []jsonPathSegment, value any) any
+	prune = func(segments []jsonPathSegment, value any) any {
+		switch v := value.(type) {
+		case map[string]any:
+			pruned := map[string]any{}
+			for key, field := range v {
+				fieldSegments := append(slices.Clone(segments), jsonPathSegment{key: key})
+				if !isIgnoredPath(ignored, fieldSegments) {
+					pruned[key] = prune(fieldSegments, field)
+				}
+			}
+			return pruned
+		case []any:
+			pruned := []any{}
+			for i, element := range v {
+				elementSegments := append(slices.Clone(segments), jsonPathSegment{index: i, isIndex: true})
+				if !isIgnoredPath(ignored, elementSegments) {
+					pruned = append(pruned, prune(elementSegments, element))
+				}
+			}
+			return pruned
+		default:
+			return v
+		}
+	}
+	return prune(nil, document), nil
+}
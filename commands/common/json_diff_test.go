@@ -0,0 +1,140 @@
+//go:build test
+// +build test
+
+package common
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		actual   string
+		ignored  []string
+		want     []string
+	}{
+		{
+			name:     "equal",
+			expected: `{"a":1,"b":[1,{"c":true}]}`,
+			actual:   `{"b":[1,{"c":true}],"a":1}`,
+		},
+		{
+			name:     "changed, missing and unexpected values in path order",
+			expected: `{"z":1,"a":{"b":"x","c":[1,2]},"gone":null}`,
+			actual:   `{"z":2,"a":{"b":"y","c":[1]},"new":{"d":1}}`,
+			want: []string{
+				`a.b: expected "x", got "y"`,
+				`a.c[1]: missing, expected 2`,
+				`gone: missing, expected null`,
+				`new: unexpected {"d":1}`,
+				`z: expected 1, got 2`,
+			},
+		},
+		{
+			name:     "different types",
+			expected: `{"a":{"b":1}}`,
+			actual:   `{"a":[1]}`,
+			want:     []string{`a: expected {"b":1}, got [1]`},
+		},
+		{
+			name:     "ignored paths",
+			expected: `{"event":{"logs":["a"],"data":{"items":[{"id":1,"at":"t1"}],"timestamp":1}}}`,
+			actual:   `{"event":{"logs":["b","c"],"data":{"items":[{"id":1,"at":"t2"}],"timestamp":2}}}`,
+			ignored:  []string{"*.logs", "**.timestamp", "event.data.items[*].at"},
+		},
+		{
+			name:     "quoted fields",
+			expected: `{"a.b":{"c":1}}`,
+			actual:   `{"a.b":{"c":2}}`,
+			want:     []string{`["a.b"].c: expected 1, got 2`},
+		},
+		{
+			name:     "root",
+			expected: `1`,
+			actual:   `"1"`,
+			want:     []string{`.: expected 1, got "1"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ignored, err := ParseJSONPathPatterns(tt.ignored)
+			require.NoError(t, err)
+
+			differences, err := DiffJSON(json.RawMessage(tt.expected), json.RawMessage(tt.actual), ignored)
+			require.NoError(t, err)
+
+			var got []string
+			for _, difference := range differences {
+				got = append(got, difference.String())
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPruneJSON(t *testing.T) {
+	ignored, err := ParseJSONPathPatterns([]string{"logs", `data["x.y"]`, "data.items[0]", "**.time*"})
+	require.NoError(t, err)
+
+	pruned, err := PruneJSON(json.RawMessage(`{"logs":[1],"data":{"x.y":1,"items":[1,2],"timestamp":3,"nested":{"time":4,"kept":5}}}`), ignored)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"data": map[string]any{"items": []any{2.0}, "nested": map[string]any{"kept": 5.0}}}, pruned)
+}
+
+func TestParseJSONPathPattern_Errors(t *testing.T) {
+	for source, wantErr := range map[string]string{
+		"a[1":    "invalid path pattern 'a[1': missing ]",
+		"a[x]":   "invalid path pattern 'a[x]': invalid index [x]",
+		"a..b":   "invalid path pattern 'a..b': empty field",
+		"a.[b-]": "invalid path pattern 'a.[b-]': invalid index [b-]",
+		"$":      "invalid path pattern '$': empty path",
+	} {
+		_, err := ParseJSONPathPattern(source)
+		assert.EqualError(t, err, wantErr, source)
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name   string
+		before []string
+		after  []string
+		want   []string
+	}{
+		{
+			name:   "same",
+			before: []string{"a", "b"},
+			after:  []string{"a", "b"},
+		},
+		{
+			name:   "changed line",
+			before: []string{"start", "size 1", "end"},
+			after:  []string{"start", "size 2", "end"},
+			want:   []string{"- size 1", "+ size 2"},
+		},
+		{
+			name:   "added and removed lines",
+			before: []string{"a", "b", "c"},
+			after:  []string{"b", "c", "d"},
+			want:   []string{"- a", "+ d"},
+		},
+		{
+			name:  "empty before",
+			after: []string{"a"},
+			want:  []string{"+ a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DiffLines(tt.before, tt.after))
+		})
+	}
+}
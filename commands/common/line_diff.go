@@ -0,0 +1,43 @@
+package common
+
+// DiffLines returns the lines removed from before, prefixed with "- ", and the lines added in after, prefixed with "+ ", in the order of the texts.
+// The unchanged lines are the longest common subsequence of the two texts, they are not returned.
+func DiffLines(before []string, after []string) []string {
+	// common[i][j] is the length of the longest common subsequence of before[i:] and after[j:]
+	common := make([][]int, len(before)+1)
+	for i := range common {
+		common[i] = make([]int, len(after)+1)
+	}
+	for i := len(before) - 1; i >= 0; i-- {
+		for j := len(after) - 1; j >= 0; j-- {
+			if before[i] == after[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	var changes []string
+	i, j := 0, 0
+	for i < len(before) && j < len(after) {
+		switch {
+		case before[i] == after[j]:
+			i++
+			j++
+		case common[i+1][j] >= common[i][j+1]:
+			changes = append(changes, "- "+before[i])
+			i++
+		default:
+			changes = append(changes, "+ "+after[j])
+			j++
+		}
+	}
+	for ; i < len(before); i++ {
+		changes = append(changes, "- "+before[i])
+	}
+	for ; j < len(after); j++ {
+		changes = append(changes, "+ "+after[j])
+	}
+	return changes
+}
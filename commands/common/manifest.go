@@ -3,11 +3,13 @@ package common
 //go:generate ${TOOLS_DIR}/mockgen -source=${GOFILE} -destination=mocks/${GOFILE}
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/jfrog/jfrog-cli-platform-services/model"
 	"github.com/jfrog/jfrog-client-go/utils/log"
@@ -94,6 +96,22 @@ func ReadSourceCode(mf *model.Manifest) (string, error) {
 	return string(sourceBytes), nil
 }
 
+// Base64SourceCodePrefix marks a source code sent encoded in base64, for the servers requiring it.
+const Base64SourceCodePrefix = "base64:"
+
+// DecodeSourceCode returns the source code of a deployed worker, decoded when it was deployed in base64.
+func DecodeSourceCode(sourceCode string) (string, error) {
+	encoded, isEncoded := strings.CutPrefix(sourceCode, Base64SourceCodePrefix)
+	if !isEncoded {
+		return sourceCode, nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("invalid base64 source code: %w", err)
+	}
+	return string(decoded), nil
+}
+
 func ValidateManifest(mf *model.Manifest, actionsMeta ActionsMetadata) error {
 	if mf.Name == "" {
 		return invalidManifestErr("missing name")
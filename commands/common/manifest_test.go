@@ -465,3 +465,16 @@ func patchedManifestSample(patch func(mf *model.Manifest)) *model.Manifest {
 	patch(&patched)
 	return &patched
 }
+
+func TestDecodeSourceCode(t *testing.T) {
+	source, err := DecodeSourceCode("export default async () => ({})")
+	require.NoError(t, err)
+	assert.Equal(t, "export default async () => ({})", source)
+
+	source, err = DecodeSourceCode(Base64SourceCodePrefix + "ZXhwb3J0IGRlZmF1bHQgYXN5bmMgKCkgPT4gKHt9KQ==")
+	require.NoError(t, err)
+	assert.Equal(t, "export default async () => ({})", source)
+
+	_, err = DecodeSourceCode(Base64SourceCodePrefix + "not base64!")
+	assert.ErrorContains(t, err, "invalid base64 source code")
+}
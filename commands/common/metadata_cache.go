@@ -0,0 +1,182 @@
+package common
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+	"github.com/jfrog/jfrog-cli-platform-services/workerclient"
+)
+
+const metadataCacheDir = "worker-cli/metadata"
+
+// MetadataOptions tells where the actions and options metadata of a command are read from.
+type MetadataOptions struct {
+	// Refresh bypasses the cache (--refresh-metadata)
+	Refresh bool
+	// Offline only reads the cache, the server is not called (--offline)
+	Offline bool
+	// ActionsFile replaces the actions of the server (--actions-file)
+	ActionsFile string
+	// OptionsFile replaces the options of the server (--options-file)
+	OptionsFile string
+}
+
+// GetMetadataOptions reads the metadata flags of the command, the flags it does not declare are left empty.
+func GetMetadataOptions(c *components.Context) MetadataOptions {
+	return MetadataOptions{
+		Refresh:     c.GetBoolFlagValue(model.FlagRefreshMetadata),
+		Offline:     c.GetBoolFlagValue(model.FlagOffline),
+		ActionsFile: c.GetStringFlagValue(model.FlagActionsFile),
+		OptionsFile: c.GetStringFlagValue(model.FlagOptionsFile),
+	}
+}
+
+// metadataCacheEntry is the content of a cache file.
+type metadataCacheEntry struct {
+	ETag      string          `json:"etag,omitempty"`
+	FetchedAt time.Time       `json:"fetchedAt"`
+	Content   json.RawMessage `json:"content"`
+}
+
+// fetchCachedMetadata returns the content of a metadata endpoint, using a per-server cache stored under the JFrog CLI home directory.
+// A cached content is used as is until its TTL expires, then it is revalidated with its ETag.
+// With --refresh-metadata the cache is bypassed, with --offline the server is not called at all.
+func fetchCachedMetadata(c model.IntFlagProvider, server *Server, cacheName string, request *workerclient.Request) ([]byte, error) {
+	// The timeout is checked even when the cache is used, so that an invalid value does not depend on the state of the cache
+	if _, err := model.GetTimeoutParameter(c); err != nil {
+		return nil, localError("%+v", err)
+	}
+
+	refresh, offline := server.Metadata.Refresh, server.Metadata.Offline
+
+	cacheFile, err := getMetadataCacheFile(server.GetUrl(), cacheName)
+	if err != nil {
+		log.Debug(fmt.Sprintf("Cannot locate the metadata cache: %+v", err))
+	}
+
+	var cached *metadataCacheEntry
+	if cacheFile != "" && !refresh {
+		cached = readMetadataCacheEntry(cacheFile)
+	}
+
+	if offline {
+		if cached == nil {
+			return nil, fmt.Errorf("no cached %s metadata for %s, run the command once without --%s", cacheName, server.GetUrl(), model.FlagOffline)
+		}
+		log.Debug(fmt.Sprintf("Using the cached %s metadata fetched at %s", cacheName, cached.FetchedAt.Format(time.RFC3339)))
+		return cached.Content, nil
+	}
+
+	if cached != nil && time.Since(cached.FetchedAt) < model.GetMetadataCacheTTL() {
+		log.Debug(fmt.Sprintf("Using the cached %s metadata fetched at %s", cacheName, cached.FetchedAt.Format(time.RFC3339)))
+		return cached.Content, nil
+	}
+
+	request.OkStatuses = []int{http.StatusOK}
+	if cached != nil && cached.ETag != "" {
+		request.Header = http.Header{"If-None-Match": []string{cached.ETag}}
+		request.OkStatuses = append(request.OkStatuses, http.StatusNotModified)
+	}
+
+	res, err := CallWorkerClient(c, server, func(ctx context.Context, client *workerclient.Client) (*workerclient.Response, error) {
+		return client.Do(ctx, request)
+	})
+	if err != nil {
+		if cached != nil && ExitCodeOf(err) == ExitCodeNetworkError {
+			log.Warn(fmt.Sprintf("The server cannot be reached, using the %s metadata cached at %s", cacheName, cached.FetchedAt.Format(time.RFC3339)))
+			return cached.Content, nil
+		}
+		return nil, err
+	}
+
+	entry := &metadataCacheEntry{ETag: res.Header.Get("ETag"), FetchedAt: time.Now(), Content: res.Body}
+	if res.StatusCode == http.StatusNotModified {
+		log.Debug(fmt.Sprintf("The cached %s metadata are up to date", cacheName))
+		entry.ETag, entry.Content = cached.ETag, cached.Content
+	}
+
+	if cacheFile != "" && json.Valid(entry.Content) {
+		writeMetadataCacheEntry(cacheFile, entry)
+	}
+
+	return entry.Content, nil
+}
+
+// getMetadataCacheFile returns <JFrog CLI home>/worker-cli/metadata/<server hash>/<name>.json
+func getMetadataCacheFile(serverURL string, name string) (string, error) {
+	homeDir, err := coreutils.GetJfrogHomeDir()
+	if err != nil {
+		return "", err
+	}
+	serverHash := sha256.Sum256([]byte(utils.AddTrailingSlashIfNeeded(serverURL)))
+	return filepath.Join(homeDir, metadataCacheDir, hex.EncodeToString(serverHash[:8]), name+".json"), nil
+}
+
+func readMetadataCacheEntry(cacheFile string) *metadataCacheEntry {
+	content, err := os.ReadFile(cacheFile)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Debug(fmt.Sprintf("Cannot read the metadata cache %s: %+v", cacheFile, err))
+		}
+		return nil
+	}
+
+	entry := new(metadataCacheEntry)
+	if err = json.Unmarshal(content, entry); err != nil || len(entry.Content) == 0 {
+		log.Debug(fmt.Sprintf("Ignoring the invalid metadata cache %s: %+v", cacheFile, err))
+		return nil
+	}
+
+	return entry
+}
+
+// writeMetadataCacheEntry stores the entry, a failure only costs a download at the next call.
+func writeMetadataCacheEntry(cacheFile string, entry *metadataCacheEntry) {
+	if err := writeFileAtomically(cacheFile, entry); err != nil {
+		log.Debug(fmt.Sprintf("Cannot write the metadata cache %s: %+v", cacheFile, err))
+	}
+}
+
+// writeFileAtomically writes through a temporary file, so that concurrent commands never read a partial file.
+func writeFileAtomically(path string, value any) error {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	_, err = tmp.Write(content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,156 @@
+//go:build test
+// +build test
+
+package common
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+)
+
+const testOptionsContent = `{"shouldEncodeSourceCodeInBase64":true}`
+
+type metadataServer struct {
+	*httptest.Server
+	calls        atomic.Int32
+	revalidated  atomic.Int32
+	notModified  atomic.Int32
+	responseBody string
+}
+
+func newMetadataServer(t *testing.T) *metadataServer {
+	s := &metadataServer{responseBody: testOptionsContent}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.calls.Add(1)
+		if r.Header.Get("If-None-Match") != "" {
+			s.revalidated.Add(1)
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			s.notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(s.responseBody))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestFetchOptions_Cache(t *testing.T) {
+	tests := []struct {
+		name            string
+		ttl             string
+		metadata        MetadataOptions
+		wantCalls       int32
+		wantRevalidated int32
+		wantNotModified int32
+	}{
+		{
+			name:      "uses the cache while it is fresh",
+			wantCalls: 1,
+		},
+		{
+			name:            "revalidates an expired cache with its ETag",
+			ttl:             "0s",
+			wantCalls:       2,
+			wantRevalidated: 1,
+			wantNotModified: 1,
+		},
+		{
+			name:      "bypasses the cache with --refresh-metadata",
+			metadata:  MetadataOptions{Refresh: true},
+			wantCalls: 2,
+		},
+		{
+			name:      "does not call the server with --offline",
+			metadata:  MetadataOptions{Offline: true},
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			TestSetEnv(t, coreutils.HomeDir, t.TempDir())
+			if tt.ttl != "" {
+				TestSetEnv(t, model.EnvKeyMetadataCacheTTL, tt.ttl)
+			}
+
+			server := newMetadataServer(t)
+
+			first, err := FetchOptions(IntFlagMap{}, TestServer(server.URL, "a-token"))
+			require.NoError(t, err)
+
+			secondServer := TestServer(server.URL, "a-token")
+			secondServer.Metadata = tt.metadata
+			second, err := FetchOptions(IntFlagMap{}, secondServer)
+			require.NoError(t, err)
+
+			assert.Equal(t, first, second)
+			assert.Equal(t, tt.wantCalls, server.calls.Load())
+			assert.Equal(t, tt.wantRevalidated, server.revalidated.Load())
+			assert.Equal(t, tt.wantNotModified, server.notModified.Load())
+		})
+	}
+}
+
+func TestFetchOptions_CacheIsPerServer(t *testing.T) {
+	TestSetEnv(t, coreutils.HomeDir, t.TempDir())
+
+	server1 := newMetadataServer(t)
+	server2 := newMetadataServer(t)
+	server2.responseBody = `{}`
+
+	options1, err := FetchOptions(IntFlagMap{}, TestServer(server1.URL, "a-token"))
+	require.NoError(t, err)
+
+	options2, err := FetchOptions(IntFlagMap{}, TestServer(server2.URL, "a-token"))
+	require.NoError(t, err)
+
+	require.NotNil(t, options1.ShouldEncodeSourceCodeInBase64)
+	assert.Nil(t, options2.ShouldEncodeSourceCodeInBase64)
+	assert.Equal(t, int32(1), server1.calls.Load())
+	assert.Equal(t, int32(1), server2.calls.Load())
+}
+
+func TestFetchOptions_Offline(t *testing.T) {
+	TestSetEnv(t, coreutils.HomeDir, t.TempDir())
+
+	server := newMetadataServer(t)
+
+	offlineServer := TestServer(server.URL, "a-token")
+	offlineServer.Metadata.Offline = true
+	_, err := FetchOptions(IntFlagMap{}, offlineServer)
+	assert.EqualError(t, err, "cannot fetch options: no cached options metadata for "+server.URL+", run the command once without --offline")
+	assert.Equal(t, int32(0), server.calls.Load())
+}
+
+func TestFetchOptions_StaleCacheOnNetworkError(t *testing.T) {
+	TestSetEnv(t, coreutils.HomeDir, t.TempDir())
+	TestSetEnv(t, model.EnvKeyMetadataCacheTTL, "0s")
+
+	server := newMetadataServer(t)
+
+	want, err := FetchOptions(IntFlagMap{}, TestServer(server.URL, "a-token"))
+	require.NoError(t, err)
+
+	server.Close()
+
+	got, err := FetchOptions(IntFlagMap{model.FlagRetries: 0}, TestServer(server.URL, "a-token"))
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	refreshServer := TestServer(server.URL, "a-token")
+	refreshServer.Metadata.Refresh = true
+	_, err = FetchOptions(IntFlagMap{model.FlagRetries: 0}, refreshServer)
+	assert.Error(t, err)
+}
@@ -0,0 +1,73 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/jfrog/jfrog-client-go/utils/log"
+
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+)
+
+// LoadActionsFile reads the actions metadata from the output of 'jf worker list-event --format json' or of 'jf worker export-metadata'.
+func LoadActionsFile(path string) (ActionsMetadata, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the actions file: %w", err)
+	}
+
+	metadata := make(ActionsMetadata, 0)
+
+	content = bytes.TrimSpace(content)
+	if bytes.HasPrefix(content, []byte("[")) {
+		if err = json.Unmarshal(content, &metadata); err != nil {
+			return nil, fmt.Errorf("invalid actions file %s: %w", path, err)
+		}
+	} else {
+		bundle := new(model.MetadataBundle)
+		if err = json.Unmarshal(content, bundle); err != nil {
+			return nil, fmt.Errorf("invalid actions file %s: %w", path, err)
+		}
+		if bundle.Actions == nil {
+			return nil, fmt.Errorf("invalid actions file %s: no actions found", path)
+		}
+		metadata = bundle.Actions
+	}
+
+	log.Debug(fmt.Sprintf("Loaded %d actions from %s", len(metadata), path))
+
+	return metadata, nil
+}
+
+// LoadOptionsFile reads the server options from the output of 'jf worker export-metadata', or from the options document returned by the server.
+func LoadOptionsFile(path string) (*model.OptionsMetadata, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the options file: %w", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(content, &fields); err != nil {
+		return nil, fmt.Errorf("invalid options file %s: %w", path, err)
+	}
+
+	if _, isBundle := fields["actions"]; isBundle {
+		bundle := new(model.MetadataBundle)
+		if err = json.Unmarshal(content, bundle); err != nil {
+			return nil, fmt.Errorf("invalid options file %s: %w", path, err)
+		}
+		if bundle.Options == nil {
+			return nil, fmt.Errorf("invalid options file %s: no options found", path)
+		}
+		return bundle.Options, nil
+	}
+
+	options := new(model.OptionsMetadata)
+	if err = json.Unmarshal(content, options); err != nil {
+		return nil, fmt.Errorf("invalid options file %s: %w", path, err)
+	}
+
+	return options, nil
+}
@@ -0,0 +1,177 @@
+package common
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strings"
+)
+
+const (
+	// Beyond this depth the objects, maps and arrays are empty, required fields included, to stop on recursive types
+	payloadGeneratorMaxDepth = 8
+	// The probability that an optional field is omitted
+	payloadGeneratorOmitRate = 0.25
+	// The probability that an array is large, a payload has at most one large array to stay small enough to be sent
+	payloadGeneratorLargeArrayRate = 0.05
+)
+
+// PayloadGeneratorLargeArraySize is the number of elements of the large arrays, which test the limits of a worker.
+var PayloadGeneratorLargeArraySize = 200
+
+// The strings are picked among these edge cases, or are random words.
+var payloadGeneratorStrings = []string{
+	"",
+	" ",
+	"a",
+	"with spaces and \"quotes\"",
+	"ünïcødé ✓ 日本",
+	"folder/sub folder/file.txt",
+	"../../etc/passwd",
+	"<script>alert(1)</script>",
+	"null",
+	strings.Repeat("x", 1024),
+}
+
+// The numbers are picked among these edge cases, or are random integers.
+var payloadGeneratorNumbers = []float64{0, 1, -1, 0.5, 1e6, math.MaxInt32, -math.MaxInt32, 1 << 53}
+
+// PayloadGenerator generates random payloads of a request type, the same seed always generates the same payloads.
+type PayloadGenerator struct {
+	types  *TSTypes
+	random *rand.Rand
+	// hasLargeArray is true once the payload being generated has a large array
+	hasLargeArray bool
+}
+
+func NewPayloadGenerator(types *TSTypes, seed int64) *PayloadGenerator {
+	return &PayloadGenerator{types: types, random: rand.New(rand.NewPCG(uint64(seed), 0))}
+}
+
+// Generate returns a random value of an interface, e.g. the ExecutionRequestType of an action.
+// The values cover the enum members (UNRECOGNIZED included), the empty strings, the missing optional fields and the large arrays.
+func (g *PayloadGenerator) Generate(typeName string) (map[string]any, error) {
+	if _, found := g.types.Interfaces[typeName]; !found {
+		return nil, fmt.Errorf("type %s not found in the types definitions", typeName)
+	}
+	g.hasLargeArray = false
+	value := g.generate(&TSType{Kind: TSReference, Name: typeName}, 0)
+	object, isObject := value.(map[string]any)
+	if !isObject {
+		return nil, fmt.Errorf("type %s is not an object", typeName)
+	}
+	return object, nil
+}
+
+func (g *PayloadGenerator) generate(t *TSType, depth int) any {
+	switch t.Kind {
+	case TSString:
+		return g.generateString()
+	case TSNumber:
+		return g.generateNumber()
+	case TSBoolean:
+		return g.random.IntN(2) == 0
+	case TSNull, TSUndefined:
+		return nil
+	case TSLiteral:
+		return t.Literal
+	case TSArray:
+		return g.generateArray(t.Element, depth)
+	case TSMap:
+		object := map[string]any{}
+		for i := 0; i < g.random.IntN(4) && depth < payloadGeneratorMaxDepth; i++ {
+			object[g.generateKey()] = g.generate(t.Element, depth+1)
+		}
+		return object
+	case TSObject:
+		object := map[string]any{}
+		if depth >= payloadGeneratorMaxDepth {
+			return object
+		}
+		for _, field := range t.Fields {
+			optional := field.Optional || field.Type.IsOptional()
+			if optional && g.random.Float64() < payloadGeneratorOmitRate {
+				continue
+			}
+			object[field.Name] = g.generate(field.Type, depth+1)
+		}
+		return object
+	case TSUnion:
+		// undefined is covered by the omitted fields
+		var variants []*TSType
+		for _, variant := range t.Variants {
+			if variant.Kind != TSUndefined {
+				variants = append(variants, variant)
+			}
+		}
+		if len(variants) == 0 {
+			return nil
+		}
+		return g.generate(variants[g.random.IntN(len(variants))], depth)
+	case TSReference:
+		if members, isEnum := g.types.Enums[t.Name]; isEnum {
+			if len(members) == 0 {
+				return nil
+			}
+			return members[g.random.IntN(len(members))].Value
+		}
+		resolved := g.types.Resolve(t)
+		if resolved == nil {
+			return g.generateScalar()
+		}
+		return g.generate(resolved, depth)
+	default:
+		return g.generateScalar()
+	}
+}
+
+func (g *PayloadGenerator) generateString() string {
+	if g.random.IntN(2) == 0 {
+		return payloadGeneratorStrings[g.random.IntN(len(payloadGeneratorStrings))]
+	}
+	return g.generateKey()
+}
+
+func (g *PayloadGenerator) generateKey() string {
+	const letters = "abcdefghijklmnopqrstuvwxyz0123456789-_"
+	word := make([]byte, 1+g.random.IntN(12))
+	for i := range word {
+		word[i] = letters[g.random.IntN(len(letters))]
+	}
+	return string(word)
+}
+
+func (g *PayloadGenerator) generateNumber() float64 {
+	if g.random.IntN(2) == 0 {
+		return payloadGeneratorNumbers[g.random.IntN(len(payloadGeneratorNumbers))]
+	}
+	return float64(g.random.IntN(100000))
+}
+
+func (g *PayloadGenerator) generateScalar() any {
+	switch g.random.IntN(3) {
+	case 0:
+		return g.generateString()
+	case 1:
+		return g.generateNumber()
+	default:
+		return g.random.IntN(2) == 0
+	}
+}
+
+func (g *PayloadGenerator) generateArray(element *TSType, depth int) []any {
+	size := 0
+	switch {
+	case depth >= payloadGeneratorMaxDepth:
+	case !g.hasLargeArray && g.random.Float64() < payloadGeneratorLargeArrayRate:
+		size, g.hasLargeArray = PayloadGeneratorLargeArraySize, true
+	default:
+		size = g.random.IntN(4)
+	}
+
+	array := make([]any, size)
+	for i := range array {
+		array[i] = g.generate(element, depth+1)
+	}
+	return array
+}
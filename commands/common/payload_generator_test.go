@@ -0,0 +1,100 @@
+//go:build test
+// +build test
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayloadGenerator(t *testing.T) {
+	actionMeta, err := LoadSampleActions(t).FindAction("BEFORE_DOWNLOAD")
+	require.NoError(t, err)
+	types, err := ParseTypesDefinitions(actionMeta.TypesDefinitions)
+	require.NoError(t, err)
+
+	generate := func(seed int64, count int) []map[string]any {
+		generator := NewPayloadGenerator(types, seed)
+		payloads := make([]map[string]any, count)
+		for i := range payloads {
+			payloads[i], err = generator.Generate(actionMeta.ExecutionRequestType)
+			require.NoError(t, err)
+		}
+		return payloads
+	}
+
+	payloads := generate(42, 200)
+	assert.Equal(t, payloads, generate(42, 200), "the same seed gives the same payloads")
+	assert.NotEqual(t, payloads, generate(43, 200))
+
+	repoTypes := map[any]bool{}
+	var missingMetadata, emptyNames, largeArrays int
+	for _, payload := range payloads {
+		assert.IsType(t, map[string]any{}, payload["headers"])
+		for _, header := range payload["headers"].(map[string]any) {
+			values := header.(map[string]any)["value"].([]any)
+			if len(values) == PayloadGeneratorLargeArraySize {
+				largeArrays++
+			}
+		}
+
+		metadata, found := payload["metadata"].(map[string]any)
+		if !found {
+			missingMetadata++
+			continue
+		}
+		repoTypes[metadata["repoType"]] = true
+		assert.IsType(t, true, metadata["headOnly"])
+		assert.IsType(t, 0.0, metadata["lastModified"])
+		if metadata["name"] == "" {
+			emptyNames++
+		}
+	}
+
+	assert.Equal(t, map[any]bool{0.0: true, 1.0: true, 2.0: true, 3.0: true, -1.0: true}, repoTypes, "every enum member is generated")
+	assert.Positive(t, missingMetadata, "the optional fields are sometimes missing")
+	assert.Positive(t, emptyNames, "the strings are sometimes empty")
+	assert.Positive(t, largeArrays, "the arrays are sometimes large")
+
+	_, err = NewPayloadGenerator(types, 1).Generate("Unknown")
+	assert.EqualError(t, err, "type Unknown not found in the types definitions")
+}
+
+func TestPayloadGenerator_RecursiveTypes(t *testing.T) {
+	types, err := ParseTypesDefinitions(`
+interface Node {
+    name: string;
+    parent?: Node;
+    children: Node[];
+}`)
+	require.NoError(t, err)
+
+	generator := NewPayloadGenerator(types, 7)
+	for i := 0; i < 50; i++ {
+		_, err = generator.Generate("Node")
+		require.NoError(t, err)
+	}
+}
+
+func TestPayloadGenerator_RequiredRecursiveField(t *testing.T) {
+	types, err := ParseTypesDefinitions(`
+interface Chain {
+    name: string;
+    next: Chain;
+}`)
+	require.NoError(t, err)
+
+	payload, err := NewPayloadGenerator(types, 3).Generate("Chain")
+	require.NoError(t, err)
+
+	depth := 0
+	for link := payload; len(link) > 0; depth++ {
+		next, isObject := link["next"].(map[string]any)
+		require.True(t, isObject, "expected an object at depth %d, got %v", depth, link["next"])
+		link = next
+	}
+	assert.Equal(t, payloadGeneratorMaxDepth, depth, "the required field is empty beyond the depth limit")
+}
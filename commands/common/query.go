@@ -0,0 +1,118 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/itchyny/gojq"
+)
+
+// Query is a jq filter applied to the JSON document printed by a command, e.g.
+//
+//	.workers[] | select(.enabled == true) | .key
+//
+// The filter is run by gojq, see https://jqlang.github.io/jq/manual for its syntax.
+// A filter yielding a single value prints it, the values of a filter yielding none or several are collected in an array.
+type Query struct {
+	source string
+	code   *gojq.Code
+}
+
+// ParseQuery compiles a query, the errors tell the invalid token.
+func ParseQuery(source string) (*Query, error) {
+	parsed, err := gojq.Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query '%s': %w", source, err)
+	}
+
+	code, err := gojq.Compile(parsed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query '%s': %w", source, err)
+	}
+
+	return &Query{source: source, code: code}, nil
+}
+
+func (q *Query) String() string {
+	return q.source
+}
+
+// Apply runs the query on a value, which is first converted to its JSON representation.
+func (q *Query) Apply(value any) (any, error) {
+	document, err := toJSONDocument(value)
+	if err != nil {
+		return nil, err
+	}
+
+	values := []any{}
+	iter := q.code.Run(document)
+	for {
+		output, hasNext := iter.Next()
+		if !hasNext {
+			break
+		}
+		if err, isErr := output.(error); isErr {
+			var haltErr *gojq.HaltError
+			if errors.As(err, &haltErr) && haltErr.Value() == nil {
+				break
+			}
+			return nil, fmt.Errorf("query '%s' failed: %w", q.source, err)
+		}
+		values = append(values, output)
+	}
+
+	if len(values) == 1 {
+		return values[0], nil
+	}
+	return values, nil
+}
+
+// toJSONDocument converts a value to the maps, slices and scalars of its JSON representation.
+func toJSONDocument(value any) (any, error) {
+	var content []byte
+	switch v := value.(type) {
+	case json.RawMessage:
+		content = v
+	case []byte:
+		content = v
+	default:
+		var err error
+		if content, err = json.Marshal(value); err != nil {
+			return nil, err
+		}
+	}
+
+	var document any
+	if err := json.Unmarshal(content, &document); err != nil {
+		return nil, fmt.Errorf("the output is not a JSON document: %w", err)
+	}
+	return document, nil
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func jsonTypeName(value any) string {
+	switch value.(type) {
+	case map[string]any:
+		return "an object"
+	case []any:
+		return "an array"
+	case string:
+		return "a string"
+	case float64:
+		return "a number"
+	case bool:
+		return "a boolean"
+	default:
+		return "null"
+	}
+}
@@ -0,0 +1,73 @@
+//go:build test
+// +build test
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuery(t *testing.T) {
+	document := map[string]any{
+		"workers": []any{
+			map[string]any{"key": "wk-1", "enabled": true, "secrets": []any{"a", "b"}, "filter": map[string]any{"repo keys": []any{"r1"}}},
+			map[string]any{"key": "wk-2", "enabled": false, "secrets": []any{}},
+			map[string]any{"key": "wk-3", "enabled": true, "debug": true},
+		},
+		"count": 3,
+	}
+
+	tests := []struct {
+		query   string
+		want    any
+		wantErr string
+	}{
+		{query: ".", want: map[string]any{"workers": []any{
+			map[string]any{"key": "wk-1", "enabled": true, "secrets": []any{"a", "b"}, "filter": map[string]any{"repo keys": []any{"r1"}}},
+			map[string]any{"key": "wk-2", "enabled": false, "secrets": []any{}},
+			map[string]any{"key": "wk-3", "enabled": true, "debug": true},
+		}, "count": float64(3)}},
+		{query: ".count", want: float64(3)},
+		{query: ".missing", want: nil},
+		{query: ".workers[0].key", want: "wk-1"},
+		{query: ".workers[-1].key", want: "wk-3"},
+		{query: ".workers[10]", want: nil},
+		{query: ".workers[].key", want: []any{"wk-1", "wk-2", "wk-3"}},
+		{query: "[.workers[].key]", want: []any{"wk-1", "wk-2", "wk-3"}},
+		{query: `.workers[0].filter."repo keys"[0]`, want: "r1"},
+		{query: `.workers[0]["filter"]["repo keys"]`, want: []any{"r1"}},
+		{query: ".workers[] | select(.enabled == true) | .key", want: []any{"wk-1", "wk-3"}},
+		{query: `.workers[] | select(.key != "wk-1" and .enabled) | .key`, want: "wk-3"},
+		{query: `[.workers[] | select(.key != "wk-1" and .enabled) | .key]`, want: []any{"wk-3"}},
+		{query: `.workers[] | select(.debug or .key == "wk-2") | .key`, want: []any{"wk-2", "wk-3"}},
+		{query: `[.workers[] | select(.secrets | length > 0) | .key]`, want: []any{"wk-1"}},
+		{query: ".workers | length", want: 3},
+		{query: ".workers[0] | keys", want: []any{"enabled", "filter", "key", "secrets"}},
+		{query: ".workers[] | select(.key == \"none\")", want: []any{}},
+		{query: "first(.workers[].key), halt", want: "wk-1"},
+		{query: ".count.key", wantErr: `query '.count.key' failed: expected an object but got: number (3)`},
+		{query: ".count[]", wantErr: "query '.count[]' failed: cannot iterate over: number (3)"},
+		{query: "workers", wantErr: "invalid query 'workers': function not defined: workers/0"},
+		{query: ".workers[", wantErr: "invalid query '.workers[': unexpected EOF"},
+		{query: ".workers |", wantErr: "invalid query '.workers |': unexpected EOF"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			query, err := ParseQuery(tt.query)
+			if err == nil {
+				var got any
+				got, err = query.Apply(document)
+				if tt.wantErr == "" {
+					require.NoError(t, err)
+					assert.Equal(t, tt.want, got)
+					return
+				}
+			}
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
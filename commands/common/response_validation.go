@@ -0,0 +1,218 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/utils/log"
+
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+)
+
+// The enum member that protobuf adds for the values it cannot map, a worker must never return it.
+const unrecognizedEnumMember = "UNRECOGNIZED"
+
+// TypeViolation is a value of a response that does not match its declared type.
+type TypeViolation struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (v *TypeViolation) String() string {
+	return v.Path + ": " + v.Message
+}
+
+// ResponseValidator checks the values returned by the workers of an action against its response type, e.g. BeforeDownloadResponse.
+type ResponseValidator struct {
+	TypeName string
+	types    *TSTypes
+}
+
+// NewResponseValidator returns the validator of the response type of an action, nil when the action does not declare one.
+// The response type is the one named after the request type, e.g. BeforeDownloadResponse for BeforeDownloadRequest.
+func NewResponseValidator(actionMeta *model.ActionMetadata) (*ResponseValidator, error) {
+	typeName, isRequest := strings.CutSuffix(actionMeta.ExecutionRequestType, "Request")
+	if !isRequest || actionMeta.TypesDefinitions == "" {
+		return nil, nil
+	}
+	typeName += "Response"
+
+	types, err := ParseTypesDefinitions(actionMeta.TypesDefinitions)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the types of %s: %w", actionMeta.Action.Name, err)
+	}
+
+	if _, found := types.Interfaces[typeName]; !found {
+		return nil, nil
+	}
+
+	return &ResponseValidator{TypeName: typeName, types: types}, nil
+}
+
+// Validate returns the violations of a value returned by a worker, the paths start with data.
+func (v *ResponseValidator) Validate(value any) []*TypeViolation {
+	var violations []*TypeViolation
+	v.validate(&TSType{Kind: TSReference, Name: v.TypeName}, executionFieldData, value, &violations)
+	return violations
+}
+
+// ValidateExecutionResponse checks the data of a successful execution.
+// The failed executions and the responses that are not executions are not checked, the worker did not return a value.
+func (v *ResponseValidator) ValidateExecutionResponse(response json.RawMessage) []*TypeViolation {
+	result, isExecution := ParseExecutionResponse(response, time.Duration(0))
+	if !isExecution || result.Failed() {
+		return nil
+	}
+	return v.Validate(result.Data)
+}
+
+// Report logs the violations as warnings, and fails with strict.
+func (v *ResponseValidator) Report(violations []*TypeViolation, strict bool) error {
+	if len(violations) == 0 {
+		return nil
+	}
+
+	for _, violation := range violations {
+		log.Warn(fmt.Sprintf("The response does not match %s, %s", v.TypeName, violation))
+	}
+
+	if strict {
+		return ErrorAlreadyReported(fmt.Errorf("the response does not match %s: %d violations", v.TypeName, len(violations)))
+	}
+	return nil
+}
+
+func (v *ResponseValidator) validate(t *TSType, path string, value any, violations *[]*TypeViolation) {
+	violate := func(message string, args ...any) {
+		*violations = append(*violations, &TypeViolation{Path: path, Message: fmt.Sprintf(message, args...)})
+	}
+
+	switch t.Kind {
+	case TSString, TSNumber, TSBoolean, TSNull, TSUndefined:
+		if !matchesScalarType(t.Kind, value) {
+			violate("expected %s, got %s", t, describeJSONValue(value))
+		}
+	case TSLiteral:
+		if !reflect.DeepEqual(t.Literal, value) {
+			violate("expected %s, got %s", t, describeJSONValue(value))
+		}
+	case TSArray:
+		array, isArray := value.([]any)
+		if !isArray {
+			violate("expected %s, got %s", t, describeJSONValue(value))
+			return
+		}
+		for i, element := range array {
+			v.validate(t.Element, fmt.Sprintf("%s[%d]", path, i), element, violations)
+		}
+	case TSMap:
+		object, isObject := value.(map[string]any)
+		if !isObject {
+			violate("expected an object, got %s", describeJSONValue(value))
+			return
+		}
+		for key, element := range object {
+			v.validate(t.Element, jsonPathKey(path, key), element, violations)
+		}
+	case TSObject:
+		object, isObject := value.(map[string]any)
+		if !isObject {
+			violate("expected an object, got %s", describeJSONValue(value))
+			return
+		}
+		for _, field := range t.Fields {
+			fieldValue, found := object[field.Name]
+			switch {
+			case found:
+				v.validate(field.Type, jsonPathKey(path, field.Name), fieldValue, violations)
+			case !field.Optional && !field.Type.IsOptional():
+				*violations = append(*violations, &TypeViolation{Path: jsonPathKey(path, field.Name), Message: "missing field of type " + field.Type.String()})
+			}
+		}
+	case TSUnion:
+		for _, variant := range t.Variants {
+			var variantViolations []*TypeViolation
+			v.validate(variant, path, value, &variantViolations)
+			if len(variantViolations) == 0 {
+				return
+			}
+		}
+		if len(t.Variants) == 1 {
+			v.validate(t.Variants[0], path, value, violations)
+			return
+		}
+		violate("expected %s, got %s", t, describeJSONValue(value))
+	case TSReference:
+		if members, isEnum := v.types.Enums[t.Name]; isEnum {
+			v.validateEnum(t.Name, members, path, value, violations)
+			return
+		}
+		if resolved := v.types.Resolve(t); resolved != nil {
+			v.validate(resolved, path, value, violations)
+		}
+	}
+}
+
+// validateEnum accepts the values and the names of the members, except UNRECOGNIZED.
+func (v *ResponseValidator) validateEnum(name string, members []*TSEnumMember, path string, value any, violations *[]*TypeViolation) {
+	var valid []string
+	for _, member := range members {
+		if member.Name == unrecognizedEnumMember {
+			if reflect.DeepEqual(member.Value, value) || value == member.Name {
+				*violations = append(*violations, &TypeViolation{Path: path, Message: fmt.Sprintf("%s is not a valid %s", unrecognizedEnumMember, name)})
+				return
+			}
+			continue
+		}
+		if reflect.DeepEqual(member.Value, value) || value == member.Name {
+			return
+		}
+		valid = append(valid, fmt.Sprintf("%s (%s)", FormatCell(member.Value), member.Name))
+	}
+	*violations = append(*violations, &TypeViolation{Path: path, Message: fmt.Sprintf("%s is not a valid %s, expected one of %s", describeJSONValue(value), name, strings.Join(valid, ", "))})
+}
+
+func matchesScalarType(kind TSKind, value any) bool {
+	switch kind {
+	case TSString:
+		_, matches := value.(string)
+		return matches
+	case TSNumber:
+		_, matches := value.(float64)
+		return matches
+	case TSBoolean:
+		_, matches := value.(bool)
+		return matches
+	default:
+		// undefined values are dropped by JSON, a null is the closest
+		return value == nil
+	}
+}
+
+// describeJSONValue returns a short description of a decoded JSON value for the messages, e.g. the string "a" or an array.
+func describeJSONValue(value any) string {
+	switch typed := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "the string " + truncateText(fmt.Sprintf("%q", typed), 40)
+	case float64, bool:
+		return FormatCell(typed)
+	case []any:
+		return "an array"
+	case map[string]any:
+		return "an object"
+	default:
+		return fmt.Sprintf("%v", typed)
+	}
+}
+
+func truncateText(text string, size int) string {
+	if len(text) <= size {
+		return text
+	}
+	return text[:size-3] + "..."
+}
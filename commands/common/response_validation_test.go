@@ -0,0 +1,145 @@
+//go:build test
+// +build test
+
+package common
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+)
+
+func TestResponseValidator(t *testing.T) {
+	validator, err := NewResponseValidator(&model.ActionMetadata{
+		ExecutionRequestType: "CheckRequest",
+		TypesDefinitions: `
+interface CheckResponse {
+    status: CheckStatus;
+    message: string;
+    details?: Details;
+    tags: string[];
+    counts: { [key: string]: number };
+    mode: 'fast' | 'slow' | undefined;
+}
+
+interface Details {
+    size: number | null;
+    valid: boolean;
+}
+
+enum CheckStatus {
+    CHECK_UNSPECIFIED = 0,
+    CHECK_PROCEED = 1,
+    CHECK_STOP = 2,
+    UNRECOGNIZED = -1,
+}`,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, validator)
+	assert.Equal(t, "CheckResponse", validator.TypeName)
+
+	tests := []struct {
+		name  string
+		value string
+		want  []string
+	}{
+		{
+			name:  "valid",
+			value: `{"status":1,"message":"ok","tags":[],"counts":{"a":1},"mode":"fast","details":{"size":null,"valid":true}}`,
+		},
+		{
+			name:  "enum name and omitted optionals",
+			value: `{"status":"CHECK_STOP","message":"","tags":["a"],"counts":{}}`,
+		},
+		{
+			name:  "missing fields",
+			value: `{"status":1}`,
+			want: []string{
+				"data.message: missing field of type string",
+				"data.tags: missing field of type string[]",
+				"data.counts: missing field of type { [key: string]: number }",
+			},
+		},
+		{
+			name:  "wrong types",
+			value: `{"status":1,"message":42,"tags":"a","counts":{"a":"1"},"mode":"medium","details":{"size":"big","valid":true}}`,
+			want: []string{
+				"data.message: expected string, got 42",
+				"data.details.size: expected number | null, got the string \"big\"",
+				`data.tags: expected string[], got the string "a"`,
+				`data.counts.a: expected number, got the string "1"`,
+				`data.mode: expected "fast" | "slow" | undefined, got the string "medium"`,
+			},
+		},
+		{
+			name:  "unrecognized enum value",
+			value: `{"status":-1,"message":"","tags":[],"counts":{}}`,
+			want:  []string{"data.status: UNRECOGNIZED is not a valid CheckStatus"},
+		},
+		{
+			name:  "out of range enum value",
+			value: `{"status":7,"message":"","tags":[],"counts":{}}`,
+			want:  []string{"data.status: 7 is not a valid CheckStatus, expected one of 0 (CHECK_UNSPECIFIED), 1 (CHECK_PROCEED), 2 (CHECK_STOP)"},
+		},
+		{
+			name:  "not an object",
+			value: `"done"`,
+			want:  []string{`data: expected an object, got the string "done"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value any
+			require.NoError(t, json.Unmarshal([]byte(tt.value), &value))
+
+			var got []string
+			for _, violation := range validator.Validate(value) {
+				got = append(got, violation.String())
+			}
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func TestResponseValidator_ExecutionResponse(t *testing.T) {
+	actionMeta, err := LoadSampleActions(t).FindAction("BEFORE_DOWNLOAD")
+	require.NoError(t, err)
+	validator, err := NewResponseValidator(actionMeta)
+	require.NoError(t, err)
+	require.NotNil(t, validator)
+	assert.Equal(t, "BeforeDownloadResponse", validator.TypeName)
+
+	assert.Empty(t, validator.ValidateExecutionResponse(json.RawMessage(`{"beforeDownload":{"data":{"status":1,"message":"ok"},"executionStatus":"STATUS_SUCCESS"}}`)))
+	assert.Empty(t, validator.ValidateExecutionResponse(json.RawMessage(`{"data":null,"executionStatus":"STATUS_FAIL"}`)), "the failed executions are not checked")
+	assert.Len(t, validator.ValidateExecutionResponse(json.RawMessage(`{"data":{"status":-1},"executionStatus":"STATUS_SUCCESS"}`)), 2)
+
+	err = validator.Report([]*TypeViolation{{Path: "data.status", Message: "UNRECOGNIZED is not a valid DownloadStatus"}}, true)
+	assert.EqualError(t, err, "the response does not match BeforeDownloadResponse: 1 violations")
+	assert.NoError(t, validator.Report([]*TypeViolation{{Path: "data", Message: "x"}}, false))
+
+	generic, err := LoadSampleActions(t).FindAction("GENERIC_EVENT")
+	require.NoError(t, err)
+	validator, err = NewResponseValidator(generic)
+	require.NoError(t, err)
+	assert.Nil(t, validator)
+}
+
+func TestResponseValidator_Strict(t *testing.T) {
+	actionMeta, err := LoadSampleActions(t).FindAction("BEFORE_DOWNLOAD")
+	require.NoError(t, err)
+	validator, err := NewResponseValidator(actionMeta)
+	require.NoError(t, err)
+	require.NotNil(t, validator)
+
+	invalid := validator.ValidateExecutionResponse(json.RawMessage(`{"beforeDownload":{"data":{"status":-1},"executionStatus":"STATUS_SUCCESS"}}`))
+	assert.NoError(t, validator.Report(invalid, false), "the violations are only warnings without strict")
+	assert.EqualError(t, validator.Report(invalid, true), "the response does not match BeforeDownloadResponse: 2 violations")
+
+	valid := validator.ValidateExecutionResponse(json.RawMessage(`{"beforeDownload":{"data":{"status":1,"message":"ok"},"executionStatus":"STATUS_SUCCESS"}}`))
+	assert.NoError(t, validator.Report(valid, true))
+}
@@ -0,0 +1,138 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+)
+
+// SamplePayloadFile is the payload written by init, next to the manifest, so that a new worker can be test-run right away.
+var SamplePayloadFile = filepath.Join("payloads", "sample.json")
+
+// SamplePayload returns the sample payload of an action, an empty object when the server does not provide one.
+func SamplePayload(actionMeta *model.ActionMetadata) (map[string]any, error) {
+	payload := map[string]any{}
+	if strings.TrimSpace(actionMeta.SamplePayload) == "" {
+		return payload, nil
+	}
+	if err := json.Unmarshal([]byte(actionMeta.SamplePayload), &payload); err != nil {
+		return nil, fmt.Errorf("the sample payload of %s is not a valid JSON object: %w", actionMeta.Action.Name, err)
+	}
+	return payload, nil
+}
+
+// SetPayloadValue applies a path=value override to a payload, e.g. metadata.repoPath.key=libs-release or items[0].size=10.
+// The value is read as JSON when it is valid JSON, else as a string. The missing objects of the path are created,
+// and an array element can be appended by using the length of the array as index.
+func SetPayloadValue(payload map[string]any, expression string) error {
+	path, rawValue, found := strings.Cut(expression, "=")
+	if !found || strings.TrimSpace(path) == "" {
+		return fmt.Errorf("invalid override '%s', expected <path>=<value>", expression)
+	}
+
+	segments, err := parseLiteralJSONPath(path)
+	if err != nil {
+		return err
+	}
+
+	var value any = rawValue
+	var jsonValue any
+	if err = json.Unmarshal([]byte(rawValue), &jsonValue); err == nil {
+		value = jsonValue
+	}
+
+	if _, err = setJSONPathValue(payload, segments, value); err != nil {
+		return fmt.Errorf("cannot set '%s': %w", path, err)
+	}
+	return nil
+}
+
+// parseLiteralJSONPath reads a path without wildcards.
+func parseLiteralJSONPath(source string) ([]jsonPathSegment, error) {
+	pattern, err := ParseJSONPathPattern(source)
+	if err != nil {
+		return nil, err
+	}
+
+	segments := make([]jsonPathSegment, len(pattern.segments))
+	for i, segment := range pattern.segments {
+		switch {
+		case segment == "*" || segment == "**" || segment == "[*]":
+			return nil, fmt.Errorf("invalid path '%s': wildcards cannot be used", source)
+		case strings.HasPrefix(segment, "[") && strings.HasSuffix(segment, "]"):
+			index, err := strconv.Atoi(segment[1 : len(segment)-1])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid path '%s': invalid index %s", source, segment)
+			}
+			segments[i] = jsonPathSegment{index: index, isIndex: true}
+		default:
+			segments[i] = jsonPathSegment{key: segment}
+		}
+	}
+	return segments, nil
+}
+
+func setJSONPathValue(container any, segments []jsonPathSegment, value any) (any, error) {
+	if len(segments) == 0 {
+		return value, nil
+	}
+
+	segment := segments[0]
+	if segment.isIndex {
+		if container == nil {
+			container = []any{}
+		}
+		array, isArray := container.([]any)
+		if !isArray {
+			return nil, fmt.Errorf("%s is applied to %s", segment.indexString(), jsonTypeName(container))
+		}
+		if segment.index > len(array) {
+			return nil, fmt.Errorf("index %d is out of range, the array has %d elements", segment.index, len(array))
+		}
+		if segment.index == len(array) {
+			array = append(array, nil)
+		}
+		element, err := setJSONPathValue(array[segment.index], segments[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		array[segment.index] = element
+		return array, nil
+	}
+
+	if container == nil {
+		container = map[string]any{}
+	}
+	object, isObject := container.(map[string]any)
+	if !isObject {
+		return nil, fmt.Errorf("field '%s' is applied to %s", segment.key, jsonTypeName(container))
+	}
+	field, err := setJSONPathValue(object[segment.key], segments[1:], value)
+	if err != nil {
+		return nil, err
+	}
+	object[segment.key] = field
+	return object, nil
+}
+
+// WriteSamplePayload writes a payload as indented JSON, creating its directory.
+func WriteSamplePayload(file string, payload map[string]any) error {
+	content, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return fmt.Errorf("cannot write the sample payload: %w", err)
+	}
+
+	if err = os.WriteFile(file, append(content, '\n'), 0o644); err != nil {
+		return fmt.Errorf("cannot write the sample payload: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+//go:build test
+// +build test
+
+package common
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+)
+
+func TestSetPayloadValue(t *testing.T) {
+	tests := []struct {
+		name       string
+		payload    string
+		expression string
+		want       string
+		wantErr    string
+	}{
+		{
+			name:       "string",
+			payload:    `{"metadata":{"repoPath":{"key":"local-repo","path":"a"}}}`,
+			expression: "metadata.repoPath.key=libs-release",
+			want:       `{"metadata":{"repoPath":{"key":"libs-release","path":"a"}}}`,
+		},
+		{
+			name:       "json values",
+			payload:    `{}`,
+			expression: `a={"b":[1,true,null]}`,
+			want:       `{"a":{"b":[1,true,null]}}`,
+		},
+		{
+			name:       "quoted string",
+			payload:    `{"a":false}`,
+			expression: `a="true"`,
+			want:       `{"a":"true"}`,
+		},
+		{
+			name:       "value with equal signs",
+			payload:    `{}`,
+			expression: `query=a=b`,
+			want:       `{"query":"a=b"}`,
+		},
+		{
+			name:       "missing objects are created",
+			payload:    `{"a":1}`,
+			expression: `b.c["d.e"]=2`,
+			want:       `{"a":1,"b":{"c":{"d.e":2}}}`,
+		},
+		{
+			name:       "array element",
+			payload:    `{"items":[{"size":1},{"size":2}]}`,
+			expression: `items[1].size=3`,
+			want:       `{"items":[{"size":1},{"size":3}]}`,
+		},
+		{
+			name:       "appended array element",
+			payload:    `{"items":["a"]}`,
+			expression: `items[1]=b`,
+			want:       `{"items":["a","b"]}`,
+		},
+		{
+			name:       "index out of range",
+			payload:    `{"items":["a"]}`,
+			expression: `items[2]=b`,
+			wantErr:    "cannot set 'items[2]': index 2 is out of range, the array has 1 elements",
+		},
+		{
+			name:       "field of a scalar",
+			payload:    `{"a":"text"}`,
+			expression: `a.b=1`,
+			wantErr:    "cannot set 'a.b': field 'b' is applied to a string",
+		},
+		{
+			name:       "index of an object",
+			payload:    `{"a":{}}`,
+			expression: `a[0]=1`,
+			wantErr:    "cannot set 'a[0]': [0] is applied to an object",
+		},
+		{
+			name:       "wildcard",
+			payload:    `{}`,
+			expression: `a[*]=1`,
+			wantErr:    "invalid path 'a[*]': wildcards cannot be used",
+		},
+		{
+			name:       "missing value",
+			payload:    `{}`,
+			expression: `a.b`,
+			wantErr:    "invalid override 'a.b', expected <path>=<value>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload map[string]any
+			require.NoError(t, json.Unmarshal([]byte(tt.payload), &payload))
+
+			err := SetPayloadValue(payload, tt.expression)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, MustJsonMarshal(t, payload))
+		})
+	}
+}
+
+func TestSamplePayload(t *testing.T) {
+	payload, err := SamplePayload(&model.ActionMetadata{SamplePayload: `{"a":1}`})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"a": 1.0}, payload)
+
+	payload, err = SamplePayload(&model.ActionMetadata{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{}, payload)
+
+	_, err = SamplePayload(&model.ActionMetadata{Action: model.Action{Name: "BEFORE_UPLOAD"}, SamplePayload: `[]`})
+	assert.ErrorContains(t, err, "the sample payload of BEFORE_UPLOAD is not a valid JSON object")
+}
@@ -17,12 +17,18 @@ import (
 
 	"github.com/jfrog/jfrog-cli-core/v2/plugins"
 	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-cli-platform-services/model"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// TestServer returns the details of a server called with the default HTTP client.
+func TestServer(serverURL string, token string) *Server {
+	return &Server{ServerDetails: &config.ServerDetails{Url: serverURL, AccessToken: token}}
+}
+
 type Test interface {
 	require.TestingT
 	Cleanup(func())
@@ -37,6 +43,16 @@ func SetCliIn(reader io.Reader) {
 	cliIn = reader
 }
 
+// SetInteractiveInput simulates a user answering the prompts with input.
+func SetInteractiveInput(t *testing.T, input string) {
+	previousIn, previousIsInteractive := cliIn, isInteractive
+	cliIn = strings.NewReader(input)
+	isInteractive = func() bool { return true }
+	t.Cleanup(func() {
+		cliIn, isInteractive = previousIn, previousIsInteractive
+	})
+}
+
 func SetCliOut(writer io.Writer) {
 	cliOut = writer
 }
@@ -187,12 +203,12 @@ func LoadSampleActionEvents(t require.TestingT) []string {
 	return events
 }
 
-func LoadSampleOptions(t require.TestingT) *OptionsMetadata {
-	// var metadata OptionsMetadata
+func LoadSampleOptions(t require.TestingT) *model.OptionsMetadata {
+	// var metadata model.OptionsMetadata
 
 	content, err := sampleFiles.ReadFile("testdata/options.json")
 	require.NoError(t, err)
-	options := &OptionsMetadata{}
+	options := &model.OptionsMetadata{}
 	err = json.Unmarshal(content, options)
 	require.NoError(t, err)
 
@@ -9,12 +9,15 @@ import (
 	"net/http"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jfrog/go-mockhttp"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-cli-platform-services/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -74,6 +77,8 @@ func NewMockWorkerServer(t *testing.T, stubs ...*ServerStub) (*mockhttp.Server,
 	TestSetEnv(t, model.EnvKeyServerURL, server.BaseUrl())
 	TestSetEnv(t, model.EnvKeyAccessToken, token)
 	TestSetEnv(t, model.EnvKeySecretsPassword, SecretPassword)
+	// Each test gets its own metadata cache
+	TestSetEnv(t, coreutils.HomeDir, t.TempDir())
 
 	t.Cleanup(server.Close)
 
@@ -106,9 +111,25 @@ func NewServerStub(t *testing.T) *ServerStub {
 		workers:          map[string]*model.WorkerDetails{},
 		queryParams:      map[string]queryParamStub{},
 		executionHistory: map[string]ExecutionHistoryStub{},
+		historySequences: map[string]*historySequence{},
 	}
 }
 
+// historySequence is the history of a worker changing from one request to the next, the last history is returned once they are all returned.
+type historySequence struct {
+	mu        sync.Mutex
+	histories []ExecutionHistoryStub
+	requests  int
+}
+
+func (h *historySequence) next() ExecutionHistoryStub {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	history := h.histories[min(h.requests, len(h.histories)-1)]
+	h.requests++
+	return history
+}
+
 type ServerStub struct {
 	test               *testing.T
 	waitFor            time.Duration
@@ -116,9 +137,13 @@ type ServerStub struct {
 	projectKey         *queryParamStub
 	workers            map[string]*model.WorkerDetails
 	executionHistory   map[string]ExecutionHistoryStub
+	historySequences   map[string]*historySequence
+	historyPagination  bool
+	statefulWorkers    bool
 	endpoints          []mockhttp.ServerEndpoint
 	queryParams        map[string]queryParamStub
 	optionsForceBase64 bool
+	options            *model.OptionsMetadata
 }
 
 func (s *ServerStub) WithT(t *testing.T) *ServerStub {
@@ -156,11 +181,46 @@ func (s *ServerStub) WithWorkers(workers ...*model.WorkerDetails) *ServerStub {
 	return s
 }
 
+// WithStatefulWorkers makes the stub manage the workers like the server does: they are listed per project, and a deleted worker is removed.
+func (s *ServerStub) WithStatefulWorkers() *ServerStub {
+	s.statefulWorkers = true
+	return s
+}
+
+// GetWorker returns the worker currently stored by the stub under the given key, or nil.
+func (s *ServerStub) GetWorker(workerKey string) *model.WorkerDetails {
+	return s.workers[workerKey]
+}
+
 func (s *ServerStub) WithWorkerExecutionHistory(workerKey string, history ExecutionHistoryStub) *ServerStub {
 	s.executionHistory[workerKey] = history
 	return s
 }
 
+// WithWorkerExecutionHistorySequence makes the execution history of a worker evolve, e.g. an execution running then completed:
+// each request returns the next history, and the last history once they were all returned.
+func (s *ServerStub) WithWorkerExecutionHistorySequence(workerKey string, histories ...ExecutionHistoryStub) *ServerStub {
+	s.historySequences[workerKey] = &historySequence{histories: histories}
+	return s
+}
+
+// WithExecutionHistoryPagination makes the history endpoint honor the limit and offset query parameters, like the servers paginating it.
+func (s *ServerStub) WithExecutionHistoryPagination() *ServerStub {
+	s.historyPagination = true
+	return s
+}
+
+// ExecutionHistoryRequests returns the number of requests of the execution history of a worker, when it is a sequence.
+func (s *ServerStub) ExecutionHistoryRequests(workerKey string) int {
+	sequence, found := s.historySequences[workerKey]
+	if !found {
+		return 0
+	}
+	sequence.mu.Lock()
+	defer sequence.mu.Unlock()
+	return sequence.requests
+}
+
 func (s *ServerStub) WithCreateEndpoint(validateBody BodyValidator) *ServerStub {
 	s.endpoints = append(s.endpoints,
 		mockhttp.NewServerEndpoint().
@@ -300,6 +360,40 @@ func (s *ServerStub) WithBase64OptionsEndpoint() *ServerStub {
 	return s.WithOptionsEndpoint()
 }
 
+// WithCustomOptionsEndpoint serves the sample options, modified by patch.
+func (s *ServerStub) WithCustomOptionsEndpoint(patch func(options *model.OptionsMetadata)) *ServerStub {
+	s.options = LoadSampleOptions(s.test)
+	patch(s.options)
+	return s.WithOptionsEndpoint()
+}
+
+func (s *ServerStub) WithArtifactoryVersionEndpoint(version string) *ServerStub {
+	s.endpoints = append(s.endpoints,
+		mockhttp.NewServerEndpoint().
+			When(
+				mockhttp.Request().GET("/artifactory/api/system/version"),
+			).
+			HandleWith(s.handle(http.StatusOK, nil, map[string]string{"version": version})),
+	)
+	return s
+}
+
+// WithProjectsEndpoint serves the list of the projects of the platform.
+func (s *ServerStub) WithProjectsEndpoint(projectKeys ...string) *ServerStub {
+	projects := make([]*model.Project, 0, len(projectKeys))
+	for _, projectKey := range projectKeys {
+		projects = append(projects, &model.Project{ProjectKey: projectKey, DisplayName: projectKey})
+	}
+	s.endpoints = append(s.endpoints,
+		mockhttp.NewServerEndpoint().
+			When(
+				mockhttp.Request().GET("/access/api/v1/projects"),
+			).
+			HandleWith(s.handle(http.StatusOK, nil, projects)),
+	)
+	return s
+}
+
 func (s *ServerStub) handleGetAll(res http.ResponseWriter, req *http.Request) {
 	s.applyDelay()
 
@@ -318,9 +412,14 @@ func (s *ServerStub) handleGetAll(res http.ResponseWriter, req *http.Request) {
 	res.WriteHeader(http.StatusOK)
 
 	action := req.URL.Query().Get("action")
+	projectKey := req.URL.Query().Get("projectKey")
 
 	workers := make([]*model.WorkerDetails, 0, len(s.workers))
 	for _, worker := range s.workers {
+		// Like the server, the workers are listed per project, the global workers have no project key
+		if s.statefulWorkers && worker.ProjectKey != projectKey {
+			continue
+		}
 		if action == "" || worker.Action == action {
 			workers = append(workers, worker)
 		}
@@ -380,6 +479,9 @@ func (s *ServerStub) handleGetExecutionHistory(res http.ResponseWriter, req *htt
 	workerKey := req.URL.Query().Get("workerKey")
 
 	executionHistory, hasHistory := s.executionHistory[workerKey]
+	if sequence, isSequence := s.historySequences[workerKey]; isSequence {
+		executionHistory, hasHistory = sequence.next(), true
+	}
 	if !hasHistory {
 		executionHistory = ExecutionHistoryStub{}
 	}
@@ -398,6 +500,14 @@ func (s *ServerStub) handleGetExecutionHistory(res http.ResponseWriter, req *htt
 	}
 	executionHistory = newHistory
 
+	if s.historyPagination {
+		offset, _ := strconv.Atoi(req.URL.Query().Get("offset"))
+		executionHistory = executionHistory[min(offset, len(executionHistory)):]
+		if limit, _ := strconv.Atoi(req.URL.Query().Get("limit")); limit > 0 && limit < len(executionHistory) {
+			executionHistory = executionHistory[:limit]
+		}
+	}
+
 	res.Header().Set("Content-Type", "application/json")
 
 	_, err := res.Write([]byte(MustJsonMarshal(s.test, executionHistory)))
@@ -428,6 +538,10 @@ func (s *ServerStub) handleDelete(res http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	if s.statefulWorkers {
+		delete(s.workers, workerKey)
+	}
+
 	res.WriteHeader(http.StatusNoContent)
 }
 
@@ -499,6 +613,9 @@ func (s *ServerStub) handleGetOptions(res http.ResponseWriter, req *http.Request
 	res.WriteHeader(http.StatusOK)
 
 	options := LoadSampleOptions(s.test)
+	if s.options != nil {
+		options = s.options
+	}
 	if s.optionsForceBase64 {
 		forcedBase64 := true
 		options.ShouldEncodeSourceCodeInBase64 = &forcedBase64
@@ -507,6 +624,9 @@ func (s *ServerStub) handleGetOptions(res http.ResponseWriter, req *http.Request
 	require.NoError(s.test, err)
 }
 
+// ResponseBodyFunc computes the response of an endpoint from the body of the request, e.g. to answer a payload with a value depending on it.
+type ResponseBodyFunc func(t *testing.T, requestBody []byte) any
+
 func (s *ServerStub) handle(status int, validateBody BodyValidator, responseBody any) http.HandlerFunc {
 	return func(res http.ResponseWriter, req *http.Request) {
 		s.applyDelay()
@@ -519,17 +639,27 @@ func (s *ServerStub) handle(status int, validateBody BodyValidator, responseBody
 			return
 		}
 
-		if validateBody != nil {
-			content, err := io.ReadAll(req.Body)
+		var content []byte
+		if validateBody != nil || responseBody != nil {
+			var err error
+			content, err = io.ReadAll(req.Body)
 			require.NoError(s.test, err)
+		}
+
+		if validateBody != nil {
 			validateBody(s.test, content)
 		}
 
+		body := responseBody
+		if responseFunc, isFunc := responseBody.(ResponseBodyFunc); isFunc {
+			body = responseFunc(s.test, content)
+		}
+
 		res.WriteHeader(status)
 
-		if responseBody != nil {
+		if body != nil {
 			res.Header().Set("Content-Type", "application/json")
-			response, err := json.Marshal(responseBody)
+			response, err := json.Marshal(body)
 			require.NoError(s.test, err)
 			_, err = res.Write(response)
 			require.NoError(s.test, err)
@@ -0,0 +1,188 @@
+package common
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/auth/cert"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/net/http/httpproxy"
+
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+)
+
+// transportSettings holds the part of the server details that drives how we connect to the server.
+type transportSettings struct {
+	clientCertPath    string
+	clientCertKeyPath string
+	insecureTLS       bool
+	caCertsPath       string
+}
+
+// Server is the JFrog Platform called by a command, with the HTTP client configured from its transport settings.
+type Server struct {
+	*config.ServerDetails
+	// HTTPClient is used for every call made to the server, a nil client stands for http.DefaultClient
+	HTTPClient *http.Client
+	// Metadata tells where the actions and options metadata of the server are read from
+	Metadata MetadataOptions
+	// Retry overrides the retry policy provided by the command flags for the calls made with this server.
+	Retry *model.RetryPolicy
+}
+
+// GetServerDetails resolves the server details like model.GetServerDetails does, with an HTTP client using their client certificate
+// and TLS settings, recording the calls when --trace-http is provided, and the metadata flags of the command. The client is shared by the calls of the command, so that connections are reused.
+func GetServerDetails(c *components.Context) (*Server, error) {
+	details, err := model.GetServerDetails(c)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient, err := newHTTPClient(details)
+	if err != nil {
+		return nil, localError("cannot configure the http client: %+v", err)
+	}
+
+	if tracePath := c.GetStringFlagValue(model.FlagTraceHTTP); tracePath != "" {
+		httpClient = withHTTPTrace(httpClient, newHARRecorder(tracePath, c.GetBoolFlagValue(model.FlagTraceIncludeSource)))
+	}
+
+	return &Server{ServerDetails: details, HTTPClient: httpClient, Metadata: GetMetadataOptions(c)}, nil
+}
+
+// newHTTPClient returns a client using the client certificate and TLS settings of the server details.
+func newHTTPClient(server *config.ServerDetails) (*http.Client, error) {
+	settings := transportSettings{
+		clientCertPath:    server.GetClientCertPath(),
+		clientCertKeyPath: server.GetClientCertKeyPath(),
+		insecureTLS:       server.InsecureTls,
+		caCertsPath:       os.Getenv(model.EnvKeyCACertsPath),
+	}
+	if value, isSet := os.LookupEnv(model.EnvKeyInsecureTLS); isSet {
+		settings.insecureTLS = value == "true"
+	}
+
+	transport, err := newTransport(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Client{Transport: transport}, nil
+}
+
+func newTransport(settings transportSettings) (*http.Transport, error) {
+	rootCAs, err := loadRootCAs(settings.caCertsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		RootCAs: rootCAs,
+		//#nosec G402 -- Skipping insecure tls verification was requested by the user.
+		InsecureSkipVerify: settings.insecureTLS,
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	if settings.clientCertPath != "" {
+		certificate, err := cert.LoadCertificate(settings.clientCertPath, settings.clientCertKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	// The proxy environment is read for each transport, i.e. each command, whereas http.ProxyFromEnvironment reads it once per process
+	proxyFunc := httpproxy.FromEnvironment().ProxyFunc()
+
+	return &http.Transport{
+		Proxy: func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		},
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 20 * time.Second,
+		}).DialContext,
+		TLSClientConfig:       tlsConfig,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   10,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}, nil
+}
+
+// loadRootCAs returns the system roots with the certificates of the JFrog CLI security directory
+// and of the custom CA bundle provided with model.EnvKeyCACertsPath.
+func loadRootCAs(caCertsPath string) (*x509.CertPool, error) {
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil {
+		log.Debug(fmt.Sprintf("Cannot load the system certificates: %+v", err))
+		rootCAs = x509.NewCertPool()
+	}
+
+	certsDir, err := coreutils.GetJfrogCertsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []string{certsDir}
+	if caCertsPath != "" {
+		paths = append(paths, caCertsPath)
+	}
+
+	for _, path := range paths {
+		if err = appendCertsFromPath(rootCAs, path); err != nil {
+			return nil, err
+		}
+	}
+
+	return rootCAs, nil
+}
+
+// appendCertsFromPath adds the PEM certificates found in path, which can either be a file or a directory.
+func appendCertsFromPath(pool *x509.CertPool, path string) error {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return err
+		}
+		files = files[:0]
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if !pool.AppendCertsFromPEM(content) {
+			log.Debug(fmt.Sprintf("No certificate found in %s", file))
+		}
+	}
+
+	return nil
+}
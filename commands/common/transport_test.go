@@ -0,0 +1,195 @@
+//go:build test
+// +build test
+
+package common
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+)
+
+func TestCallWorkerAPI_Transport(t *testing.T) {
+	clientCertFile, clientKeyFile, clientCert := generateTestCertificate(t)
+
+	tests := []struct {
+		name        string
+		server      *config.ServerDetails
+		caCerts     bool
+		requireCert bool
+		wantErr     string
+	}{
+		{
+			name:    "reject unknown certificate authority",
+			server:  &config.ServerDetails{},
+			wantErr: "certificate",
+		},
+		{
+			name:    "trust custom CA",
+			server:  &config.ServerDetails{},
+			caCerts: true,
+		},
+		{
+			name:   "insecure tls",
+			server: &config.ServerDetails{InsecureTls: true},
+		},
+		{
+			name:        "send client certificate",
+			server:      &config.ServerDetails{ClientCertPath: clientCertFile, ClientCertKeyPath: clientKeyFile},
+			caCerts:     true,
+			requireCert: true,
+		},
+		{
+			name:        "fails without client certificate",
+			server:      &config.ServerDetails{},
+			caCerts:     true,
+			requireCert: true,
+			wantErr:     "unexpected status code 401",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				if tt.requireCert && (len(req.TLS.PeerCertificates) == 0 || !req.TLS.PeerCertificates[0].Equal(clientCert)) {
+					res.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				res.WriteHeader(http.StatusOK)
+			}))
+			if tt.requireCert {
+				server.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+			}
+			server.StartTLS()
+			t.Cleanup(server.Close)
+
+			if tt.caCerts {
+				caFile := filepath.Join(t.TempDir(), "ca.pem")
+				require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600))
+				TestSetEnv(t, model.EnvKeyCACertsPath, caFile)
+			}
+
+			httpClient, err := newHTTPClient(tt.server)
+			require.NoError(t, err)
+
+			err = CallWorkerAPI(IntFlagMap{}, APICallParams{
+				Method:     http.MethodGet,
+				ServerURL:  server.URL,
+				HTTPClient: httpClient,
+				Path:       []string{"workers"},
+				OkStatuses: []int{http.StatusOK},
+			})
+
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCallWorkerAPI_Proxy(t *testing.T) {
+	var proxiedURL string
+	proxy := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		proxiedURL = req.URL.String()
+		res.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(proxy.Close)
+
+	TestSetEnv(t, "HTTP_PROXY", proxy.URL)
+	TestSetEnv(t, "NO_PROXY", "")
+
+	httpClient, err := newHTTPClient(&config.ServerDetails{})
+	require.NoError(t, err)
+
+	err = CallWorkerAPI(IntFlagMap{}, APICallParams{
+		Method:     http.MethodGet,
+		ServerURL:  "http://platform.example.com",
+		HTTPClient: httpClient,
+		Path:       []string{"workers"},
+		OkStatuses: []int{http.StatusOK},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "http://platform.example.com/worker/api/v1/workers", proxiedURL)
+}
+
+func TestCallWorkerClient_ServerHTTPClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	transport := &countingTransport{next: http.DefaultTransport}
+	details := &Server{ServerDetails: &config.ServerDetails{Url: server.URL}, HTTPClient: &http.Client{Transport: transport}}
+
+	for range 2 {
+		_, err := FetchWorkerDetails(IntFlagMap{}, details, "my-worker", "")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, transport.calls)
+
+	// Another server, e.g. of another command, does not share the client
+	_, err := FetchWorkerDetails(IntFlagMap{}, TestServer(server.URL, ""), "my-worker", "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, transport.calls)
+}
+
+type countingTransport struct {
+	next  http.RoundTripper
+	calls int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.calls++
+	return t.next.RoundTrip(req)
+}
+
+func generateTestCertificate(t *testing.T) (string, string, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "worker-cli-test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(certBytes)
+	require.NoError(t, err)
+
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client.key")
+
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certBytes}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes}), 0o600))
+
+	return certFile, keyFile, cert
+}
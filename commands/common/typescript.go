@@ -9,13 +9,10 @@ import (
 )
 
 var (
-	tsNewInstancePattern        = regexp.MustCompile(`new\s+([A-Z][a-zA-Z0-9]+)\(`)
-	tsFieldTypePattern          = regexp.MustCompile(`[a-zA-Z0-9]+\s*:\s*([A-Z][a-zA-Z0-9]+)`)
-	tsFieldDeconstructedPattern = regexp.MustCompile(`\[[^]]+]\s*:\s*([A-Z][a-zA-Z0-9]+)`)
-	tsFieldAccessPattern        = regexp.MustCompile(`\W([A-Z][a-zA-Z0-9]+)\.[a-zA-Z0-9]+`)
-	tsTypeInTypeParameters      = regexp.MustCompile(`<([A-Z][a-zA-Z0-9]+)>`)
-	tsExcludeTypes              = []string{"PlatformContext"}
-	tsUnexportedType            = regexp.MustCompile(`^(class|type|interface|enum|const)\s+[A-Za-z_$][0-9A-Za-z_$]*`)
+	tsExcludeTypes   = []string{"PlatformContext"}
+	tsUnexportedType = regexp.MustCompile(`^(class|type|interface|enum|const)\s+[A-Za-z_$][0-9A-Za-z_$]*`)
+	// The keywords followed by the name of a type in the types definitions
+	tsDeclarationKeywords = []string{"class", "type", "interface", "enum"}
 )
 
 // AddExportToTypesDeclarations Add export to (interface, class, enum, type) XXX found in the source.
@@ -29,41 +26,59 @@ func AddExportToTypesDeclarations(tsSource string) string {
 	return strings.Join(lines, "\n")
 }
 
-// ExtractActionUsedTypes extracts all the type used in an action's sampleCode and defined in the action's typesDefinitions.
+// ExtractActionUsedTypes extracts all the type used in an action's sampleCode and declared in the action's typesDefinitions.
 func ExtractActionUsedTypes(md *model.ActionMetadata) []string {
+	// The declarations read before an invalid token are kept, the types definitions are only scanned for names
+	definitions, _ := tokenizeTypeScript(md.TypesDefinitions)
+	var declared []string
+	for i := 1; i < len(definitions); i++ {
+		if slices.Contains(tsDeclarationKeywords, definitions[i-1]) && isTypeScriptIdentifier(definitions[i]) {
+			declared = append(declared, definitions[i])
+		}
+	}
+
 	var types []string
 	for _, typeName := range ExtractUsedTypes(md.SampleCode) {
-		if strings.Contains(md.TypesDefinitions, typeName) {
+		if slices.Contains(declared, typeName) {
 			types = append(types, typeName)
 		}
 	}
 	return types
 }
 
-// ExtractUsedTypes extracts types from a TypeScript source file.
+// ExtractUsedTypes extracts types from a TypeScript source file: the instantiated classes, the types of the variables and parameters,
+// the type arguments and the types of which a member is accessed, e.g. an enum member.
 func ExtractUsedTypes(tsSource string) []string {
-	var types []string
-
-	handleMatch := func(m string) {
-		if slices.Index(types, m) == -1 && slices.Index(tsExcludeTypes, m) == -1 {
-			types = append(types, m)
+	// The types used before an invalid token are kept, e.g. a regular expression literal with a quote
+	tokens, _ := tokenizeTypeScript(tsSource)
+	token := func(i int) string {
+		if i < 0 || i >= len(tokens) {
+			return ""
 		}
+		return tokens[i]
 	}
 
-	extractTypesByPattern(tsNewInstancePattern, tsSource, handleMatch)
-	extractTypesByPattern(tsFieldTypePattern, tsSource, handleMatch)
-	extractTypesByPattern(tsFieldAccessPattern, tsSource, handleMatch)
-	extractTypesByPattern(tsTypeInTypeParameters, tsSource, handleMatch)
-	extractTypesByPattern(tsFieldDeconstructedPattern, tsSource, handleMatch)
+	var types []string
+	for i, name := range tokens {
+		if !isTypeScriptTypeName(name) || slices.Contains(types, name) || slices.Contains(tsExcludeTypes, name) {
+			continue
+		}
+		previous, next := token(i-1), token(i+1)
+		switch {
+		case previous == "new" && next == "(",
+			previous == ":" && (isTypeScriptIdentifier(token(i-2)) || token(i-2) == "]"),
+			previous == "<" && next == ">",
+			next == "." && isTypeScriptIdentifier(token(i+2)):
+			types = append(types, name)
+		}
+	}
 
 	slices.Sort(types)
 
 	return types
 }
 
-func extractTypesByPattern(pattern *regexp.Regexp, source string, onMatch func(m string)) {
-	matches := pattern.FindAllStringSubmatch(source, -1)
-	for _, match := range matches {
-		onMatch(match[1])
-	}
+// isTypeScriptTypeName tells whether an identifier follows the naming of the types, e.g. BeforeDownloadRequest.
+func isTypeScriptTypeName(token string) bool {
+	return len(token) > 1 && token[0] >= 'A' && token[0] <= 'Z' && isTypeScriptIdentifier(token)
 }
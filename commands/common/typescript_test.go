@@ -8,6 +8,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/jfrog/jfrog-cli-platform-services/model"
 )
 
 func TestAddExportToTypesDeclarations(t *testing.T) {
@@ -225,3 +227,24 @@ func TestExtractActionUsedTypes(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractUsedTypes_SkipsCommentsAndStrings(t *testing.T) {
+	source := `
+// new Ignored() or Ignored.value
+export default async (context: PlatformContext, data: BeforeDownloadRequest): Promise<BeforeDownloadResponse> => {
+    const message = "Status.DOWNLOAD_PROCEED: new Quoted()";
+    /* const [a, b]: Commented = ... */
+    return { status: DownloadStatus.DOWNLOAD_PROCEED, message };
+};`
+
+	assert.Equal(t, []string{"BeforeDownloadRequest", "BeforeDownloadResponse", "DownloadStatus"}, ExtractUsedTypes(source))
+}
+
+func TestExtractActionUsedTypes_DeclaredTypesOnly(t *testing.T) {
+	actionMeta := &model.ActionMetadata{
+		SampleCode:       `export default async (context: PlatformContext, data: Request): Promise<Response> => ({ headers: new Header() })`,
+		TypesDefinitions: "interface Request {\n    headers: HeaderValue[];\n}\n\ntype Response = { status: number };",
+	}
+
+	assert.Equal(t, []string{"Request", "Response"}, ExtractActionUsedTypes(actionMeta))
+}
@@ -0,0 +1,599 @@
+package common
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// TSKind is the kind of TypeScript type, as far as the JSON payloads of the workers are concerned.
+type TSKind int
+
+const (
+	TSAny TSKind = iota
+	TSString
+	TSNumber
+	TSBoolean
+	TSNull
+	TSUndefined
+	// TSLiteral is a string or number literal type, e.g. 'GET'
+	TSLiteral
+	TSArray
+	// TSMap is an index signature, e.g. { [key: string]: Header } or Record<string, Header>
+	TSMap
+	TSObject
+	TSUnion
+	// TSReference is a named interface, enum or type alias
+	TSReference
+)
+
+// TSType is a TypeScript type read from the types definitions of an action.
+type TSType struct {
+	Kind TSKind
+	// Name is the name of a TSReference
+	Name string
+	// Literal is the string or float64 value of a TSLiteral
+	Literal any
+	// Element is the type of the elements of a TSArray or of the values of a TSMap
+	Element *TSType
+	// Fields are the fields of a TSObject
+	Fields []*TSField
+	// Variants are the types of a TSUnion
+	Variants []*TSType
+}
+
+// TSField is a field of an interface or of an object literal type.
+type TSField struct {
+	Name string
+	// Optional is true for the fields declared with ?, the fields of which the type includes undefined can also be omitted
+	Optional bool
+	Type     *TSType
+}
+
+// TSEnumMember is a member of an enum, its value is a float64 or a string.
+type TSEnumMember struct {
+	Name  string
+	Value any
+}
+
+// TSTypes are the interfaces, enums and type aliases declared in the types definitions of an action.
+type TSTypes struct {
+	Interfaces map[string]*TSType
+	Enums      map[string][]*TSEnumMember
+	Aliases    map[string]*TSType
+}
+
+// ParseTypesDefinitions reads the declarations of the typesDefinitions of an action: the interfaces (with extends), the enums and the type aliases.
+// The other declarations are skipped, and the unsupported types are read as any.
+func ParseTypesDefinitions(source string) (*TSTypes, error) {
+	tokens, err := tokenizeTypeScript(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &tsParser{tokens: tokens}
+	types := &TSTypes{Interfaces: map[string]*TSType{}, Enums: map[string][]*TSEnumMember{}, Aliases: map[string]*TSType{}}
+	extends := map[string][]string{}
+
+	for !p.eof() {
+		switch p.next() {
+		case "interface":
+			name := p.next()
+			p.skipTypeArguments()
+			for p.accept("extends") || p.accept(",") {
+				extends[name] = append(extends[name], p.next())
+				p.skipTypeArguments()
+			}
+			object, err := p.parseObject()
+			if err != nil {
+				return nil, fmt.Errorf("invalid interface %s: %w", name, err)
+			}
+			types.Interfaces[name] = object
+		case "enum":
+			name := p.next()
+			members, err := p.parseEnum()
+			if err != nil {
+				return nil, fmt.Errorf("invalid enum %s: %w", name, err)
+			}
+			types.Enums[name] = members
+		case "type":
+			name := p.next()
+			p.skipTypeArguments()
+			if !p.accept("=") {
+				return nil, fmt.Errorf("invalid type %s: expected =", name)
+			}
+			alias, err := p.parseType()
+			if err != nil {
+				return nil, fmt.Errorf("invalid type %s: %w", name, err)
+			}
+			types.Aliases[name] = alias
+		case "{":
+			// The bodies of the classes, functions and namespaces
+			p.skipBlock()
+		}
+	}
+
+	for name, parents := range extends {
+		for _, parent := range parents {
+			if parentType, found := types.Interfaces[parent]; found {
+				types.Interfaces[name].Fields = append(slices.Clone(parentType.Fields), types.Interfaces[name].Fields...)
+			}
+		}
+	}
+
+	return types, nil
+}
+
+// Resolve returns the type behind a reference, nil when the reference is unknown.
+func (t *TSTypes) Resolve(reference *TSType) *TSType {
+	if reference.Kind != TSReference {
+		return reference
+	}
+	if object, found := t.Interfaces[reference.Name]; found {
+		return object
+	}
+	if alias, found := t.Aliases[reference.Name]; found {
+		return t.Resolve(alias)
+	}
+	return nil
+}
+
+// IsOptional tells whether a value of this type can be omitted, i.e. whether it is undefined or a union with undefined.
+func (t *TSType) IsOptional() bool {
+	switch t.Kind {
+	case TSUndefined:
+		return true
+	case TSUnion:
+		for _, variant := range t.Variants {
+			if variant.IsOptional() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (t *TSType) String() string {
+	switch t.Kind {
+	case TSString:
+		return "string"
+	case TSNumber:
+		return "number"
+	case TSBoolean:
+		return "boolean"
+	case TSNull:
+		return "null"
+	case TSUndefined:
+		return "undefined"
+	case TSLiteral:
+		if text, isString := t.Literal.(string); isString {
+			return strconv.Quote(text)
+		}
+		return FormatCell(t.Literal)
+	case TSArray:
+		return t.Element.String() + "[]"
+	case TSMap:
+		return "{ [key: string]: " + t.Element.String() + " }"
+	case TSObject:
+		return "object"
+	case TSUnion:
+		variants := make([]string, len(t.Variants))
+		for i, variant := range t.Variants {
+			variants[i] = variant.String()
+		}
+		return strings.Join(variants, " | ")
+	case TSReference:
+		return t.Name
+	default:
+		return "any"
+	}
+}
+
+type tsParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *tsParser) eof() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *tsParser) peek() string {
+	if p.eof() {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *tsParser) next() string {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+func (p *tsParser) accept(token string) bool {
+	if p.peek() == token {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *tsParser) expect(token string) error {
+	if !p.accept(token) {
+		return fmt.Errorf("expected '%s' but found '%s'", token, p.peek())
+	}
+	return nil
+}
+
+// skipBlock skips the tokens up to the brace closing the one just read.
+func (p *tsParser) skipBlock() {
+	for depth := 1; depth > 0 && !p.eof(); {
+		switch p.next() {
+		case "{":
+			depth++
+		case "}":
+			depth--
+		}
+	}
+}
+
+func (p *tsParser) skipTypeArguments() {
+	if !p.accept("<") {
+		return
+	}
+	for depth := 1; depth > 0 && !p.eof(); {
+		switch p.next() {
+		case "<":
+			depth++
+		case ">":
+			depth--
+		}
+	}
+}
+
+// parseObject reads the members of an interface or of an object literal type, from the opening brace.
+func (p *tsParser) parseObject() (*TSType, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	object := &TSType{Kind: TSObject}
+	for !p.accept("}") {
+		if p.eof() {
+			return nil, fmt.Errorf("missing '}'")
+		}
+		if p.peek() == "readonly" && p.pos+1 < len(p.tokens) && !slices.Contains([]string{":", "?", "("}, p.tokens[p.pos+1]) {
+			p.next()
+		}
+
+		if p.accept("[") {
+			// An index signature
+			p.next()
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+			p.next()
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+			element, err := p.parseType()
+			if err != nil {
+				return nil, err
+			}
+			p.skipSeparators()
+			if len(object.Fields) == 0 && p.peek() == "}" {
+				object = &TSType{Kind: TSMap, Element: element}
+			}
+			continue
+		}
+
+		name := p.next()
+		if unquoted, err := strconv.Unquote(name); err == nil {
+			name = unquoted
+		}
+		field := &TSField{Name: name, Optional: p.accept("?")}
+		if p.peek() == "(" || p.peek() == "<" {
+			// The methods are not part of the JSON payloads
+			if err := p.skipMethod(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		fieldType, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		field.Type = fieldType
+		object.Fields = append(object.Fields, field)
+		p.skipSeparators()
+	}
+	return object, nil
+}
+
+func (p *tsParser) skipMethod() error {
+	p.skipTypeArguments()
+	if err := p.expect("("); err != nil {
+		return err
+	}
+	for depth := 1; depth > 0; {
+		if p.eof() {
+			return fmt.Errorf("missing ')'")
+		}
+		switch p.next() {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		}
+	}
+	if p.accept(":") {
+		if _, err := p.parseType(); err != nil {
+			return err
+		}
+	}
+	p.skipSeparators()
+	return nil
+}
+
+func (p *tsParser) skipSeparators() {
+	for p.accept(";") || p.accept(",") {
+	}
+}
+
+func (p *tsParser) parseEnum() ([]*TSEnumMember, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	var members []*TSEnumMember
+	var nextValue float64
+	for !p.accept("}") {
+		if p.eof() {
+			return nil, fmt.Errorf("missing '}'")
+		}
+		member := &TSEnumMember{Name: p.next(), Value: nextValue}
+		if p.accept("=") {
+			value := p.next()
+			if unquoted, err := strconv.Unquote(value); err == nil {
+				member.Value = unquoted
+			} else if number, err := strconv.ParseFloat(value, 64); err == nil {
+				member.Value = number
+			} else {
+				return nil, fmt.Errorf("unsupported value '%s' of %s", value, member.Name)
+			}
+		}
+		if number, isNumber := member.Value.(float64); isNumber {
+			nextValue = number + 1
+		}
+		members = append(members, member)
+		p.skipSeparators()
+	}
+	return members, nil
+}
+
+// parseType reads a union of types, with an optional leading |.
+func (p *tsParser) parseType() (*TSType, error) {
+	p.accept("|")
+
+	var variants []*TSType
+	for {
+		variant, err := p.parsePostfixType()
+		if err != nil {
+			return nil, err
+		}
+		variants = append(variants, variant)
+		if !p.accept("|") {
+			break
+		}
+	}
+
+	// The intersections are not supported, their first type is kept
+	for p.accept("&") {
+		if _, err := p.parsePostfixType(); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(variants) == 1 {
+		return variants[0], nil
+	}
+	return &TSType{Kind: TSUnion, Variants: variants}, nil
+}
+
+func (p *tsParser) parsePostfixType() (*TSType, error) {
+	primary, err := p.parsePrimaryType()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "[" && p.pos+1 < len(p.tokens) && p.tokens[p.pos+1] == "]" {
+		p.pos += 2
+		primary = &TSType{Kind: TSArray, Element: primary}
+	}
+	return primary, nil
+}
+
+func (p *tsParser) parsePrimaryType() (*TSType, error) {
+	token := p.peek()
+	switch {
+	case token == "{":
+		return p.parseObject()
+	case token == "(":
+		p.next()
+		inner, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expect(")")
+	case token == "[":
+		// A tuple, read as an array of its types
+		p.next()
+		var variants []*TSType
+		for !p.accept("]") {
+			if p.eof() {
+				return nil, fmt.Errorf("missing ']'")
+			}
+			variant, err := p.parseType()
+			if err != nil {
+				return nil, err
+			}
+			variants = append(variants, variant)
+			p.accept(",")
+		}
+		element := &TSType{Kind: TSAny}
+		if len(variants) == 1 {
+			element = variants[0]
+		} else if len(variants) > 1 {
+			element = &TSType{Kind: TSUnion, Variants: variants}
+		}
+		return &TSType{Kind: TSArray, Element: element}, nil
+	case token == "":
+		return nil, fmt.Errorf("unexpected end of the types definitions")
+	}
+
+	p.next()
+	if unquoted, err := strconv.Unquote(token); err == nil {
+		return &TSType{Kind: TSLiteral, Literal: unquoted}, nil
+	}
+	if number, err := strconv.ParseFloat(token, 64); err == nil {
+		return &TSType{Kind: TSLiteral, Literal: number}, nil
+	}
+
+	switch token {
+	case "string":
+		return &TSType{Kind: TSString}, nil
+	case "number", "bigint":
+		return &TSType{Kind: TSNumber}, nil
+	case "boolean":
+		return &TSType{Kind: TSBoolean}, nil
+	case "true", "false":
+		return &TSType{Kind: TSLiteral, Literal: token == "true"}, nil
+	case "null":
+		return &TSType{Kind: TSNull}, nil
+	case "undefined", "void":
+		return &TSType{Kind: TSUndefined}, nil
+	case "Array", "ReadonlyArray", "Set":
+		element, err := p.parseTypeArguments(1)
+		if err != nil {
+			return nil, err
+		}
+		return &TSType{Kind: TSArray, Element: element[0]}, nil
+	case "Record", "Map":
+		arguments, err := p.parseTypeArguments(2)
+		if err != nil {
+			return nil, err
+		}
+		return &TSType{Kind: TSMap, Element: arguments[1]}, nil
+	}
+
+	if !isTypeScriptIdentifier(token) {
+		return nil, fmt.Errorf("unexpected '%s'", token)
+	}
+	// Qualified names, e.g. Namespace.Type, and the generic types are referenced by their last name
+	for p.accept(".") {
+		token = p.next()
+	}
+	p.skipTypeArguments()
+	if token == "any" || token == "unknown" || token == "object" || token == "Date" {
+		return &TSType{Kind: TSAny}, nil
+	}
+	return &TSType{Kind: TSReference, Name: token}, nil
+}
+
+func (p *tsParser) parseTypeArguments(count int) ([]*TSType, error) {
+	if err := p.expect("<"); err != nil {
+		return nil, err
+	}
+	var arguments []*TSType
+	for {
+		argument, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		arguments = append(arguments, argument)
+		if !p.accept(",") {
+			break
+		}
+	}
+	if err := p.expect(">"); err != nil {
+		return nil, err
+	}
+	if len(arguments) != count {
+		return nil, fmt.Errorf("expected %d type arguments but found %d", count, len(arguments))
+	}
+	return arguments, nil
+}
+
+func isTypeScriptIdentifier(token string) bool {
+	for i, c := range token {
+		if !(c == '_' || c == '$' || unicode.IsLetter(c) || (i > 0 && unicode.IsDigit(c))) {
+			return false
+		}
+	}
+	return token != ""
+}
+
+// tokenizeTypeScript splits a TypeScript source in identifiers, numbers, quoted strings and punctuation, without the comments.
+// On error, the tokens read up to the error are returned with it.
+func tokenizeTypeScript(source string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(source); {
+		c := source[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(source[i:], "//"):
+			end := strings.IndexByte(source[i:], '\n')
+			if end < 0 {
+				return tokens, nil
+			}
+			i += end
+		case strings.HasPrefix(source[i:], "/*"):
+			end := strings.Index(source[i+2:], "*/")
+			if end < 0 {
+				return tokens, fmt.Errorf("unterminated comment in the types definitions")
+			}
+			i += end + 4
+		case c == '"' || c == '\'' || c == '`':
+			end := i + 1
+			for end < len(source) && source[end] != c {
+				if source[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(source) {
+				return tokens, fmt.Errorf("unterminated string in the types definitions")
+			}
+			literal := source[i+1 : end]
+			tokens = append(tokens, strconv.Quote(literal))
+			i = end + 1
+		case c == '-' || (c >= '0' && c <= '9'):
+			end := i + 1
+			for end < len(source) && (source[end] == '.' || (source[end] >= '0' && source[end] <= '9')) {
+				end++
+			}
+			tokens = append(tokens, source[i:end])
+			i = end
+		case c == '_' || c == '$' || c >= 0x80 || unicode.IsLetter(rune(c)):
+			end := i + 1
+			for end < len(source) && (source[end] == '_' || source[end] == '$' || source[end] >= 0x80 || unicode.IsLetter(rune(source[end])) || unicode.IsDigit(rune(source[end]))) {
+				end++
+			}
+			tokens = append(tokens, source[i:end])
+			i = end
+		default:
+			tokens = append(tokens, string(c))
+			i++
+		}
+	}
+	return tokens, nil
+}
@@ -0,0 +1,107 @@
+//go:build test
+// +build test
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTypesDefinitions(t *testing.T) {
+	types, err := ParseTypesDefinitions(`
+/** A comment with { braces } and 'quotes' */
+interface Base<T> {
+    id: string
+    readonly tags?: Array<string>;
+}
+
+interface Request extends Base<number> {
+    method: 'GET' | 'POST';
+    headers: { [key: string]: Header };
+    owner: User | undefined;
+    sizes: number[];
+    attributes: Record<string, any>;
+    callback(value: string): void;
+}
+
+interface Header {
+    value: string[];
+}
+
+type User = { name: string; admin: boolean };
+
+enum Status {
+    UNSPECIFIED = 0,
+    PROCEED,
+    NAMED = 'named',
+    UNRECOGNIZED = -1,
+}
+
+function ignored(): void {
+    return;
+}
+`)
+	require.NoError(t, err)
+
+	request := types.Interfaces["Request"]
+	require.NotNil(t, request)
+
+	fields := map[string]string{}
+	optional := map[string]bool{}
+	for _, field := range request.Fields {
+		fields[field.Name] = field.Type.String()
+		optional[field.Name] = field.Optional || field.Type.IsOptional()
+	}
+
+	assert.Equal(t, map[string]string{
+		"id":         "string",
+		"tags":       "string[]",
+		"method":     `"GET" | "POST"`,
+		"headers":    "{ [key: string]: Header }",
+		"owner":      "User | undefined",
+		"sizes":      "number[]",
+		"attributes": "{ [key: string]: any }",
+	}, fields)
+	assert.Equal(t, map[string]bool{
+		"id":         false,
+		"tags":       true,
+		"method":     false,
+		"headers":    false,
+		"owner":      true,
+		"sizes":      false,
+		"attributes": false,
+	}, optional)
+
+	user := types.Resolve(&TSType{Kind: TSReference, Name: "User"})
+	require.NotNil(t, user)
+	assert.Equal(t, TSObject, user.Kind)
+	assert.Len(t, user.Fields, 2)
+
+	assert.Nil(t, types.Resolve(&TSType{Kind: TSReference, Name: "Status"}))
+	assert.Equal(t, []*TSEnumMember{
+		{Name: "UNSPECIFIED", Value: 0.0},
+		{Name: "PROCEED", Value: 1.0},
+		{Name: "NAMED", Value: "named"},
+		{Name: "UNRECOGNIZED", Value: -1.0},
+	}, types.Enums["Status"])
+}
+
+func TestParseTypesDefinitions_SampleActions(t *testing.T) {
+	for _, action := range LoadSampleActions(t) {
+		t.Run(action.Action.Name, func(t *testing.T) {
+			types, err := ParseTypesDefinitions(action.TypesDefinitions)
+			require.NoError(t, err)
+			if action.ExecutionRequestType != "" {
+				assert.Contains(t, types.Interfaces, action.ExecutionRequestType)
+			}
+		})
+	}
+}
+
+func TestParseTypesDefinitions_Invalid(t *testing.T) {
+	_, err := ParseTypesDefinitions(`interface Broken { name: string;`)
+	assert.Error(t, err)
+}
@@ -6,7 +6,7 @@ import (
 	"github.com/jfrog/jfrog-cli-platform-services/model"
 )
 
-func ValidateVersion(version *model.Version, options *OptionsMetadata) error {
+func ValidateVersion(version *model.Version, options *model.OptionsMetadata) error {
 	if len(version.Number) > options.Edition.MaxVersionNumberChars {
 		return fmt.Errorf("version number exceeds maximum length of %d characters", options.Edition.MaxVersionNumberChars)
 	}
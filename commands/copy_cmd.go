@@ -0,0 +1,213 @@
+package commands
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	plugins_common "github.com/jfrog/jfrog-cli-core/v2/plugins/common"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+
+	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+	"github.com/jfrog/jfrog-cli-platform-services/workerclient"
+)
+
+const flagCopyDisabled = "disabled"
+
+type copyCommandHandler struct {
+	ctx        *components.Context
+	server     *common.Server
+	projectKey string
+}
+
+func GetCopyCommand() components.Command {
+	return components.Command{
+		Name:        "copy",
+		Description: "Copy a deployed worker to a new key",
+		AIDescription: `Create a new worker on the JFrog Platform from the server-side definition of an existing one. The source code, action, description, filter criteria and debug flag are copied as-is; no local files are read or written.
+
+When to use:
+- Creating a variant of an existing worker without a pull / edit / deploy cycle.
+- Duplicating a worker before experimenting with it.
+
+Prerequisites:
+- The source worker must already be deployed and the target key must not be in use.
+- Configured server (jf c add or jf login) with permission to manage workers in the target scope (project or platform).
+
+Common patterns:
+  $ jf worker copy my-worker my-worker-v2
+  $ jf worker copy my-worker my-worker-v2 --disabled
+  $ jf worker copy my-worker my-worker-v2 --project-key my-project
+
+Gotchas:
+- Both workers react to the same events when the copy is enabled; use --disabled to create it turned off.
+- Secret values are only copied when the server returns them, the omitted and masked (****) values are skipped; redeploy these secrets with 'jf worker deploy'.
+- The actions metadata are cached per server for an hour (JFROG_WORKER_CLI_METADATA_CACHE_TTL); pass --refresh-metadata to download them again, e.g. after installing an application.
+- On machines that cannot reach the server, pass --actions-file with the output of 'jf worker export-metadata' or 'jf worker list-event --format json'.
+
+Related: jf worker rename, jf worker deploy, jf worker list`,
+		Aliases: []string{"cp"},
+		Flags: slices.Concat([]components.Flag{
+			plugins_common.GetServerIdFlag(),
+			model.GetTimeoutFlag(),
+			model.GetProjectKeyFlag(),
+			components.NewBoolFlag(flagCopyDisabled, "Create the copy disabled, whatever the state of the source worker.", components.WithBoolDefaultValue(false)),
+		}, model.GetMetadataFlags(), model.GetHTTPClientFlags()),
+		Arguments: []components.Argument{
+			{Name: "source-worker-key", Description: "The key of the worker to copy."},
+			{Name: "target-worker-key", Description: "The key of the worker to create."},
+		},
+		Action: func(c *components.Context) error {
+			h, sourceKey, targetKey, err := newCopyCommandHandler(c)
+			if err != nil {
+				return err
+			}
+			return h.copy(sourceKey, targetKey)
+		},
+	}
+}
+
+func newCopyCommandHandler(c *components.Context) (*copyCommandHandler, string, string, error) {
+	if len(c.Arguments) != 2 {
+		return nil, "", "", plugins_common.WrongNumberOfArgumentsHandler(c)
+	}
+
+	sourceKey, targetKey := c.Arguments[0], c.Arguments[1]
+	if sourceKey == targetKey {
+		return nil, "", "", fmt.Errorf("the source and target worker keys must be different")
+	}
+
+	server, err := common.GetServerDetails(c)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	if err = common.CheckProjectSupport(c, server, c.GetStringFlagValue(model.FlagProjectKey)); err != nil {
+		return nil, "", "", err
+	}
+
+	return &copyCommandHandler{
+		ctx:        c,
+		server:     server,
+		projectKey: c.GetStringFlagValue(model.FlagProjectKey),
+	}, sourceKey, targetKey, nil
+}
+
+func (h *copyCommandHandler) copy(sourceKey, targetKey string) error {
+	source, err := h.fetchSourceAndCheckTarget(sourceKey, targetKey)
+	if err != nil {
+		return err
+	}
+
+	request, err := h.prepareCopyRequest(source, targetKey)
+	if err != nil {
+		return err
+	}
+
+	if h.ctx.GetBoolFlagValue(flagCopyDisabled) {
+		request.Enabled = false
+	}
+
+	log.Info(fmt.Sprintf("Copying worker '%s' to '%s'", sourceKey, targetKey))
+
+	if err = h.createWorker(request); err != nil {
+		return err
+	}
+
+	log.Info(fmt.Sprintf("Worker '%s' copied to '%s'", sourceKey, targetKey))
+
+	return nil
+}
+
+// fetchSourceAndCheckTarget returns the details of the source worker and makes sure that the target key is free.
+func (h *copyCommandHandler) fetchSourceAndCheckTarget(sourceKey, targetKey string) (*model.WorkerDetails, error) {
+	source, err := common.FetchWorkerDetails(h.ctx, h.server, sourceKey, h.projectKey)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil {
+		return nil, fmt.Errorf("worker '%s' does not exist", sourceKey)
+	}
+
+	target, err := common.FetchWorkerDetails(h.ctx, h.server, targetKey, h.projectKey)
+	if err != nil {
+		return nil, err
+	}
+	if target != nil {
+		return nil, fmt.Errorf("worker '%s' already exists", targetKey)
+	}
+
+	return source, nil
+}
+
+// sourceProjectKey returns the project of the source worker, the one of --project-key when the server does not tell it.
+func (h *copyCommandHandler) sourceProjectKey(source *model.WorkerDetails) string {
+	return cmp.Or(source.ProjectKey, h.projectKey)
+}
+
+func (h *copyCommandHandler) prepareCopyRequest(source *model.WorkerDetails, targetKey string) (*model.WorkerRequest, error) {
+	projectKey := h.sourceProjectKey(source)
+
+	actionsMeta, err := common.FetchActions(h.ctx, h.server, projectKey)
+	if err != nil {
+		return nil, err
+	}
+
+	actionMeta, err := actionsMeta.FindAction(source.Action, source.Application)
+	if err != nil {
+		return nil, err
+	}
+
+	var secrets []*model.Secret
+	for _, secret := range source.Secrets {
+		if !secretValueReturned(secret) {
+			log.Warn(fmt.Sprintf("The value of secret '%s' was not returned by the server, it will not be copied", secret.Key))
+			continue
+		}
+		secrets = append(secrets, &model.Secret{Key: secret.Key, Value: secret.Value})
+	}
+
+	request := &model.WorkerRequest{
+		Key:         targetKey,
+		Description: source.Description,
+		Enabled:     source.Enabled,
+		Debug:       source.Debug,
+		SourceCode:  source.SourceCode,
+		Action:      actionMeta.Action,
+		Secrets:     secrets,
+		ProjectKey:  projectKey,
+	}
+
+	if actionMeta.MandatoryFilter {
+		request.FilterCriteria = source.FilterCriteria
+	}
+
+	return request, nil
+}
+
+func (h *copyCommandHandler) createWorker(request *model.WorkerRequest) error {
+	return common.RunWorkerClient(h.ctx, h.server, func(ctx context.Context, client *workerclient.Client) error {
+		return client.CreateWorker(ctx, request)
+	})
+}
+
+func (h *copyCommandHandler) updateWorker(request *model.WorkerRequest) error {
+	return common.RunWorkerClient(h.ctx, h.server, func(ctx context.Context, client *workerclient.Client) error {
+		return client.UpdateWorker(ctx, request)
+	})
+}
+
+func (h *copyCommandHandler) deleteWorker(workerKey string, projectKey string) error {
+	return common.RunWorkerClient(h.ctx, h.server, func(ctx context.Context, client *workerclient.Client) error {
+		return client.DeleteWorker(ctx, workerKey, projectKey)
+	})
+}
+
+// secretValueReturned tells whether the server returned the value of a secret, the values are either omitted or masked otherwise.
+func secretValueReturned(secret *model.Secret) bool {
+	return strings.Trim(secret.Value, "*") != ""
+}
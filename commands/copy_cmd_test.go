@@ -0,0 +1,134 @@
+//go:build test
+// +build test
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+)
+
+func TestCopyCommand(t *testing.T) {
+	tests := []struct {
+		name        string
+		commandArgs []string
+		serverStub  *common.ServerStub
+		wantErr     string
+		assert      func(t *testing.T, stub *common.ServerStub)
+	}{
+		{
+			name:        "copy",
+			commandArgs: []string{"wk-0", "wk-1"},
+			serverStub: common.NewServerStub(t).
+				WithWorkers(&model.WorkerDetails{
+					Key:         "wk-0",
+					Action:      "GENERIC_EVENT",
+					Description: "my worker",
+					Enabled:     true,
+					SourceCode:  "export default async () => ({ 'S': 'OK'})",
+					Secrets:     []*model.Secret{{Key: "sec-1", Value: "val-1"}, {Key: "sec-2"}},
+				}),
+			assert: func(t *testing.T, stub *common.ServerStub) {
+				require.NotNil(t, stub.GetWorker("wk-0"))
+				copied := stub.GetWorker("wk-1")
+				require.NotNil(t, copied)
+				assert.Equal(t, "GENERIC_EVENT", copied.Action)
+				assert.Equal(t, "my worker", copied.Description)
+				assert.True(t, copied.Enabled)
+				assert.Equal(t, "export default async () => ({ 'S': 'OK'})", copied.SourceCode)
+				assert.Equal(t, []*model.Secret{{Key: "sec-1", Value: "val-1"}}, copied.Secrets)
+			},
+		},
+		{
+			name:        "copy without the masked secrets",
+			commandArgs: []string{"wk-0", "wk-1"},
+			serverStub: common.NewServerStub(t).
+				WithWorkers(&model.WorkerDetails{Key: "wk-0", Action: "GENERIC_EVENT", Secrets: []*model.Secret{{Key: "sec-1", Value: "****"}, {Key: "sec-2", Value: "val-2"}}}),
+			assert: func(t *testing.T, stub *common.ServerStub) {
+				require.NotNil(t, stub.GetWorker("wk-1"))
+				assert.Equal(t, []*model.Secret{{Key: "sec-2", Value: "val-2"}}, stub.GetWorker("wk-1").Secrets)
+			},
+		},
+		{
+			name:        "copy disabled",
+			commandArgs: []string{"--" + flagCopyDisabled, "wk-0", "wk-1"},
+			serverStub: common.NewServerStub(t).
+				WithWorkers(&model.WorkerDetails{Key: "wk-0", Action: "GENERIC_EVENT", Enabled: true}),
+			assert: func(t *testing.T, stub *common.ServerStub) {
+				require.NotNil(t, stub.GetWorker("wk-1"))
+				assert.False(t, stub.GetWorker("wk-1").Enabled)
+			},
+		},
+		{
+			name:        "copy filter criteria",
+			commandArgs: []string{"wk-0", "wk-1"},
+			serverStub: common.NewServerStub(t).
+				WithWorkers(&model.WorkerDetails{
+					Key:    "wk-0",
+					Action: "BEFORE_UPLOAD",
+					FilterCriteria: &model.FilterCriteria{
+						ArtifactFilterCriteria: &model.ArtifactFilterCriteria{RepoKeys: []string{"libs-local"}},
+					},
+				}),
+			assert: func(t *testing.T, stub *common.ServerStub) {
+				require.NotNil(t, stub.GetWorker("wk-1"))
+				assert.Equal(t, []string{"libs-local"}, stub.GetWorker("wk-1").FilterCriteria.ArtifactFilterCriteria.RepoKeys)
+			},
+		},
+		{
+			name:        "fails if source does not exist",
+			commandArgs: []string{"wk-0", "wk-1"},
+			serverStub:  common.NewServerStub(t),
+			wantErr:     "worker 'wk-0' does not exist",
+		},
+		{
+			name:        "fails if target exists",
+			commandArgs: []string{"wk-0", "wk-1"},
+			serverStub: common.NewServerStub(t).
+				WithWorkers(&model.WorkerDetails{Key: "wk-0"}, &model.WorkerDetails{Key: "wk-1"}),
+			wantErr: "worker 'wk-1' already exists",
+		},
+		{
+			name:        "fails if same keys",
+			commandArgs: []string{"wk-0", "wk-0"},
+			serverStub:  common.NewServerStub(t),
+			wantErr:     "the source and target worker keys must be different",
+		},
+		{
+			name:        "fails if missing argument",
+			commandArgs: []string{"wk-0"},
+			serverStub:  common.NewServerStub(t),
+			wantErr:     "Wrong number of arguments (1).",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			common.NewMockWorkerServer(t,
+				tt.serverStub.
+					WithT(t).
+					WithDefaultActionsMetadataEndpoint().
+					WithGetOneEndpoint().
+					WithCreateEndpoint(nil),
+			)
+
+			runCmd := common.CreateCliRunner(t, GetCopyCommand())
+
+			err := runCmd(append([]string{"worker", "copy"}, tt.commandArgs...)...)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			tt.assert(t, tt.serverStub)
+		})
+	}
+}
@@ -1,13 +1,12 @@
 package commands
 
 import (
+	"context"
 	"encoding/base64"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"slices"
 
-	"github.com/jfrog/jfrog-cli-core/v2/common/format"
 	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
 
 	plugins_common "github.com/jfrog/jfrog-cli-core/v2/plugins/common"
@@ -15,30 +14,17 @@ import (
 	"github.com/jfrog/jfrog-client-go/utils/log"
 
 	"github.com/jfrog/jfrog-cli-platform-services/model"
+	"github.com/jfrog/jfrog-cli-platform-services/workerclient"
 )
 
-type deployRequest struct {
-	Key            string                `json:"key"`
-	Description    string                `json:"description"`
-	Enabled        bool                  `json:"enabled"`
-	Debug          bool                  `json:"debug"`
-	SourceCode     string                `json:"sourceCode"`
-	Action         model.Action          `json:"action"`
-	FilterCriteria *model.FilterCriteria `json:"filterCriteria,omitempty"`
-	Secrets        []*model.Secret       `json:"secrets"`
-	ProjectKey     string                `json:"projectKey"`
-	Version        *model.Version        `json:"version,omitempty"`
-}
-
 type deployCommandHandler struct {
 	ctx                      *components.Context
 	manifest                 *model.Manifest
 	actionMeta               *model.ActionMetadata
 	version                  *model.Version
-	serverURL                string
-	token                    string
+	server                   *common.Server
 	encodeSourceCodeInBase64 bool
-	outputFormat             format.OutputFormat
+	output                   *common.Output
 }
 
 func GetDeployCommand() components.Command {
@@ -63,17 +49,22 @@ Common patterns:
   $ jf worker deploy --version 1.2.3 --description "Add filter" --commit-sha abc1234
   $ jf worker deploy --base64
   $ jf worker deploy --format json
+  $ jf worker deploy --template '{{.StatusCode}}'
 
 Gotchas:
 - Secrets in manifest.json are decrypted locally and sent in plaintext over TLS unless --no-secrets is set.
 - Filter criteria are only sent when the action requires them (e.g. BEFORE_UPLOAD with a repo filter, SCHEDULED_EVENT with a cron).
 - The --base64 flag is ignored by servers that do not support base64-encoded source code.
+- A manifest with a projectKey is refused (exit code 18) when Artifactory is older than the version required for projects; the error names the required version.
 - Versioning fields are only validated against the server's version policy when at least one of --version / --description / --commit-sha is set.
+- The actions and options metadata are cached per server for an hour (JFROG_WORKER_CLI_METADATA_CACHE_TTL); pass --refresh-metadata to download them again, e.g. after installing an application.
+- On machines that cannot reach the metadata endpoints, pass --actions-file and --options-file with the output of 'jf worker export-metadata'.
+- Nothing is printed without --format, --template or --query; they print the status of the deployment (201 when created, 204 when updated).
 
 Related: jf worker test-run, jf worker undeploy, jf worker list, jf worker edit-schedule`,
 		Aliases:          []string{"d"},
-		SupportedFormats: []format.OutputFormat{format.Json},
-		Flags: []components.Flag{
+		SupportedFormats: common.DocumentFormats,
+		Flags: slices.Concat([]components.Flag{
 			plugins_common.GetServerIdFlag(),
 			model.GetTimeoutFlag(),
 			model.GetNoSecretsFlag(),
@@ -81,18 +72,16 @@ Related: jf worker test-run, jf worker undeploy, jf worker list, jf worker edit-
 			model.GetChangesDescriptionFlag(),
 			model.GetChangesCommitShaFlag(),
 			model.GetBase64Flag(),
-		},
+			model.GetTemplateFlag(),
+			model.GetQueryFlag(),
+		}, model.GetMetadataFlags(), model.GetHTTPClientFlags()),
 		Action: func(c *components.Context) error {
-			var outputFormat format.OutputFormat
-			if slices.Contains(c.FlagsUsed, format.FlagName) {
-				var fmtErr error
-				outputFormat, fmtErr = c.GetOutputFormat()
-				if fmtErr != nil {
-					return fmtErr
-				}
+			output, err := common.NewOutput(c)
+			if err != nil {
+				return err
 			}
 
-			server, err := model.GetServerDetails(c)
+			server, err := common.GetServerDetails(c)
 			if err != nil {
 				return err
 			}
@@ -102,7 +91,7 @@ Related: jf worker test-run, jf worker undeploy, jf worker list, jf worker edit-
 				return err
 			}
 
-			actionsMeta, err := common.FetchActions(c, server.GetUrl(), server.GetAccessToken(), manifest.ProjectKey)
+			actionsMeta, err := common.FetchActions(c, server, manifest.ProjectKey)
 			if err != nil {
 				return err
 			}
@@ -132,24 +121,30 @@ Related: jf worker test-run, jf worker undeploy, jf worker list, jf worker edit-
 				CommitSha:   c.GetStringFlagValue(model.FlagChangesCommitSha),
 			}
 
-			options, err := common.FetchOptions(c, server.GetUrl(), server.GetAccessToken())
+			capabilities, err := common.ProbeCapabilities(c, server)
 			if err != nil {
 				return err
 			}
 
+			if err = common.RequireProjectSupport(manifest.ProjectKey).Check(capabilities); err != nil {
+				return err
+			}
+
 			if !version.IsEmpty() {
-				if err = common.ValidateVersion(version, options); err != nil {
+				if capabilities.Options == nil {
+					log.Warn("The server does not expose its version limits, the version will be validated by the server.")
+				} else if err = common.ValidateVersion(version, capabilities.Options); err != nil {
 					return err
 				}
 			}
 
 			var encodeSourceCodeInBase64 bool
-			if options.ShouldEncodeSourceCodeInBase64 == nil {
+			if !capabilities.SupportsBase64SourceCode() {
 				if c.IsFlagSet(model.FlagBase64) {
 					log.Warn("The --base64 flag is not supported by this server. It will be ignored.")
 				}
 			} else {
-				encodeSourceCodeInBase64 = *options.ShouldEncodeSourceCodeInBase64 || c.GetBoolFlagValue(model.FlagBase64)
+				encodeSourceCodeInBase64 = capabilities.ShouldEncodeSourceCodeInBase64() || c.GetBoolFlagValue(model.FlagBase64)
 			}
 
 			return (&deployCommandHandler{
@@ -157,77 +152,53 @@ Related: jf worker test-run, jf worker undeploy, jf worker list, jf worker edit-
 				manifest:                 manifest,
 				actionMeta:               actionMeta,
 				version:                  version,
-				serverURL:                server.GetUrl(),
-				token:                    server.GetAccessToken(),
+				server:                   server,
 				encodeSourceCodeInBase64: encodeSourceCodeInBase64,
-				outputFormat:             outputFormat,
+				output:                   output,
 			}).run()
 		},
 	}
 }
 
 func (h *deployCommandHandler) run() error {
-	existingWorker, err := common.FetchWorkerDetails(h.ctx, h.serverURL, h.token, h.manifest.Name, h.manifest.ProjectKey)
+	existingWorker, err := common.FetchWorkerDetails(h.ctx, h.server, h.manifest.Name, h.manifest.ProjectKey)
 	if err != nil {
 		return err
 	}
 
-	body, err := h.prepareRequest(existingWorker)
+	request, err := h.prepareRequest(existingWorker)
 	if err != nil {
 		return err
 	}
 
-	bodyBytes, err := json.Marshal(body)
-	if err != nil {
-		return err
-	}
-
-	var responseStatus int
-	var contentHandler common.APIContentHandler
-	if h.outputFormat != format.None {
-		contentHandler = func(body []byte) error {
-			return common.PrintJSONOrStatus(responseStatus, body)
-		}
-	}
-
 	if existingWorker == nil {
 		log.Info(fmt.Sprintf("Deploying worker '%s'", h.manifest.Name))
-		err = common.CallWorkerAPI(h.ctx, common.APICallParams{
-			Method:        http.MethodPost,
-			ServerURL:     h.serverURL,
-			ServerToken:   h.token,
-			Body:          bodyBytes,
-			OkStatuses:    []int{http.StatusCreated},
-			Path:          []string{"workers"},
-			APIVersion:    common.APIVersionV2,
-			OnContent:     contentHandler,
-			CaptureStatus: &responseStatus,
-		})
-		if err == nil {
-			log.Info(fmt.Sprintf("Worker '%s' deployed", h.manifest.Name))
-		}
-	} else {
-		log.Info(fmt.Sprintf("Updating worker '%s'", h.manifest.Name))
-		err = common.CallWorkerAPI(h.ctx, common.APICallParams{
-			Method:        http.MethodPut,
-			ServerURL:     h.serverURL,
-			ServerToken:   h.token,
-			Body:          bodyBytes,
-			OkStatuses:    []int{http.StatusNoContent},
-			Path:          []string{"workers"},
-			APIVersion:    common.APIVersionV2,
-			OnContent:     contentHandler,
-			CaptureStatus: &responseStatus,
+		err = common.RunWorkerClient(h.ctx, h.server, func(ctx context.Context, client *workerclient.Client) error {
+			return client.CreateWorker(ctx, request)
 		})
-		if err == nil {
-			log.Info(fmt.Sprintf("Worker '%s' updated", h.manifest.Name))
+		if err != nil {
+			return err
 		}
+		log.Info(fmt.Sprintf("Worker '%s' deployed", h.manifest.Name))
+		return h.printStatus(http.StatusCreated)
 	}
 
-	return err
+	log.Info(fmt.Sprintf("Updating worker '%s'", h.manifest.Name))
+	err = common.RunWorkerClient(h.ctx, h.server, func(ctx context.Context, client *workerclient.Client) error {
+		return client.UpdateWorker(ctx, request)
+	})
+	if err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("Worker '%s' updated", h.manifest.Name))
+	return h.printStatus(http.StatusNoContent)
+}
+
+func (h *deployCommandHandler) printStatus(status int) error {
+	return h.output.Print(&common.Result{Value: common.StatusContent{StatusCode: status}})
 }
 
-func (h *deployCommandHandler) prepareRequest(existingWorker *model.WorkerDetails) (*deployRequest, error) {
+func (h *deployCommandHandler) prepareRequest(existingWorker *model.WorkerDetails) (*model.WorkerRequest, error) {
 	sourceCode, err := common.ReadSourceCode(h.manifest)
 	if err != nil {
 		return nil, err
@@ -235,7 +206,7 @@ func (h *deployCommandHandler) prepareRequest(existingWorker *model.WorkerDetail
 	sourceCode = common.CleanImports(sourceCode)
 
 	if h.encodeSourceCodeInBase64 {
-		sourceCode = "base64:" + base64.StdEncoding.EncodeToString([]byte(sourceCode))
+		sourceCode = common.Base64SourceCodePrefix + base64.StdEncoding.EncodeToString([]byte(sourceCode))
 	}
 
 	var secrets []*model.Secret
@@ -243,7 +214,7 @@ func (h *deployCommandHandler) prepareRequest(existingWorker *model.WorkerDetail
 		secrets = common.PrepareSecretsUpdate(h.manifest, existingWorker)
 	}
 
-	payload := &deployRequest{
+	payload := &model.WorkerRequest{
 		Key:         h.manifest.Name,
 		Action:      h.actionMeta.Action,
 		Description: h.manifest.Description,
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -139,7 +140,27 @@ func TestDeployCommand(t *testing.T) {
 				WithDelay(1 * time.Second).
 				WithOptionsEndpoint().
 				WithCreateEndpoint(nil),
-			wantErr: errors.New("request timed out after 500ms"),
+			// init caches the actions but not the options, whose fetch is the first call of deploy to time out
+			wantErr: errors.New("cannot fetch options: request timed out after 500ms"),
+		},
+		{
+			name: "create on a server without options",
+			serverBehavior: common.NewServerStub(t).
+				WithGetOneEndpoint().
+				WithCreateEndpoint(nil),
+			commandArgs: []string{"--" + model.FlagBase64},
+		},
+		{
+			name: "fails if projects are not supported",
+			serverBehavior: common.NewServerStub(t).
+				WithOptionsEndpoint().
+				WithArtifactoryVersionEndpoint("7.90.1").
+				WithGetOneEndpoint().
+				WithCreateEndpoint(nil),
+			patchManifest: func(mf *model.Manifest) {
+				mf.ProjectKey = "proj-1"
+			},
+			wantErr: errors.New("the server does not support projects, Artifactory 7.95.0 or later is required (found 7.90.1)\nHint: Upgrade Artifactory to 7.95.0 or later, or run the command without --project-key to use global workers."),
 		},
 		{
 			name:           "fails if invalid timeout",
@@ -270,7 +291,7 @@ func TestDeployCommand(t *testing.T) {
 	}
 }
 
-func assertDeployRequestEquals(t require.TestingT, want, got *deployRequest) {
+func assertDeployRequestEquals(t require.TestingT, want, got *model.WorkerRequest) {
 	assert.Equalf(t, want.Key, got.Key, "Key mismatch")
 	assert.Equalf(t, want.Description, got.Description, "Description mismatch")
 	assert.Equalf(t, want.Enabled, got.Enabled, "Enabled mismatch")
@@ -293,7 +314,7 @@ func assertDeployRequestEquals(t require.TestingT, want, got *deployRequest) {
 func expectDeployRequest(actionsMeta common.ActionsMetadata, workerName, actionName, projectKey string, secrets ...*model.Secret) common.BodyValidator {
 	return func(t require.TestingT, body []byte) {
 		want := getExpectedDeployRequestForAction(t, actionsMeta, workerName, actionName, projectKey, secrets...)
-		got := &deployRequest{}
+		got := &model.WorkerRequest{}
 		err := json.Unmarshal(body, got)
 		require.NoError(t, err)
 		assertDeployRequestEquals(t, want, got)
@@ -304,7 +325,7 @@ func expectDeployRequestBase64(actionsMeta common.ActionsMetadata, workerName, a
 	return func(t require.TestingT, body []byte) {
 		want := getExpectedDeployRequestForAction(t, actionsMeta, workerName, actionName, projectKey, secrets...)
 		want.SourceCode = "base64:" + base64.StdEncoding.EncodeToString([]byte(want.SourceCode))
-		got := &deployRequest{}
+		got := &model.WorkerRequest{}
 		err := json.Unmarshal(body, got)
 		require.NoError(t, err)
 		assertDeployRequestEquals(t, want, got)
@@ -316,11 +337,11 @@ func getExpectedDeployRequestForAction(
 	actionsMeta common.ActionsMetadata,
 	workerName, actionName, projectKey string,
 	secrets ...*model.Secret,
-) *deployRequest {
+) *model.WorkerRequest {
 	actionMeta, err := actionsMeta.FindAction(actionName)
 	require.NoError(t, err)
 
-	r := &deployRequest{
+	r := &model.WorkerRequest{
 		Key:         workerName,
 		Description: "Run a script on " + actionName,
 		Enabled:     false,
@@ -393,3 +414,104 @@ func TestWorkerDeploy_NoFormat(t *testing.T) {
 	require.NoError(t, runCmd("worker", "deploy"))
 	assert.Empty(t, out.String(), "expected no JSON output when --format is not set, got: %s", out.String())
 }
+
+func TestWorkerDeploy_TraceHTTP(t *testing.T) {
+	tests := []struct {
+		name          string
+		includeSource bool
+	}{
+		{name: "truncate source"},
+		{name: "include source", includeSource: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, token := common.NewMockWorkerServer(t, common.NewServerStub(t).
+				WithDefaultActionsMetadataEndpoint().
+				WithGetOneEndpoint().
+				WithOptionsEndpoint().
+				WithCreateEndpoint(nil))
+
+			runCmd := common.CreateCliRunner(t, GetInitCommand(), GetDeployCommand())
+
+			dir, workerName := common.PrepareWorkerDirForTest(t)
+			require.NoError(t, runCmd("worker", "init", "GENERIC_EVENT", workerName))
+
+			common.PatchManifest(t, func(mf *model.Manifest) {
+				mf.Secrets = model.Secrets{"sec-1": common.MustEncryptSecret(t, "secret-value-1")}
+			})
+
+			harFile := filepath.Join(dir, "trace.har")
+			args := []string{"worker", "deploy", "--" + model.FlagTraceHTTP, harFile}
+			if tt.includeSource {
+				args = append(args, "--"+model.FlagTraceIncludeSource)
+			}
+			require.NoError(t, runCmd(args...))
+
+			content, err := os.ReadFile(harFile)
+			require.NoError(t, err)
+
+			assert.NotContains(t, string(content), token)
+			assert.NotContains(t, string(content), "secret-value-1")
+
+			var har struct {
+				Log struct {
+					Version string `json:"version"`
+					Entries []struct {
+						Time    float64 `json:"time"`
+						Request struct {
+							Method  string `json:"method"`
+							URL     string `json:"url"`
+							Headers []struct {
+								Name  string `json:"name"`
+								Value string `json:"value"`
+							} `json:"headers"`
+							PostData *struct {
+								Text string `json:"text"`
+							} `json:"postData"`
+						} `json:"request"`
+						Response struct {
+							Status int `json:"status"`
+						} `json:"response"`
+						Timings struct {
+							Send    float64 `json:"send"`
+							Wait    float64 `json:"wait"`
+							Receive float64 `json:"receive"`
+						} `json:"timings"`
+					} `json:"entries"`
+				} `json:"log"`
+			}
+			require.NoError(t, json.Unmarshal(content, &har))
+
+			assert.Equal(t, "1.2", har.Log.Version)
+			require.NotEmpty(t, har.Log.Entries)
+
+			createEntry := har.Log.Entries[len(har.Log.Entries)-1]
+			assert.Equal(t, "POST", createEntry.Request.Method)
+			assert.Equal(t, 201, createEntry.Response.Status)
+			assert.GreaterOrEqual(t, createEntry.Time, 0.0)
+			assert.GreaterOrEqual(t, createEntry.Timings.Wait, 0.0)
+
+			for _, header := range createEntry.Request.Headers {
+				if header.Name == "Authorization" {
+					assert.Equal(t, "Bearer ***", header.Value)
+				}
+			}
+
+			require.NotNil(t, createEntry.Request.PostData)
+			var request model.WorkerRequest
+			require.NoError(t, json.Unmarshal([]byte(createEntry.Request.PostData.Text), &request))
+
+			require.Len(t, request.Secrets, 1)
+			assert.Equal(t, "***", request.Secrets[0].Value)
+
+			sourceCode, err := os.ReadFile(filepath.Join(dir, "worker.ts"))
+			require.NoError(t, err)
+			if tt.includeSource {
+				assert.Equal(t, common.CleanImports(string(sourceCode)), request.SourceCode)
+			} else {
+				assert.Contains(t, request.SourceCode, "(truncated,")
+			}
+		})
+	}
+}
@@ -0,0 +1,491 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	plugins_common "github.com/jfrog/jfrog-cli-core/v2/plugins/common"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-client-go/auth"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+
+	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+	"github.com/jfrog/jfrog-cli-platform-services/workerclient"
+)
+
+type doctorStatus string
+
+const (
+	doctorPass doctorStatus = "pass"
+	doctorWarn doctorStatus = "warn"
+	doctorFail doctorStatus = "fail"
+	doctorSkip doctorStatus = "skip"
+)
+
+// A token expiring sooner than this is reported with a warning.
+const doctorTokenExpiryWarning = 7 * 24 * time.Hour
+
+const pingEndpoint = "artifactory/api/system/ping"
+
+type doctorCheck struct {
+	Name    string       `json:"name"`
+	Status  doctorStatus `json:"status"`
+	Message string       `json:"message"`
+	Hint    string       `json:"hint,omitempty"`
+}
+
+type doctorReport struct {
+	Checks []*doctorCheck `json:"checks"`
+}
+
+type doctorHandler struct {
+	ctx        *components.Context
+	server     *common.Server
+	projectKey string
+	manifest   *model.Manifest
+	// Read when the token is checked, reused to report the options
+	options    *model.OptionsMetadata
+	optionsErr error
+	// Nil when the actions cannot be fetched, then the manifest is only validated locally
+	actionsMeta common.ActionsMetadata
+	checks      []*doctorCheck
+}
+
+func GetDoctorCommand() components.Command {
+	return components.Command{
+		Name:        "doctor",
+		Description: "Diagnose the configuration, the connectivity and the permissions needed by the worker commands.",
+		AIDescription: `Run a series of diagnostics and report each one as pass, warn, fail or skip, with a remediation hint. Use it first when another worker command fails in an opaque way, or to validate the setup of a new machine.
+
+Checks, in order:
+- server: the server details are resolved like the other commands (the JFROG_WORKER_CLI_DEV_SERVER_URL / JFROG_WORKER_CLI_DEV_ACCESS_TOKEN env vars, else --server-id, else the default server).
+- connectivity / tls: the platform answers, and its certificate is trusted (JFROG_WORKER_CLI_CA_CERTS_PATH, JFROG_WORKER_CLI_INSECURE_TLS).
+- token: the access token is accepted by the server and not about to expire.
+- options / actions: the Worker Service metadata endpoints answer, and projects are supported when a project key is used.
+- list-permission / create-permission: the token can list and create workers in the project.
+- manifest: the manifest.json of the current directory is valid for the server.
+- secrets: the secrets password decrypts every secret of the manifest.
+
+When to use:
+- Onboarding a developer or a CI agent.
+- Investigating 401/403 errors, TLS errors or timeouts.
+- Before a deployment, to check the manifest and its secrets in one go.
+
+Prerequisites:
+- None: failed checks are reported with a hint. Run from a worker directory to also check its manifest and secrets.
+
+Common patterns:
+  $ jf worker doctor
+  $ jf worker doctor --server-id my-server --project-key my-project
+  $ jf worker doctor --format json
+  $ jf worker doctor --query '.checks[] | select(.status == "fail") | .name' --format csv
+
+Gotchas:
+- The command exits with a non-zero code when at least one check fails; warnings do not change the exit code.
+- The command only sends read requests: the create permission is reported as failed when the token cannot list the workers, and skipped otherwise, as only a deployment can confirm it.
+- The project key defaults to the one of the manifest.
+- The default text format is a report with hints and a summary; the table and csv formats have one row per check, with its hint.
+- The secrets password is read from JFROG_WORKER_CLI_DEV_SECRETS_PASSWORD, or prompted when the manifest has secrets.
+
+Related: jf worker list-event, jf worker deploy, jf worker add-secret`,
+		SupportedFormats: common.TextOutputFormats,
+		DefaultFormat:    common.FormatText,
+		Flags: append([]components.Flag{
+			plugins_common.GetServerIdFlag(),
+			model.GetTimeoutFlag(),
+			model.GetProjectKeyFlag(),
+			model.GetTemplateFlag(),
+			model.GetQueryFlag(),
+		}, model.GetHTTPClientFlags()...),
+		Action: func(c *components.Context) error {
+			output, err := common.NewOutput(c)
+			if err != nil {
+				return err
+			}
+
+			h := &doctorHandler{ctx: c}
+			h.run()
+
+			table := &common.Table{Headers: []string{"STATUS", "CHECK", "MESSAGE", "HINT"}}
+			for _, check := range h.checks {
+				table.Rows = append(table.Rows, []string{string(check.Status), check.Name, check.Message, check.Hint})
+			}
+
+			if err = output.Print(&common.Result{
+				Value: doctorReport{Checks: h.checks},
+				Items: h.checks,
+				Table: table,
+				Text:  h.printReport,
+			}); err != nil {
+				return err
+			}
+
+			return h.result()
+		},
+	}
+}
+
+func (h *doctorHandler) run() {
+	h.loadManifest()
+
+	serverReady := h.checkServer() && h.checkConnectivity() && h.checkToken()
+	if serverReady {
+		h.checkOptions()
+		h.checkActions()
+		h.checkCreatePermission(h.checkListPermission())
+	} else {
+		h.skip("options", "actions", "list-permission", "create-permission")
+	}
+
+	h.checkManifest()
+	h.checkSecrets()
+}
+
+func (h *doctorHandler) loadManifest() {
+	h.projectKey = h.ctx.GetStringFlagValue(model.FlagProjectKey)
+
+	manifest, err := common.ReadManifest()
+	if err != nil {
+		log.Debug(fmt.Sprintf("No manifest read: %+v", err))
+		return
+	}
+
+	h.manifest = manifest
+	if h.projectKey == "" {
+		h.projectKey = manifest.ProjectKey
+	}
+}
+
+func (h *doctorHandler) report(name string, status doctorStatus, hint string, message string, args ...any) {
+	h.checks = append(h.checks, &doctorCheck{Name: name, Status: status, Message: fmt.Sprintf(message, args...), Hint: hint})
+}
+
+func (h *doctorHandler) reportError(name string, err error, message string, args ...any) {
+	check := &doctorCheck{Name: name, Status: doctorFail, Message: fmt.Sprintf(message, args...) + ": " + err.Error()}
+	var apiErr *common.APIError
+	if errors.As(err, &apiErr) {
+		check.Message = fmt.Sprintf(message, args...) + ": " + strings.SplitN(apiErr.Error(), "\n", 2)[0]
+		check.Hint = apiErr.Hint
+	}
+	h.checks = append(h.checks, check)
+}
+
+func (h *doctorHandler) skip(names ...string) {
+	for _, name := range names {
+		h.report(name, doctorSkip, "", "Skipped, a previous check failed")
+	}
+}
+
+func (h *doctorHandler) checkServer() bool {
+	server, err := common.GetServerDetails(h.ctx)
+	if err != nil {
+		h.report("server", doctorFail, "Configure a server with 'jf login' or 'jf c add', or select one with --server-id.", "Cannot resolve the server details: %s", err.Error())
+		h.skip("connectivity", "tls", "token")
+		return false
+	}
+	h.server = server
+
+	source := "the default server"
+	_, hasURLEnv := os.LookupEnv(model.EnvKeyServerURL)
+	_, hasTokenEnv := os.LookupEnv(model.EnvKeyAccessToken)
+	switch {
+	case hasURLEnv && hasTokenEnv:
+		source = fmt.Sprintf("the %s and %s environment variables", model.EnvKeyServerURL, model.EnvKeyAccessToken)
+	case server.ServerId != "":
+		source = fmt.Sprintf("the server '%s'", server.ServerId)
+	}
+
+	if server.GetUrl() == "" {
+		h.report("server", doctorFail, "Re-run 'jf c add' to set the platform URL of the server.", "No URL found in %s", source)
+		h.skip("connectivity", "tls", "token")
+		return false
+	}
+
+	h.report("server", doctorPass, "", "Using %s from %s", server.GetUrl(), source)
+	return true
+}
+
+func (h *doctorHandler) checkConnectivity() bool {
+	err := common.RunWorkerClient(h.ctx, h.server, func(ctx context.Context, client *workerclient.Client) error {
+		_, err := client.Do(ctx, &workerclient.Request{Method: http.MethodGet, Endpoint: pingEndpoint, OkStatuses: []int{http.StatusOK}})
+		return err
+	})
+
+	// Any status means that the server was reached, the ping endpoint is not available on every platform
+	exitCode := common.ExitCodeOf(err)
+	reached := err == nil || (exitCode != common.ExitCodeNetworkError && exitCode != common.ExitCodeTimeout && exitCode != common.ExitCodeError)
+
+	switch {
+	case reached:
+		h.report("connectivity", doctorPass, "", "The server answers at %s", h.server.GetUrl())
+	case isTLSError(err):
+		h.report("connectivity", doctorFail, "", "The connection to %s failed", h.server.GetUrl())
+		h.report("tls", doctorFail,
+			fmt.Sprintf("Trust the certificate authority of the server with %s=<PEM file or directory>, or, on a test server only, skip the verification with %s=true.", model.EnvKeyCACertsPath, model.EnvKeyInsecureTLS),
+			"The certificate of the server is not trusted: %s", err.Error())
+		h.skip("token")
+		return false
+	case exitCode == common.ExitCodeTimeout:
+		h.report("connectivity", doctorFail, fmt.Sprintf("Check the URL, the proxy settings and the VPN, or increase --%s.", model.FlagTimeout), "%s", err.Error())
+		h.skip("tls", "token")
+		return false
+	default:
+		h.report("connectivity", doctorFail, "Check the URL of the server, the proxy settings (HTTPS_PROXY, NO_PROXY) and the VPN.", "Cannot reach %s: %s", h.server.GetUrl(), err.Error())
+		h.skip("tls", "token")
+		return false
+	}
+
+	_, insecureFromEnv := os.LookupEnv(model.EnvKeyInsecureTLS)
+	switch {
+	case !strings.HasPrefix(strings.ToLower(h.server.GetUrl()), "https://"):
+		h.report("tls", doctorWarn, "Use the https:// URL of the platform.", "The connection is not encrypted, the token is sent in clear text")
+	case h.server.InsecureTls || (insecureFromEnv && os.Getenv(model.EnvKeyInsecureTLS) == "true"):
+		h.report("tls", doctorWarn, fmt.Sprintf("Trust the certificate authority of the server with %s instead.", model.EnvKeyCACertsPath), "The certificate of the server is not verified")
+	default:
+		h.report("tls", doctorPass, "", "The certificate of the server is trusted")
+	}
+
+	return true
+}
+
+func (h *doctorHandler) checkToken() bool {
+	token := h.server.GetAccessToken()
+	if token == "" {
+		h.report("token", doctorFail, "The worker commands need an access token, run 'jf login' or 'jf c add --access-token'.", "No access token configured")
+		return false
+	}
+
+	h.options, h.optionsErr = common.CallWorkerClient(h.ctx, h.server, func(ctx context.Context, client *workerclient.Client) (*model.OptionsMetadata, error) {
+		return client.Options(ctx)
+	})
+	if h.optionsErr != nil && common.ExitCodeOf(h.optionsErr) == common.ExitCodeUnauthorized {
+		h.reportError("token", h.optionsErr, "The access token is rejected by the server")
+		return false
+	}
+
+	expiry, hasExpiry := readTokenExpiry(token)
+	switch {
+	case !hasExpiry:
+		h.report("token", doctorPass, "", "The access token is accepted by the server")
+	case time.Until(expiry) < time.Minute:
+		h.report("token", doctorFail, "Re-run 'jf login' to get a new token.", "The access token has expired")
+		return false
+	case time.Until(expiry) < doctorTokenExpiryWarning:
+		h.report("token", doctorWarn, "Re-run 'jf login', or create a token with a longer expiry.", "The access token expires on %s", expiry.Format(time.RFC3339))
+	default:
+		h.report("token", doctorPass, "", "The access token is accepted by the server, it expires on %s", expiry.Format(time.RFC3339))
+	}
+
+	return true
+}
+
+func (h *doctorHandler) checkOptions() {
+	if h.optionsErr != nil {
+		if common.ExitCodeOf(h.optionsErr) == common.ExitCodeNotFound {
+			h.report("options", doctorWarn, "Upgrade the server to benefit from the latest features of the Worker Service.", "The server does not expose the options of the Worker Service, it may be outdated")
+			return
+		}
+		h.reportError("options", h.optionsErr, "Cannot read the options of the Worker Service")
+		return
+	}
+
+	artifactoryVersion, err := common.CallWorkerClient(h.ctx, h.server, func(ctx context.Context, client *workerclient.Client) (string, error) {
+		return client.ArtifactoryVersion(ctx)
+	})
+	if err != nil {
+		log.Debug(fmt.Sprintf("Cannot read the version of Artifactory: %+v", err))
+	}
+
+	capabilities := &common.Capabilities{ArtifactoryVersion: artifactoryVersion, Options: h.options}
+	if err = common.RequireProjectSupport(h.projectKey).Check(capabilities); err != nil {
+		h.reportError("options", err, "Project '%s' cannot be used", h.projectKey)
+		return
+	}
+
+	message := "The Worker Service options are available"
+	if artifactoryVersion != "" {
+		message += fmt.Sprintf(" (Artifactory %s)", artifactoryVersion)
+	}
+	if !capabilities.SupportsExecutionHistory() {
+		h.report("options", doctorWarn, "Ask your administrator to enable the execution history to troubleshoot the workers executions.", "%s, the execution history is disabled", message)
+		return
+	}
+	h.report("options", doctorPass, "", "%s", message)
+}
+
+func (h *doctorHandler) checkActions() {
+	actions, err := common.CallWorkerClient(h.ctx, h.server, func(ctx context.Context, client *workerclient.Client) ([]*model.ActionMetadata, error) {
+		return client.Actions(ctx, h.projectKey)
+	})
+	if err != nil {
+		h.reportError("actions", err, "Cannot list the actions%s", h.inProject())
+		return
+	}
+
+	h.actionsMeta = actions
+	if len(actions) == 0 {
+		h.report("actions", doctorWarn, "Check that the Worker Service is enabled on the platform.", "No actions available%s", h.inProject())
+		return
+	}
+	h.report("actions", doctorPass, "", "%d actions available%s", len(actions), h.inProject())
+}
+
+// checkListPermission returns the error of the listing, nil when the token can list the workers.
+func (h *doctorHandler) checkListPermission() error {
+	workers, err := common.CallWorkerClient(h.ctx, h.server, func(ctx context.Context, client *workerclient.Client) ([]*model.WorkerDetails, error) {
+		return client.ListWorkers(ctx, workerclient.ListWorkersOptions{ProjectKey: h.projectKey})
+	})
+	if err != nil {
+		h.reportError("list-permission", err, "Cannot list the workers%s", h.inProject())
+		return err
+	}
+	h.report("list-permission", doctorPass, "", "The token can list the workers%s (%d found)", h.inProject(), len(workers))
+	return nil
+}
+
+// checkCreatePermission reports the permission to create workers without sending any write request: a token that cannot list
+// the workers cannot manage them either, whereas the permission of a token that can list them is only confirmed by a deployment.
+func (h *doctorHandler) checkCreatePermission(listErr error) {
+	if status := common.ExitCodeOf(listErr); listErr != nil && (status == common.ExitCodeForbidden || status == common.ExitCodeUnauthorized) {
+		h.report("create-permission", doctorFail,
+			"Ask your administrator for the 'Manage' permission on workers"+h.inProject()+", or use a token of a user who has it.",
+			"The token cannot create workers%s", h.inProject())
+		return
+	}
+	h.report("create-permission", doctorSkip, "", "Cannot be checked without creating a worker%s, 'jf worker deploy' reports a missing permission", h.inProject())
+}
+
+func (h *doctorHandler) checkManifest() {
+	if h.manifest == nil {
+		if _, err := os.Stat("manifest.json"); err == nil {
+			h.report("manifest", doctorFail, "Fix the JSON syntax of manifest.json.", "Cannot read manifest.json")
+		} else {
+			h.report("manifest", doctorSkip, "", "No manifest.json in the current directory")
+		}
+		return
+	}
+
+	if err := common.ValidateManifest(h.manifest, h.actionsMeta); err != nil {
+		h.report("manifest", doctorFail, "Fix manifest.json, 'jf worker list-event' lists the valid actions.", "%s", err.Error())
+		return
+	}
+
+	if _, err := common.ReadSourceCode(h.manifest); err != nil {
+		h.report("manifest", doctorFail, "Fix the sourceCodePath of manifest.json.", "Cannot read the source code %s: %s", h.manifest.SourceCodePath, err.Error())
+		return
+	}
+
+	if h.actionsMeta == nil {
+		h.report("manifest", doctorWarn, "", "Worker '%s' is valid, but its action could not be checked against the server", h.manifest.Name)
+		return
+	}
+
+	actionMeta, err := h.actionsMeta.FindAction(h.manifest.Action, h.manifest.Application)
+	if err == nil {
+		err = common.ValidateFilterCriteria(h.manifest.FilterCriteria, actionMeta)
+	}
+	if err != nil {
+		h.report("manifest", doctorFail, "Fix the filterCriteria of manifest.json.", "%s", err.Error())
+		return
+	}
+
+	h.report("manifest", doctorPass, "", "Worker '%s' (%s) is valid", h.manifest.Name, h.manifest.Action)
+}
+
+func (h *doctorHandler) checkSecrets() {
+	if h.manifest == nil || len(h.manifest.Secrets) == 0 {
+		h.report("secrets", doctorSkip, "", "No secrets to decrypt")
+		return
+	}
+
+	password, err := common.ReadSecretPassword("Secrets Password: ")
+	if err != nil {
+		h.report("secrets", doctorFail, fmt.Sprintf("Provide the password with %s or at the prompt.", model.EnvKeySecretsPassword), "Cannot read the secrets password: %s", err.Error())
+		return
+	}
+
+	var failed []string
+	for name, value := range h.manifest.Secrets {
+		if _, err = common.DecryptSecret(password, value); err != nil {
+			log.Debug(fmt.Sprintf("cannot decrypt secret '%s': %+v", name, err))
+			failed = append(failed, name)
+		}
+	}
+	slices.Sort(failed)
+
+	if len(failed) > 0 {
+		h.report("secrets", doctorFail,
+			"Check the password, or encrypt the secrets again with 'jf worker add-secret --edit <name>'.",
+			"Cannot decrypt %d of %d secrets: %s", len(failed), len(h.manifest.Secrets), strings.Join(failed, ", "))
+		return
+	}
+
+	h.report("secrets", doctorPass, "", "The %d secrets are decrypted with the password", len(h.manifest.Secrets))
+}
+
+func (h *doctorHandler) inProject() string {
+	if h.projectKey == "" {
+		return ""
+	}
+	return fmt.Sprintf(" in project '%s'", h.projectKey)
+}
+
+func (h *doctorHandler) printReport() error {
+	counts := map[doctorStatus]int{}
+	for _, check := range h.checks {
+		counts[check.Status]++
+		if err := common.Print("%-5s %-18s %s\n", strings.ToUpper(string(check.Status)), check.Name, check.Message); err != nil {
+			return err
+		}
+		if check.Hint != "" && (check.Status == doctorFail || check.Status == doctorWarn) {
+			if err := common.Print("%-24s Hint: %s\n", "", check.Hint); err != nil {
+				return err
+			}
+		}
+	}
+	return common.Print("\n%d passed, %d warnings, %d failed, %d skipped\n", counts[doctorPass], counts[doctorWarn], counts[doctorFail], counts[doctorSkip])
+}
+
+// result fails when at least one check failed, the details are already part of the report.
+func (h *doctorHandler) result() error {
+	failed := 0
+	for _, check := range h.checks {
+		if check.Status == doctorFail {
+			failed++
+		}
+	}
+	if failed == 0 {
+		return nil
+	}
+	return common.ErrorAlreadyReported(fmt.Errorf("%d of %d checks failed", failed, len(h.checks)))
+}
+
+// readTokenExpiry returns the expiry of a JWT access token, to the minute. Reference tokens and the tokens that never expire have no readable expiry.
+func readTokenExpiry(token string) (time.Time, bool) {
+	lifetime, err := auth.ExtractExpiryFromAccessToken(token)
+	if err != nil || lifetime <= 0 {
+		return time.Time{}, false
+	}
+
+	minutesLeft, err := auth.GetTokenMinutesLeft(token)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return time.Now().Add(time.Duration(minutesLeft) * time.Minute), true
+}
+
+func isTLSError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "x509:") || strings.Contains(msg, "tls:") || strings.Contains(msg, "certificate")
+}
@@ -0,0 +1,230 @@
+//go:build test
+// +build test
+
+package commands
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/common/format"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+)
+
+func TestDoctor(t *testing.T) {
+	tests := []struct {
+		name          string
+		serverStub    *common.ServerStub
+		patchManifest func(mf *model.Manifest)
+		noManifest    bool
+		wantStatuses  map[string]doctorStatus
+		wantErr       string
+	}{
+		{
+			name: "all checks pass",
+			serverStub: common.NewServerStub(t).
+				WithOptionsEndpoint().
+				WithArtifactoryVersionEndpoint("7.104.2").
+				WithGetAllEndpoint(),
+			patchManifest: func(mf *model.Manifest) {
+				mf.Secrets = model.Secrets{
+					"sec-1": common.MustEncryptSecret(t, "val-1"),
+					"sec-2": common.MustEncryptSecret(t, "val-2"),
+				}
+			},
+			wantStatuses: map[string]doctorStatus{
+				"server":            doctorPass,
+				"connectivity":      doctorPass,
+				"tls":               doctorWarn,
+				"token":             doctorPass,
+				"options":           doctorPass,
+				"actions":           doctorPass,
+				"list-permission":   doctorPass,
+				"create-permission": doctorSkip,
+				"manifest":          doctorPass,
+				"secrets":           doctorPass,
+			},
+		},
+		{
+			name: "outside of a worker directory",
+			serverStub: common.NewServerStub(t).
+				WithOptionsEndpoint().
+				WithGetAllEndpoint(),
+			noManifest: true,
+			wantStatuses: map[string]doctorStatus{
+				"server":            doctorPass,
+				"connectivity":      doctorPass,
+				"tls":               doctorWarn,
+				"token":             doctorPass,
+				"options":           doctorPass,
+				"actions":           doctorPass,
+				"list-permission":   doctorPass,
+				"create-permission": doctorSkip,
+				"manifest":          doctorSkip,
+				"secrets":           doctorSkip,
+			},
+		},
+		{
+			name: "forbidden",
+			serverStub: common.NewServerStub(t).
+				WithToken("another-token").
+				WithOptionsEndpoint().
+				WithGetAllEndpoint(),
+			wantStatuses: map[string]doctorStatus{
+				"server":            doctorPass,
+				"connectivity":      doctorPass,
+				"tls":               doctorWarn,
+				"token":             doctorPass,
+				"options":           doctorFail,
+				"actions":           doctorFail,
+				"list-permission":   doctorFail,
+				"create-permission": doctorFail,
+				"manifest":          doctorWarn,
+				"secrets":           doctorSkip,
+			},
+			wantErr: "4 of 10 checks failed",
+		},
+		{
+			name: "invalid manifest and secrets",
+			serverStub: common.NewServerStub(t).
+				WithOptionsEndpoint().
+				WithGetAllEndpoint(),
+			patchManifest: func(mf *model.Manifest) {
+				mf.Action = "HACK_SYSTEM"
+				mf.Secrets = model.Secrets{
+					"sec-1": common.MustEncryptSecret(t, "val-1"),
+					"sec-2": common.MustEncryptSecret(t, "val-2", "another-password"),
+				}
+			},
+			wantStatuses: map[string]doctorStatus{
+				"server":            doctorPass,
+				"connectivity":      doctorPass,
+				"tls":               doctorWarn,
+				"token":             doctorPass,
+				"options":           doctorPass,
+				"actions":           doctorPass,
+				"list-permission":   doctorPass,
+				"create-permission": doctorSkip,
+				"manifest":          doctorFail,
+				"secrets":           doctorFail,
+			},
+			wantErr: "2 of 10 checks failed",
+		},
+		{
+			name: "unsupported project",
+			serverStub: common.NewServerStub(t).
+				WithOptionsEndpoint().
+				WithArtifactoryVersionEndpoint("7.90.1").
+				WithGetAllEndpoint(),
+			patchManifest: func(mf *model.Manifest) {
+				mf.ProjectKey = "proj-1"
+			},
+			wantStatuses: map[string]doctorStatus{
+				"server":            doctorPass,
+				"connectivity":      doctorPass,
+				"tls":               doctorWarn,
+				"token":             doctorPass,
+				"options":           doctorFail,
+				"actions":           doctorPass,
+				"list-permission":   doctorPass,
+				"create-permission": doctorSkip,
+				"manifest":          doctorPass,
+				"secrets":           doctorSkip,
+			},
+			wantErr: "1 of 10 checks failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			common.NewMockWorkerServer(t, tt.serverStub.WithT(t).WithDefaultActionsMetadataEndpoint())
+
+			runCmd := common.CreateCliRunner(t, GetInitCommand(), GetDoctorCommand())
+
+			_, workerName := common.PrepareWorkerDirForTest(t)
+			if !tt.noManifest {
+				require.NoError(t, runCmd("worker", "init", "--"+model.FlagActionsFile, writeSampleActionsFile(t), "BEFORE_DOWNLOAD", workerName))
+			}
+			if tt.patchManifest != nil {
+				common.PatchManifest(t, tt.patchManifest)
+			}
+
+			var out bytes.Buffer
+			common.SetCliOut(&out)
+			t.Cleanup(func() { common.SetCliOut(os.Stdout) })
+
+			err := runCmd("worker", "doctor", "--"+format.FlagName, "json")
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.wantErr)
+				assert.Equal(t, common.ExitCodeError, common.ExitCodeOf(err))
+			}
+
+			// The errors are part of the report, the output is a single JSON document
+			var report doctorReport
+			require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+
+			gotStatuses := map[string]doctorStatus{}
+			for _, check := range report.Checks {
+				gotStatuses[check.Name] = check.Status
+				if check.Status == doctorFail {
+					assert.NotEmptyf(t, check.Hint, "No hint for the failed check %s", check.Name)
+				}
+			}
+			assert.Equal(t, tt.wantStatuses, gotStatuses)
+		})
+	}
+}
+
+func TestDoctor_Unreachable(t *testing.T) {
+	server, _ := common.NewMockWorkerServer(t, common.NewServerStub(t))
+	server.Close()
+
+	var out bytes.Buffer
+	common.SetCliOut(&out)
+	t.Cleanup(func() { common.SetCliOut(os.Stdout) })
+
+	common.PrepareWorkerDirForTest(t)
+
+	runCmd := common.CreateCliRunner(t, GetDoctorCommand())
+	err := runCmd("worker", "doctor", "--"+model.FlagRetries, "0")
+	require.EqualError(t, err, "1 of 10 checks failed")
+
+	assert.Regexp(t, `(?m)^FAIL\s+connectivity\s+Cannot reach `, out.String())
+	assert.Regexp(t, `(?m)^\s+Hint: Check the URL of the server`, out.String())
+	assert.Regexp(t, `(?m)^SKIP\s+token\s+`, out.String())
+	assert.Contains(t, out.String(), "1 passed, 0 warnings, 1 failed, 8 skipped")
+}
+
+func TestReadTokenExpiry(t *testing.T) {
+	expiry := time.Now().Add(time.Hour).Truncate(time.Second)
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"sub":"user","exp":%d}`, expiry.Unix())))
+
+	got, found := readTokenExpiry("header." + payload + ".signature")
+	require.True(t, found)
+	assert.WithinDuration(t, expiry, got, time.Minute)
+
+	expired := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"sub":"user","iat":%d,"exp":%d}`, time.Now().Add(-2*time.Hour).Unix(), time.Now().Add(-time.Hour).Unix())))
+	got, found = readTokenExpiry("header." + expired + ".signature")
+	require.True(t, found)
+	assert.True(t, time.Until(got) < time.Minute)
+
+	_, found = readTokenExpiry("a-reference-token")
+	assert.False(t, found)
+}
+
+func writeSampleActionsFile(t *testing.T) string {
+	actionsFile := t.TempDir() + "/actions.json"
+	require.NoError(t, os.WriteFile(actionsFile, []byte(common.MustJsonMarshal(t, common.LoadSampleActions(t))), 0o600))
+	return actionsFile
+}
@@ -1,9 +1,12 @@
 package commands
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/jfrog/jfrog-cli-core/v2/common/format"
 	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
@@ -14,17 +17,15 @@ import (
 	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
 
 	"github.com/jfrog/jfrog-cli-platform-services/model"
+	"github.com/jfrog/jfrog-cli-platform-services/workerclient"
 )
 
+const flagDryRunSample = "sample"
+
 type dryRunHandler struct {
 	ctx *components.Context
-}
-
-type dryRunRequest struct {
-	Code          string          `json:"code"`
-	Action        string          `json:"action"`
-	StagedSecrets []*model.Secret `json:"stagedSecrets,omitempty"`
-	Data          map[string]any  `json:"data"`
+	// validator checks the values returned by the worker, nil when the action has no response type
+	validator *common.ResponseValidator
 }
 
 func GetDryRunCommand() components.Command {
@@ -47,46 +48,90 @@ Common patterns:
   $ jf worker test-run '{"repoPath":"my-repo/path/to/artifact"}'
   $ jf worker test-run @./sample-payload.json
   $ jf worker test-run @- < sample-payload.json
+  $ jf worker test-run --sample
+  $ jf worker test-run @payloads/sample.json              # written by 'jf worker init'
   $ jf worker test-run --no-secrets '{}'
+  $ jf worker test-run --strict @payloads/sample.json      # fail when the returned value does not match the response type
+  $ jf worker test-run --watch @payloads/sample.json
+  $ jf worker test-run --compare-deployed @payloads/sample.json
+  $ jf worker test-run --compare-deployed --fixtures ./fixtures --format json
+  $ jf worker test-run --format text @./sample-payload.json
   $ jf worker test-run --format table '{}'
+  $ jf worker test-run --format yaml @./sample-payload.json
+  $ jf worker test-run --query '.logs' '{}'
+  $ jf worker test-run --fixtures ./fixtures --junit-report fixtures.xml
+  $ jf worker test-run --fixtures ./fixtures --update
+  $ jf worker test-run --fuzz 50
+  $ jf worker test-run --fuzz 50 --seed 1718 --save-failures ./fixtures
 
 Gotchas:
 - The payload argument is required and must match what the action delivers at runtime; check types.ts for the expected shape.
 - Use '@filename' to load the payload from a file and '@-' to read it from stdin.
+- --sample sends the sample payload of the action as provided by the server; 'jf worker sample-payload --set' saves a variant of it to edit some values.
 - By default, secrets in manifest.json are decrypted and sent as staged secrets; pass --no-secrets to omit them.
 - The 'debug' flag in manifest.json controls whether debug logs are returned by the sandbox.
+- With --fixtures, every <name>.json of the directory is run (up to --concurrency at a time) and its response compared to <name>.expected.json; the logs and timestamps are not compared (see --ignore-paths), and --update writes the expected files from the current responses.
+- The fixtures are reported as text by default, and with --format json, csv or table; the command exits with an error when a fixture fails or has no expected file.
+- --fuzz generates payloads from the executionRequestType of the action in its typesDefinitions: every enum member (UNRECOGNIZED included), empty and unusual strings, missing optional fields and large arrays. A payload fails when the request fails, the execution status is not a success, the response has neither data nor status or the returned value does not match the response type.
+- The seed is printed at the start of a --fuzz run; pass it back with --seed to replay the same payloads. --save-failures writes the failing payloads as <name>.json fixtures, to be completed by 'test-run --fixtures --update' once the worker is fixed.
+- The value returned by the worker is checked against the response type of the action (e.g. BeforeDownloadResponse in types.ts): the missing fields, the wrong types and the invalid enum values such as UNRECOGNIZED are logged as warnings, and --strict makes the command fail on them. The actions without a response type, such as GENERIC_EVENT, are not checked.
+- --watch runs the worker, then runs it again each time manifest.json, the source code, the files it imports with a relative path or the @file payload change; the run starts once the files are left unchanged for a moment, the screen is cleared and the changes of the result since the previous run are listed below it. The secrets password is asked once for the session, Ctrl-C ends it.
+- In watch mode the errors (invalid manifest or payload, failed request) are printed and the session goes on, a payload read from stdin is read once; --strict does not apply.
+- --compare-deployed runs each payload twice in the sandbox, with the local source and with the source of the deployed worker (decoded when deployed in base64), with the same secrets. The results are compared path by path (see --ignore-paths with --fixtures) and the logs line by line without their timestamps; the command fails when a payload behaves differently, which makes it a regression check before 'jf worker deploy'.
+- With --compare-deployed and --fixtures, only the <name>.json payloads are read; --update and --junit-report do not apply.
+- The json and yaml formats print the response as returned, under the name of the event; --format text unwraps it and shows the status, the duration, the returned value and the logs in order, and --format table lists its dotted paths in a stable order.
+- The actions metadata are cached per server for an hour (JFROG_WORKER_CLI_METADATA_CACHE_TTL); pass --refresh-metadata to download them again, e.g. after installing an application.
+- On machines that cannot reach the server, pass --actions-file with the output of 'jf worker export-metadata' or 'jf worker list-event --format json'.
 
 Related: jf worker deploy, jf worker execute, jf worker init`,
 		Aliases:          []string{"dry-run", "dr", "tr"},
-		SupportedFormats: []format.OutputFormat{format.Json, format.Table},
+		SupportedFormats: common.TextOutputFormats,
 		DefaultFormat:    format.Json,
-		Flags: []components.Flag{
+		Flags: slices.Concat([]components.Flag{
 			plugins_common.GetServerIdFlag(),
 			model.GetTimeoutFlag(),
 			model.GetNoSecretsFlag(),
-		},
+			model.GetStrictFlag(),
+			components.NewBoolFlag(flagDryRunWatch, "Run the worker again each time manifest.json, the source code, its local imports or the payload file change, until Ctrl-C.", components.WithBoolDefaultValue(false)),
+			components.NewBoolFlag(flagCompareDeployed, "Run the payload with the deployed source too, and print how the results and the logs of the local source differ.", components.WithBoolDefaultValue(false)),
+			components.NewBoolFlag(flagDryRunSample, "Use the sample payload of the action of manifest.json instead of a payload argument.", components.WithBoolDefaultValue(false)),
+			components.NewStringFlag(flagFixtures, "A directory of <name>.json payloads to run, each result is compared to <name>.expected.json.", components.WithStrDefaultValue("")),
+			components.NewBoolFlag(flagFixturesUpdate, "Write the results of the fixtures to their expected files instead of failing.", components.WithBoolDefaultValue(false)),
+			components.NewStringFlag(flagFixturesIgnorePaths, "A comma-separated list of the paths not compared with --fixtures, e.g. logs,**.timestamp.", components.WithStrDefaultValue(strings.Join(defaultFixturesIgnorePaths, ","))),
+			components.NewStringFlag(flagFixturesJUnitReport, "A file where the results of the fixtures are written in the JUnit XML format.", components.WithStrDefaultValue("")),
+			components.NewStringFlag(flagFuzz, "Run this number of random payloads generated from the request type of the action, and report the ones making the worker fail.", components.WithIntDefaultValue(0)),
+			components.NewStringFlag(flagFuzzSeed, "The seed of the random payloads of --fuzz, a new seed is picked and printed if omitted.", components.WithStrDefaultValue("")),
+			components.NewStringFlag(flagFuzzSaveFailures, "A directory where the payloads failing with --fuzz are saved as fixtures.", components.WithStrDefaultValue("")),
+			model.GetConcurrencyFlag("The number of fixtures or generated payloads run at the same time.", fixturesConcurrency),
+			model.GetTemplateFlag(),
+			model.GetQueryFlag(),
+		}, model.GetMetadataFlags(), model.GetHTTPClientFlags()),
 		Arguments: []components.Argument{
 			model.GetJSONPayloadArgument(),
 		},
 		Action: func(c *components.Context) error {
-			outputFormat, err := c.GetOutputFormat()
+			output, err := common.NewOutput(c)
 			if err != nil {
 				return err
 			}
 
-			h := &dryRunHandler{c}
+			h := &dryRunHandler{ctx: c}
 
 			manifest, err := common.ReadManifest()
 			if err != nil {
 				return err
 			}
 
-			server, err := model.GetServerDetails(c)
+			server, err := common.GetServerDetails(c)
 			if err != nil {
 				return err
 			}
 
-			actionsMeta, err := common.FetchActions(c, server.GetUrl(), server.GetAccessToken(), manifest.ProjectKey)
+			if err = common.CheckProjectSupport(c, server, manifest.ProjectKey); err != nil {
+				return err
+			}
+
+			actionsMeta, err := common.FetchActions(c, server, manifest.ProjectKey)
 			if err != nil {
 				return err
 			}
@@ -95,71 +140,155 @@ Related: jf worker deploy, jf worker execute, jf worker init`,
 				return err
 			}
 
-			inputReader := common.NewInputReader(c)
+			h.validator = getResponseValidator(c, actionsMeta, manifest.Action, manifest.Application)
 
-			data, err := inputReader.ReadData()
+			fixtures, err := getFixturesOptions(h)
 			if err != nil {
 				return err
 			}
 
+			fuzz, err := getFuzzOptions(h, fixtures)
+			if err != nil {
+				return err
+			}
+
+			watch := c.GetBoolFlagValue(flagDryRunWatch)
+			if watch && (fixtures.dir != "" || fuzz.runs > 0) {
+				return fmt.Errorf("--%s cannot be combined with --%s or --%s", flagDryRunWatch, flagFixtures, flagFuzz)
+			}
+
+			compare := c.GetBoolFlagValue(flagCompareDeployed)
+			switch {
+			case compare && (fuzz.runs > 0 || watch):
+				return fmt.Errorf("--%s cannot be combined with --%s or --%s", flagCompareDeployed, flagFuzz, flagDryRunWatch)
+			case compare && (fixtures.update || fixtures.junitReport != ""):
+				return fmt.Errorf("--%s cannot be combined with --%s or --%s, the expected files are not read", flagCompareDeployed, flagFixturesUpdate, flagFixturesJUnitReport)
+			}
+
+			var data map[string]any
+			switch {
+			case fuzz.runs > 0:
+			case c.GetBoolFlagValue(flagDryRunSample):
+				if data, err = h.getSamplePayload(manifest, actionsMeta, fixtures); err != nil {
+					return err
+				}
+			case fixtures.dir == "":
+				if data, err = common.NewInputReader(c).ReadData(); err != nil {
+					return err
+				}
+			}
+
+			// The reports of the fixtures, of the generated payloads, of the comparisons and of the watch mode are read by humans first
+			if (fixtures.dir != "" || fuzz.runs > 0 || watch || compare) && !slices.Contains(c.FlagsUsed, format.FlagName) && c.GetStringFlagValue(model.FlagQuery) == "" && c.GetStringFlagValue(model.FlagTemplate) == "" {
+				output.Format = common.FormatText
+			}
+
+			// The watch mode reads the manifest at each run, and decrypts its secrets with the password asked at the first one
+			if watch {
+				return h.watch(server, actionsMeta, data, output)
+			}
+
 			if !c.GetBoolFlagValue(model.FlagNoSecrets) {
 				if err = common.DecryptManifestSecrets(manifest); err != nil {
 					return err
 				}
 			}
 
-			return h.run(manifest, server.GetUrl(), server.GetAccessToken(), data, outputFormat)
+			if compare && fixtures.dir != "" {
+				return h.compareFixtures(manifest, server, fixtures, output)
+			}
+
+			if compare {
+				return h.runCompare(manifest, server, data, output)
+			}
+
+			if fixtures.dir != "" {
+				return h.runFixtures(manifest, server, fixtures, output)
+			}
+
+			if fuzz.runs > 0 {
+				return h.runFuzz(manifest, server, actionsMeta, fuzz, output)
+			}
+
+			return h.run(manifest, server, data, output)
 		},
 	}
 }
 
-func (c *dryRunHandler) run(manifest *model.Manifest, serverURL string, token string, data map[string]any, outputFormat format.OutputFormat) error {
-	body, err := c.preparePayload(manifest, serverURL, token, data)
+// getSamplePayload returns the sample payload of the action of the manifest, to be used instead of a payload argument.
+func (c *dryRunHandler) getSamplePayload(manifest *model.Manifest, actionsMeta common.ActionsMetadata, fixtures *fixturesOptions) (map[string]any, error) {
+	if fixtures.dir != "" {
+		return nil, fmt.Errorf("--%s cannot be combined with --%s", flagDryRunSample, flagFixtures)
+	}
+	if len(c.ctx.Arguments) > 0 {
+		return nil, fmt.Errorf("a json payload cannot be combined with --%s", flagDryRunSample)
+	}
+
+	actionMeta, err := actionsMeta.FindAction(manifest.Action, manifest.Application)
+	if err != nil {
+		return nil, err
+	}
+
+	return common.SamplePayload(actionMeta)
+}
+
+func (c *dryRunHandler) run(manifest *model.Manifest, server *common.Server, data map[string]any, output *common.Output) error {
+	payload, err := c.preparePayload(manifest, server, data)
+	if err != nil {
+		return err
+	}
+
+	response, roundTrip, err := c.testRun(manifest, server, payload)
 	if err != nil {
 		return err
 	}
 
-	var contentHandler func([]byte) error
-	switch outputFormat {
-	case format.Json:
-		contentHandler = common.PrintJSON
-	case format.Table:
-		contentHandler = printDryRunResponseAsTable
+	if err = output.Print(common.NewExecutionOutput(response, roundTrip)); err != nil {
+		return err
 	}
 
-	return common.CallWorkerAPI(c.ctx, common.APICallParams{
-		Method:      http.MethodPost,
-		ServerURL:   serverURL,
-		ServerToken: token,
-		Body:        body,
-		ProjectKey:  manifest.ProjectKey,
-		Query: map[string]string{
-			"debug": fmt.Sprint(manifest.Debug),
-		},
-		OkStatuses: []int{http.StatusOK},
-		Path:       []string{"test", manifest.Name},
-		OnContent:  contentHandler,
-	})
+	if c.validator == nil {
+		return nil
+	}
+	return c.validator.Report(c.validator.ValidateExecutionResponse(response), c.ctx.GetBoolFlagValue(model.FlagStrict))
 }
 
-func printDryRunResponseAsTable(responseBytes []byte) error {
-	var data map[string]any
-	if err := json.Unmarshal(responseBytes, &data); err != nil {
-		return common.PrintJSON(responseBytes)
+// getResponseValidator returns the validator of the responses of an action, nil when the action has no response type or when its types cannot be read.
+func getResponseValidator(c *components.Context, actionsMeta common.ActionsMetadata, action string, application string) *common.ResponseValidator {
+	actionMeta, err := actionsMeta.FindAction(action, application)
+	if err == nil {
+		var validator *common.ResponseValidator
+		if validator, err = common.NewResponseValidator(actionMeta); err == nil && validator != nil {
+			return validator
+		}
 	}
 
-	writer := common.NewCsvWriter()
-	for k, v := range data {
-		if err := writer.Write([]string{k, fmt.Sprint(v)}); err != nil {
-			return err
-		}
+	message := fmt.Sprintf("The response of %s cannot be validated, the action has no response type", action)
+	if err != nil {
+		message = fmt.Sprintf("The response of %s cannot be validated: %s", action, err)
+	}
+	if c.GetBoolFlagValue(model.FlagStrict) {
+		log.Warn(message)
+	} else {
+		log.Debug(message)
 	}
-	writer.Flush()
-	return writer.Error()
+	return nil
+}
+
+// testRun sends a payload to the sandbox, and returns the response with the round trip of the request.
+func (c *dryRunHandler) testRun(manifest *model.Manifest, server *common.Server, payload *model.TestRunRequest) (json.RawMessage, time.Duration, error) {
+	start := time.Now()
+	response, err := common.CallWorkerClient(c.ctx, server, func(ctx context.Context, client *workerclient.Client) (json.RawMessage, error) {
+		return client.TestRun(ctx, manifest.Name, payload, workerclient.TestRunOptions{
+			ProjectKey: manifest.ProjectKey,
+			Debug:      manifest.Debug,
+		})
+	})
+	return response, time.Since(start), err
 }
 
-func (c *dryRunHandler) preparePayload(manifest *model.Manifest, serverURL string, token string, data map[string]any) ([]byte, error) {
-	payload := &dryRunRequest{Action: manifest.Action, Data: data}
+func (c *dryRunHandler) preparePayload(manifest *model.Manifest, server *common.Server, data map[string]any) (*model.TestRunRequest, error) {
+	payload := &model.TestRunRequest{Action: manifest.Action, Data: data}
 
 	var err error
 
@@ -169,7 +298,7 @@ func (c *dryRunHandler) preparePayload(manifest *model.Manifest, serverURL strin
 	}
 	payload.Code = common.CleanImports(payload.Code)
 
-	existingWorker, err := common.FetchWorkerDetails(c.ctx, serverURL, token, manifest.Name, manifest.ProjectKey)
+	existingWorker, err := common.FetchWorkerDetails(c.ctx, server, manifest.Name, manifest.ProjectKey)
 	if err != nil {
 		log.Warn(err.Error())
 	}
@@ -178,5 +307,5 @@ func (c *dryRunHandler) preparePayload(manifest *model.Manifest, serverURL strin
 		payload.StagedSecrets = common.PrepareSecretsUpdate(manifest, existingWorker)
 	}
 
-	return json.Marshal(&payload)
+	return payload, nil
 }
@@ -257,3 +257,37 @@ func TestWorkerDryRun_FormatUnsupported(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "only the following output formats are supported")
 }
+
+func TestWorkerDryRun_FormatText(t *testing.T) {
+	workerKey := workerKeyForDryRunTest
+	serverStub := common.NewServerStub(t).
+		WithWorkers(&model.WorkerDetails{Key: workerKey}).
+		WithDefaultActionsMetadataEndpoint().
+		WithGetOneEndpoint().
+		WithTestEndpoint(nil, map[string]any{
+			"genericEvent": map[string]any{
+				"data":            map[string]any{"user": map[string]any{"name": "me"}},
+				"executionStatus": "STATUS_SUCCESS",
+				"logs":            []any{map[string]any{"level": "INFO", "message": "hello"}},
+			},
+		})
+	common.NewMockWorkerServer(t, serverStub)
+
+	common.PrepareWorkerDirForTest(t)
+
+	runCmd := common.CreateCliRunner(t, GetInitCommand(), GetDryRunCommand())
+	require.NoError(t, runCmd("worker", "init", "BEFORE_DOWNLOAD", workerKey))
+
+	var out bytes.Buffer
+	common.SetCliOut(&out)
+	t.Cleanup(func() { common.SetCliOut(os.Stdout) })
+
+	require.NoError(t, runCmd("worker", "dry-run", "--"+format.FlagName, "text", `{}`))
+	assert.Contains(t, out.String(), "Event:    genericEvent\nStatus:   STATUS_SUCCESS\n")
+	assert.Contains(t, out.String(), "Result:\n{\n  \"user\": {\n    \"name\": \"me\"\n  }\n}\n")
+	assert.Contains(t, out.String(), "Logs:\nINFO  hello\n")
+
+	out.Reset()
+	require.NoError(t, runCmd("worker", "dry-run", "--"+format.FlagName, "csv", `{}`))
+	assert.Regexp(t, `^event,genericEvent\nexecutionStatus,STATUS_SUCCESS\ndurationMillis,\d+\ndata.user.name,me\nlogs\[0\].level,INFO\nlogs\[0\].message,hello\n$`, out.String())
+}
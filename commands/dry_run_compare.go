@@ -0,0 +1,289 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+)
+
+const flagCompareDeployed = "compare-deployed"
+
+// comparison is the result of a payload run with the deployed source and with the local one.
+type comparison struct {
+	Name string `json:"name"`
+	Same bool   `json:"same"`
+	// Differences are the differences of the results, the expected values are the deployed ones
+	Differences []*common.JSONDifference `json:"differences,omitempty"`
+	// LogChanges are the log lines of the deployed run only, prefixed with "- ", and of the local run only, prefixed with "+ "
+	LogChanges []string        `json:"logChanges,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	Deployed   json.RawMessage `json:"deployed,omitempty"`
+	Local      json.RawMessage `json:"local,omitempty"`
+}
+
+type comparisonReport struct {
+	Comparisons []*comparison `json:"comparisons"`
+	Same        int           `json:"same"`
+	Different   int           `json:"different"`
+	Errors      int           `json:"errors"`
+}
+
+// compareRequests are the requests of the local and deployed runs, that only differ by their source code.
+type compareRequests struct {
+	local    *model.TestRunRequest
+	deployed *model.TestRunRequest
+}
+
+// prepareCompareRequests returns the requests running the local source and the deployed one, with the same secrets.
+func (c *dryRunHandler) prepareCompareRequests(manifest *model.Manifest, server *common.Server, data map[string]any) (*compareRequests, error) {
+	local, err := c.preparePayload(manifest, server, data)
+	if err != nil {
+		return nil, err
+	}
+
+	worker, err := common.FetchWorkerDetails(c.ctx, server, manifest.Name, manifest.ProjectKey)
+	if err != nil {
+		return nil, err
+	}
+	if worker == nil {
+		return nil, fmt.Errorf("the worker %s is not deployed, there is no deployed source to compare with", manifest.Name)
+	}
+
+	deployed := *local
+	if deployed.Code, err = common.DecodeSourceCode(worker.SourceCode); err != nil {
+		return nil, fmt.Errorf("cannot read the deployed source of %s: %w", manifest.Name, err)
+	}
+
+	return &compareRequests{local: local, deployed: &deployed}, nil
+}
+
+// runCompare runs a payload with the deployed source and with the local one, and reports how the results and the logs differ.
+func (c *dryRunHandler) runCompare(manifest *model.Manifest, server *common.Server, data map[string]any, output *common.Output) error {
+	requests, err := c.prepareCompareRequests(manifest, server, data)
+	if err != nil {
+		return err
+	}
+
+	ignored, err := common.ParseJSONPathPatterns(defaultFixturesIgnorePaths)
+	if err != nil {
+		return err
+	}
+
+	result := c.compare(manifest, server, requests, ignored)
+	result.Name = "payload"
+	return printComparisonReport(output, []*comparison{result})
+}
+
+// compareFixtures compares the deployed and local runs of every payload of a fixtures directory, the expected files are not read.
+func (c *dryRunHandler) compareFixtures(manifest *model.Manifest, server *common.Server, options *fixturesOptions, output *common.Output) error {
+	names, err := findFixtures(options.dir)
+	if err != nil {
+		return err
+	}
+
+	requests, err := c.prepareCompareRequests(manifest, server, nil)
+	if err != nil {
+		return err
+	}
+
+	comparisons := make([]*comparison, len(names))
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, options.concurrency)
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			payloadFile := filepath.Join(options.dir, name+fixtureExtension)
+			data, err := common.NewInputReader(c.ctx).ReadDataFromFile(payloadFile)
+			if err != nil {
+				comparisons[i] = &comparison{Name: name, Error: fmt.Sprintf("cannot read %s: %s", payloadFile, err)}
+				return
+			}
+
+			local, deployed := *requests.local, *requests.deployed
+			local.Data, deployed.Data = data, data
+			comparisons[i] = c.compare(manifest, server, &compareRequests{local: &local, deployed: &deployed}, options.ignored)
+			comparisons[i].Name = name
+		}()
+	}
+	wg.Wait()
+
+	return printComparisonReport(output, comparisons)
+}
+
+// compare runs the deployed and local requests at the same time. The results are compared without the ignored paths,
+// and the logs line by line without their timestamps.
+func (c *dryRunHandler) compare(manifest *model.Manifest, server *common.Server, requests *compareRequests, ignored []*common.JSONPathPattern) *comparison {
+	result := &comparison{}
+
+	var deployedErr, localErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		result.Deployed, _, deployedErr = c.testRun(manifest, server, requests.deployed)
+	}()
+	go func() {
+		defer wg.Done()
+		result.Local, _, localErr = c.testRun(manifest, server, requests.local)
+	}()
+	wg.Wait()
+
+	switch {
+	case deployedErr != nil:
+		result.Error = "deployed source: " + deployedErr.Error()
+		return result
+	case localErr != nil:
+		result.Error = "local source: " + localErr.Error()
+		return result
+	}
+
+	var err error
+	if result.Differences, err = common.DiffJSON(result.Deployed, result.Local, ignored); err != nil {
+		result.Error = fmt.Sprintf("cannot compare the results: %s", err)
+		return result
+	}
+
+	result.LogChanges = common.DiffLines(executionLogLines(result.Deployed), executionLogLines(result.Local))
+	result.Same = len(result.Differences) == 0 && len(result.LogChanges) == 0
+	return result
+}
+
+// executionLogLines returns the logs of a response as "LEVEL message" lines.
+func executionLogLines(response json.RawMessage) []string {
+	result, isExecution := common.ParseExecutionResponse(response, time.Duration(0))
+	if !isExecution {
+		return nil
+	}
+	lines := make([]string, len(result.Logs))
+	for i, line := range result.Logs {
+		lines[i] = strings.TrimSpace(fmt.Sprintf("%-5s %s", line.Level, line.Message))
+	}
+	return lines
+}
+
+func printComparisonReport(output *common.Output, comparisons []*comparison) error {
+	report := &comparisonReport{Comparisons: comparisons}
+	for _, result := range comparisons {
+		switch {
+		case result.Error != "":
+			report.Errors++
+		case result.Same:
+			report.Same++
+		default:
+			report.Different++
+		}
+	}
+
+	if err := output.Print(&common.Result{
+		Value: report,
+		Items: report.Comparisons,
+		Table: newComparisonTable(report),
+		Text:  func() error { return printComparisonText(report) },
+	}); err != nil {
+		return err
+	}
+
+	if report.Different+report.Errors > 0 {
+		return common.ErrorAlreadyReported(fmt.Errorf("%d of %d payloads behave differently with the local source", report.Different+report.Errors, len(comparisons)))
+	}
+	return nil
+}
+
+func newComparisonTable(report *comparisonReport) *common.Table {
+	table := &common.Table{Headers: []string{"PAYLOAD", "STATUS", "DETAILS"}}
+	for _, result := range report.Comparisons {
+		details := result.Error
+		if details == "" {
+			var changes []string
+			for _, difference := range result.Differences {
+				changes = append(changes, formatComparedDifference(difference))
+			}
+			details = strings.Join(append(changes, result.LogChanges...), "; ")
+		}
+		table.Rows = append(table.Rows, []string{result.Name, result.status(), details})
+	}
+	return table
+}
+
+func (r *comparison) status() string {
+	switch {
+	case r.Error != "":
+		return "error"
+	case r.Same:
+		return "same"
+	default:
+		return "different"
+	}
+}
+
+// formatComparedDifference returns a difference as "<path>: <deployed> -> <local>".
+func formatComparedDifference(difference *common.JSONDifference) string {
+	switch {
+	case difference.Expected == nil:
+		return fmt.Sprintf("%s: added %s", difference.Path, difference.Actual)
+	case difference.Actual == nil:
+		return fmt.Sprintf("%s: removed %s", difference.Path, difference.Expected)
+	default:
+		return fmt.Sprintf("%s: %s -> %s", difference.Path, difference.Expected, difference.Actual)
+	}
+}
+
+func printComparisonText(report *comparisonReport) error {
+	for _, result := range report.Comparisons {
+		status := strings.ToUpper(result.status())
+		switch {
+		case result.Error != "":
+			status = common.Colorize(status, common.ColorRed)
+		case result.Same:
+			status = common.Colorize(status, common.ColorGreen)
+		default:
+			status = common.Colorize(status, common.ColorYellow)
+		}
+
+		if err := common.Print("%s %s\n", status, result.Name); err != nil {
+			return err
+		}
+		if result.Error != "" {
+			if err := common.Print("    %s\n", result.Error); err != nil {
+				return err
+			}
+		}
+		if len(result.Differences) > 0 {
+			if err := common.Print("    Result (deployed -> local):\n"); err != nil {
+				return err
+			}
+			for _, difference := range result.Differences {
+				if err := common.Print("        %s\n", formatComparedDifference(difference)); err != nil {
+					return err
+				}
+			}
+		}
+		if len(result.LogChanges) > 0 {
+			if err := common.Print("    Logs:\n"); err != nil {
+				return err
+			}
+			for _, change := range result.LogChanges {
+				color := common.ColorGreen
+				if strings.HasPrefix(change, "-") {
+					color = common.ColorRed
+				}
+				if err := common.Print("        %s\n", common.Colorize(change, color)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return common.Print("\n%d same, %d different, %d errors\n", report.Same, report.Different, report.Errors)
+}
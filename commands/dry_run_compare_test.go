@@ -0,0 +1,131 @@
+//go:build test
+// +build test
+
+package commands
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/common/format"
+	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerDryRun_CompareDeployed(t *testing.T) {
+	const deployedCode = "export default async () => ({ deployed: true })"
+
+	// The local source returns another value for n=2, and logs another line for n=3
+	serverStub := common.NewServerStub(t).
+		WithWorkers(&model.WorkerDetails{Key: workerKeyForDryRunTest, SourceCode: common.Base64SourceCodePrefix + base64.StdEncoding.EncodeToString([]byte(deployedCode))}).
+		WithDefaultActionsMetadataEndpoint().
+		WithGetOneEndpoint().
+		WithTestEndpoint(nil, common.ResponseBodyFunc(func(t *testing.T, requestBody []byte) any {
+			var request model.TestRunRequest
+			require.NoError(t, json.Unmarshal(requestBody, &request))
+			n, local := request.Data["n"], request.Code != deployedCode
+			message := "checked"
+			if local && n == 3.0 {
+				message = "checked twice"
+			}
+			if local && n == 2.0 {
+				n = 20
+			}
+			return map[string]any{"beforeDownload": map[string]any{
+				"data":            map[string]any{"status": 1, "message": "ok", "n": n},
+				"executionStatus": "STATUS_SUCCESS",
+				"logs":            []any{map[string]any{"level": "INFO", "message": message, "timestamp": time.Now().String()}},
+			}}
+		}))
+	common.NewMockWorkerServer(t, serverStub)
+	dir, _ := common.PrepareWorkerDirForTest(t)
+
+	runCmd := common.CreateCliRunner(t, GetInitCommand(), GetDryRunCommand())
+	require.NoError(t, runCmd("worker", "init", "BEFORE_DOWNLOAD", workerKeyForDryRunTest))
+
+	fixturesDir := filepath.Join(dir, "fixtures")
+	require.NoError(t, os.Mkdir(fixturesDir, 0755))
+	for name, content := range map[string]string{"a.json": `{"n":1}`, "a.expected.json": `{}`, "b.json": `{"n":2}`, "c.json": `{"n":3}`} {
+		require.NoError(t, os.WriteFile(filepath.Join(fixturesDir, name), []byte(content), 0644))
+	}
+
+	var out bytes.Buffer
+	common.SetCliOut(&out)
+	t.Cleanup(func() { common.SetCliOut(os.Stdout) })
+
+	err := runCmd("worker", "dry-run", "--"+flagCompareDeployed, "--"+flagFixtures, fixturesDir)
+	require.EqualError(t, err, "2 of 3 payloads behave differently with the local source")
+	assert.Equal(t, "SAME a\n"+
+		"DIFFERENT b\n    Result (deployed -> local):\n        beforeDownload.data.n: 2 -> 20\n"+
+		"DIFFERENT c\n    Logs:\n        - INFO  checked\n        + INFO  checked twice\n"+
+		"\n1 same, 2 different, 0 errors\n", out.String())
+
+	out.Reset()
+	require.NoError(t, runCmd("worker", "dry-run", "--"+flagCompareDeployed, "--"+format.FlagName, "json", `{"n":1}`))
+	var report comparisonReport
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	require.Len(t, report.Comparisons, 1)
+	assert.True(t, report.Comparisons[0].Same)
+	assert.Equal(t, 1, report.Same)
+
+	err = runCmd("worker", "dry-run", "--"+flagCompareDeployed, "--"+flagFixtures, fixturesDir, "--"+flagFixturesUpdate)
+	assert.EqualError(t, err, "--compare-deployed cannot be combined with --update or --junit-report, the expected files are not read")
+}
+
+func TestWorkerDryRun_CompareDeployed_NotDeployed(t *testing.T) {
+	serverStub := common.NewServerStub(t).
+		WithDefaultActionsMetadataEndpoint().
+		WithGetOneEndpoint()
+	common.NewMockWorkerServer(t, serverStub)
+	common.PrepareWorkerDirForTest(t)
+
+	runCmd := common.CreateCliRunner(t, GetInitCommand(), GetDryRunCommand())
+	require.NoError(t, runCmd("worker", "init", "BEFORE_DOWNLOAD", workerKeyForDryRunTest))
+
+	err := runCmd("worker", "dry-run", "--"+flagCompareDeployed, `{}`)
+	assert.EqualError(t, err, "the worker test-worker is not deployed, there is no deployed source to compare with")
+}
+
+func TestExecutionLogLines(t *testing.T) {
+	response := json.RawMessage(`{"data":null,"executionStatus":"STATUS_SUCCESS","logs":[` +
+		`{"level":"info","message":"first","timestamp":"10:00"},{"level":"error","message":"second","timestamp":"10:01"},"third"]}`)
+	assert.Equal(t, []string{"INFO  first", "ERROR second", "third"}, executionLogLines(response), "the timestamps are not compared")
+
+	assert.Nil(t, executionLogLines(json.RawMessage(`{"other":true}`)), "a response that is not an execution has no logs")
+}
+
+func TestPrintComparisonReport(t *testing.T) {
+	comparisons := []*comparison{
+		{Name: "same", Same: true},
+		{Name: "changed", Differences: []*common.JSONDifference{
+			{Path: "data.n", Expected: json.RawMessage(`1`), Actual: json.RawMessage(`2`)},
+			{Path: "data.added", Actual: json.RawMessage(`"new"`)},
+			{Path: "data.removed", Expected: json.RawMessage(`true`)},
+		}, LogChanges: []string{"- INFO before", "+ INFO after"}},
+		{Name: "failed", Error: "local source: boom"},
+	}
+
+	var out bytes.Buffer
+	common.SetCliOut(&out)
+	t.Cleanup(func() { common.SetCliOut(os.Stdout) })
+
+	err := printComparisonReport(&common.Output{Format: format.Table}, comparisons)
+	assert.EqualError(t, err, "2 of 3 payloads behave differently with the local source")
+	assert.Equal(t, "PAYLOAD  STATUS     DETAILS\n"+
+		"same     same       \n"+
+		"changed  different  data.n: 1 -> 2; data.added: added \"new\"; data.removed: removed true; - INFO before; + INFO after\n"+
+		"failed   error      local source: boom\n", out.String())
+
+	out.Reset()
+	require.NoError(t, printComparisonReport(&common.Output{Format: format.Json}, comparisons[:1]))
+	var report comparisonReport
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	assert.Equal(t, []int{1, 0, 0}, []int{report.Same, report.Different, report.Errors})
+}
@@ -0,0 +1,367 @@
+package commands
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+)
+
+const (
+	flagFixtures            = "fixtures"
+	flagFixturesUpdate      = "update"
+	flagFixturesIgnorePaths = "ignore-paths"
+	flagFixturesJUnitReport = "junit-report"
+	fixturesConcurrency     = 4
+	fixtureExtension        = ".json"
+	fixtureExpectedSuffix   = ".expected.json"
+)
+
+// The logs and the timing of an execution change at every run, the paths are the ones of the execute and test-run responses.
+var defaultFixturesIgnorePaths = []string{"logs", "*.logs", "**.timestamp", "**.executionDurationMillis"}
+
+type fixtureStatus string
+
+const (
+	fixturePass    fixtureStatus = "pass"
+	fixtureFail    fixtureStatus = "fail"
+	fixtureError   fixtureStatus = "error"
+	fixtureUpdated fixtureStatus = "updated"
+)
+
+type fixtureResult struct {
+	Name           string                   `json:"name"`
+	Status         fixtureStatus            `json:"status"`
+	DurationMillis int64                    `json:"durationMillis"`
+	Differences    []*common.JSONDifference `json:"differences,omitempty"`
+	Error          string                   `json:"error,omitempty"`
+	duration       time.Duration
+}
+
+type fixturesReport struct {
+	Fixtures []*fixtureResult `json:"fixtures"`
+	Passed   int              `json:"passed"`
+	Failed   int              `json:"failed"`
+	Errors   int              `json:"errors"`
+	Updated  int              `json:"updated"`
+}
+
+type fixturesOptions struct {
+	dir         string
+	update      bool
+	ignored     []*common.JSONPathPattern
+	junitReport string
+	concurrency int
+}
+
+func getFixturesOptions(c *dryRunHandler) (*fixturesOptions, error) {
+	options := &fixturesOptions{
+		dir:         c.ctx.GetStringFlagValue(flagFixtures),
+		update:      c.ctx.GetBoolFlagValue(flagFixturesUpdate),
+		junitReport: c.ctx.GetStringFlagValue(flagFixturesJUnitReport),
+	}
+
+	if options.dir == "" {
+		for _, flag := range []string{flagFixturesUpdate, flagFixturesIgnorePaths, flagFixturesJUnitReport} {
+			if slices.Contains(c.ctx.FlagsUsed, flag) {
+				return nil, fmt.Errorf("--%s requires --%s", flag, flagFixtures)
+			}
+		}
+		return options, nil
+	}
+
+	if len(c.ctx.Arguments) > 0 {
+		return nil, fmt.Errorf("a json payload cannot be combined with --%s, the payloads are read from the fixtures", flagFixtures)
+	}
+
+	var err error
+	if options.ignored, err = common.ParseJSONPathPatterns(strings.Split(c.ctx.GetStringFlagValue(flagFixturesIgnorePaths), ",")); err != nil {
+		return nil, err
+	}
+
+	if options.concurrency, err = model.GetConcurrencyParameter(c.ctx); err != nil {
+		return nil, err
+	}
+
+	return options, nil
+}
+
+// findFixtures returns the names of the payloads of a directory, i.e. their file names without the .json extension, sorted.
+func findFixtures(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the fixtures: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, fixtureExtension) || strings.HasSuffix(name, fixtureExpectedSuffix) {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(name, fixtureExtension))
+	}
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no fixtures found in '%s', expected <name>%s payloads next to their <name>%s results", dir, fixtureExtension, fixtureExpectedSuffix)
+	}
+
+	slices.Sort(names)
+	return names, nil
+}
+
+// runFixtures runs the payloads of a directory in the sandbox and compares the responses to the expected ones.
+func (c *dryRunHandler) runFixtures(manifest *model.Manifest, server *common.Server, options *fixturesOptions, output *common.Output) error {
+	names, err := findFixtures(options.dir)
+	if err != nil {
+		return err
+	}
+
+	payload, err := c.preparePayload(manifest, server, nil)
+	if err != nil {
+		return err
+	}
+
+	report := &fixturesReport{Fixtures: make([]*fixtureResult, len(names))}
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, options.concurrency)
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			request := *payload
+			report.Fixtures[i] = c.runFixture(manifest, server, &request, name, options)
+		}()
+	}
+	wg.Wait()
+
+	for _, result := range report.Fixtures {
+		switch result.Status {
+		case fixturePass:
+			report.Passed++
+		case fixtureFail:
+			report.Failed++
+		case fixtureError:
+			report.Errors++
+		case fixtureUpdated:
+			report.Updated++
+		}
+	}
+
+	if options.junitReport != "" {
+		if err = writeJUnitReport(options.junitReport, manifest.Name, report); err != nil {
+			return err
+		}
+	}
+
+	if err = output.Print(&common.Result{
+		Value: report,
+		Items: report.Fixtures,
+		Table: newFixturesTable(report),
+		Text:  func() error { return printFixturesReport(report) },
+	}); err != nil {
+		return err
+	}
+
+	if report.Failed+report.Errors > 0 {
+		return common.ErrorAlreadyReported(fmt.Errorf("%d of %d fixtures failed", report.Failed+report.Errors, len(report.Fixtures)))
+	}
+	return nil
+}
+
+func (c *dryRunHandler) runFixture(manifest *model.Manifest, server *common.Server, request *model.TestRunRequest, name string, options *fixturesOptions) *fixtureResult {
+	result := &fixtureResult{Name: name}
+	fail := func(err error) *fixtureResult {
+		result.Status, result.Error = fixtureError, err.Error()
+		return result
+	}
+
+	payloadFile := filepath.Join(options.dir, name+fixtureExtension)
+	data, err := common.NewInputReader(c.ctx).ReadDataFromFile(payloadFile)
+	if err != nil {
+		return fail(fmt.Errorf("cannot read %s: %w", payloadFile, err))
+	}
+	request.Data = data
+
+	response, roundTrip, err := c.testRun(manifest, server, request)
+	result.duration, result.DurationMillis = roundTrip, roundTrip.Milliseconds()
+	if err != nil {
+		return fail(err)
+	}
+
+	expectedFile := filepath.Join(options.dir, name+fixtureExpectedSuffix)
+	expected, err := os.ReadFile(expectedFile)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fail(fmt.Errorf("cannot read %s: %w", expectedFile, err))
+	}
+
+	if expected != nil {
+		if result.Differences, err = common.DiffJSON(json.RawMessage(expected), response, options.ignored); err != nil {
+			return fail(fmt.Errorf("cannot compare with %s: %w", expectedFile, err))
+		}
+	}
+
+	switch {
+	case expected != nil && len(result.Differences) == 0:
+		result.Status = fixturePass
+	case options.update:
+		if err = writeExpectedFile(expectedFile, response, options.ignored); err != nil {
+			return fail(err)
+		}
+		result.Status = fixtureUpdated
+	case expected == nil:
+		return fail(fmt.Errorf("%s not found, run with --%s to create it", expectedFile, flagFixturesUpdate))
+	default:
+		result.Status = fixtureFail
+	}
+
+	return result
+}
+
+// writeExpectedFile saves a response without its ignored paths, so that the expected files only have the compared values.
+func writeExpectedFile(expectedFile string, response json.RawMessage, ignored []*common.JSONPathPattern) error {
+	pruned, err := common.PruneJSON(response, ignored)
+	if err != nil {
+		return fmt.Errorf("cannot update %s: %w", expectedFile, err)
+	}
+
+	content, err := json.MarshalIndent(pruned, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot update %s: %w", expectedFile, err)
+	}
+
+	if err = os.WriteFile(expectedFile, append(content, '\n'), 0644); err != nil {
+		return fmt.Errorf("cannot update %s: %w", expectedFile, err)
+	}
+	return nil
+}
+
+func newFixturesTable(report *fixturesReport) *common.Table {
+	table := &common.Table{Headers: []string{"FIXTURE", "STATUS", "DURATION", "DETAILS"}}
+	for _, result := range report.Fixtures {
+		table.Rows = append(table.Rows, []string{result.Name, string(result.Status), strconv.FormatInt(result.DurationMillis, 10) + "ms", result.details()})
+	}
+	return table
+}
+
+func (r *fixtureResult) details() string {
+	if r.Error != "" {
+		return r.Error
+	}
+	details := make([]string, len(r.Differences))
+	for i, difference := range r.Differences {
+		details[i] = difference.String()
+	}
+	return strings.Join(details, "; ")
+}
+
+func printFixturesReport(report *fixturesReport) error {
+	for _, result := range report.Fixtures {
+		status := strings.ToUpper(string(result.Status))
+		switch result.Status {
+		case fixturePass:
+			status = common.Colorize(status, common.ColorGreen)
+		case fixtureFail, fixtureError:
+			status = common.Colorize(status, common.ColorRed)
+		case fixtureUpdated:
+			status = common.Colorize(status, common.ColorYellow)
+		}
+
+		if err := common.Print("%s %s (%s)\n", status, result.Name, result.duration.Round(time.Millisecond)); err != nil {
+			return err
+		}
+		if result.Error != "" {
+			if err := common.Print("    %s\n", result.Error); err != nil {
+				return err
+			}
+		}
+		for _, difference := range result.Differences {
+			if err := common.Print("    %s\n", difference); err != nil {
+				return err
+			}
+		}
+	}
+
+	return common.Print("\n%d passed, %d failed, %d errors, %d updated\n", report.Passed, report.Failed, report.Errors, report.Updated)
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name          `xml:"testsuites"`
+	Suites  []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string           `xml:"name,attr"`
+	Tests     int              `xml:"tests,attr"`
+	Failures  int              `xml:"failures,attr"`
+	Errors    int              `xml:"errors,attr"`
+	Time      string           `xml:"time,attr"`
+	TestCases []*junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
+
+// writeJUnitReport writes the results of the fixtures as a JUnit XML test suite named after the worker, to be read by the CI servers.
+func writeJUnitReport(file string, workerKey string, report *fixturesReport) error {
+	suite := &junitTestSuite{Name: workerKey, Tests: len(report.Fixtures), Failures: report.Failed, Errors: report.Errors}
+
+	var total time.Duration
+	for _, result := range report.Fixtures {
+		total += result.duration
+		testCase := &junitTestCase{Name: result.Name, ClassName: workerKey, Time: formatJUnitTime(result.duration)}
+		switch result.Status {
+		case fixtureFail:
+			var differences []string
+			for _, difference := range result.Differences {
+				differences = append(differences, difference.String())
+			}
+			testCase.Failure = &junitProblem{
+				Message: fmt.Sprintf("%d differences with the expected result", len(result.Differences)),
+				Content: strings.Join(differences, "\n"),
+			}
+		case fixtureError:
+			testCase.Error = &junitProblem{Message: result.Error}
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+	suite.Time = formatJUnitTime(total)
+
+	content, err := xml.MarshalIndent(&junitTestSuites{Suites: []*junitTestSuite{suite}}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.WriteFile(file, append([]byte(xml.Header), append(content, '\n')...), 0644); err != nil {
+		return fmt.Errorf("cannot write the JUnit report: %w", err)
+	}
+	return nil
+}
+
+func formatJUnitTime(duration time.Duration) string {
+	return strconv.FormatFloat(duration.Seconds(), 'f', 3, 64)
+}
@@ -0,0 +1,93 @@
+//go:build test
+// +build test
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/common/format"
+	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerDryRun_Fixtures(t *testing.T) {
+	workerKey := workerKeyForDryRunTest
+	serverStub := common.NewServerStub(t).
+		WithWorkers(&model.WorkerDetails{Key: workerKey}).
+		WithDefaultActionsMetadataEndpoint().
+		WithGetOneEndpoint().
+		WithTestEndpoint(nil, common.ResponseBodyFunc(func(t *testing.T, requestBody []byte) any {
+			var request model.TestRunRequest
+			require.NoError(t, json.Unmarshal(requestBody, &request))
+			return map[string]any{
+				"genericEvent": map[string]any{
+					"data":            request.Data,
+					"executionStatus": "STATUS_SUCCESS",
+					"logs":            []any{map[string]any{"level": "INFO", "message": time.Now().String()}},
+				},
+			}
+		}))
+	common.NewMockWorkerServer(t, serverStub)
+
+	dir, _ := common.PrepareWorkerDirForTest(t)
+
+	runCmd := common.CreateCliRunner(t, GetInitCommand(), GetDryRunCommand())
+	require.NoError(t, runCmd("worker", "init", "BEFORE_DOWNLOAD", workerKey))
+
+	fixturesDir := filepath.Join(dir, "fixtures")
+	require.NoError(t, os.Mkdir(fixturesDir, 0755))
+	writeFixture := func(name string, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(fixturesDir, name), []byte(content), 0644))
+	}
+	writeFixture("a.json", `{"n":1}`)
+	writeFixture("a.expected.json", `{"genericEvent":{"data":{"n":1},"executionStatus":"STATUS_SUCCESS","logs":["ignored"]}}`)
+	writeFixture("b.json", `{"n":2}`)
+	writeFixture("b.expected.json", `{"genericEvent":{"data":{"n":3},"executionStatus":"STATUS_SUCCESS"}}`)
+	writeFixture("c.json", `{"n":3}`)
+
+	var out bytes.Buffer
+	common.SetCliOut(&out)
+	t.Cleanup(func() { common.SetCliOut(os.Stdout) })
+
+	junitReport := filepath.Join(dir, "report.xml")
+	err := runCmd("worker", "dry-run", "--"+flagFixtures, fixturesDir, "--"+model.FlagConcurrency, "2", "--"+flagFixturesJUnitReport, junitReport)
+	require.EqualError(t, err, "2 of 3 fixtures failed")
+
+	assert.Regexp(t, `^PASS a \(\d+m?s\)\n`+
+		`FAIL b \(\d+m?s\)\n    genericEvent\.data\.n: expected 3, got 2\n`+
+		`ERROR c \(\d+m?s\)\n    .*c\.expected\.json not found, run with --update to create it\n`+
+		`\n1 passed, 1 failed, 1 errors, 0 updated\n$`, out.String())
+
+	report, err := os.ReadFile(junitReport)
+	require.NoError(t, err)
+	assert.Contains(t, string(report), `<testsuite name="test-worker" tests="3" failures="1" errors="1"`)
+	assert.Contains(t, string(report), `<failure message="1 differences with the expected result">genericEvent.data.n: expected 3, got 2</failure>`)
+
+	out.Reset()
+	require.NoError(t, runCmd("worker", "dry-run", "--"+flagFixtures, fixturesDir, "--"+flagFixturesUpdate, "--"+format.FlagName, "json"))
+	var got fixturesReport
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	assert.Equal(t, []int{1, 0, 0, 2}, []int{got.Passed, got.Failed, got.Errors, got.Updated})
+
+	expected, err := os.ReadFile(filepath.Join(fixturesDir, "c.expected.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"genericEvent":{"data":{"n":3},"executionStatus":"STATUS_SUCCESS"}}`, string(expected))
+
+	out.Reset()
+	require.NoError(t, runCmd("worker", "dry-run", "--"+flagFixtures, fixturesDir))
+	assert.Contains(t, out.String(), "3 passed, 0 failed, 0 errors, 0 updated")
+
+	err = runCmd("worker", "dry-run", "--"+flagFixturesUpdate, `{}`)
+	assert.EqualError(t, err, "--update requires --fixtures")
+
+	err = runCmd("worker", "dry-run", "--"+flagFixtures, fixturesDir, `{}`)
+	assert.EqualError(t, err, "a json payload cannot be combined with --fixtures, the payloads are read from the fixtures")
+}
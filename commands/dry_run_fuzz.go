@@ -0,0 +1,249 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/utils/log"
+
+	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+)
+
+const (
+	flagFuzz             = "fuzz"
+	flagFuzzSeed         = "seed"
+	flagFuzzSaveFailures = "save-failures"
+)
+
+type fuzzFailure struct {
+	Index    int             `json:"index"`
+	Reason   string          `json:"reason"`
+	Payload  map[string]any  `json:"payload"`
+	Response json.RawMessage `json:"response,omitempty"`
+}
+
+type fuzzReport struct {
+	Seed     int64          `json:"seed"`
+	Runs     int            `json:"runs"`
+	Failures []*fuzzFailure `json:"failures"`
+}
+
+type fuzzOptions struct {
+	runs         int
+	seed         int64
+	saveFailures string
+	concurrency  int
+}
+
+func getFuzzOptions(c *dryRunHandler, fixtures *fixturesOptions) (*fuzzOptions, error) {
+	runs, err := c.ctx.GetIntFlagValue(flagFuzz)
+	if err != nil || runs < 0 {
+		return nil, fmt.Errorf("invalid --%s provided, expected a number of payloads", flagFuzz)
+	}
+	options := &fuzzOptions{runs: runs, saveFailures: c.ctx.GetStringFlagValue(flagFuzzSaveFailures)}
+
+	if runs == 0 {
+		for _, flag := range []string{flagFuzzSeed, flagFuzzSaveFailures} {
+			if slices.Contains(c.ctx.FlagsUsed, flag) {
+				return nil, fmt.Errorf("--%s requires --%s", flag, flagFuzz)
+			}
+		}
+		return options, nil
+	}
+
+	switch {
+	case fixtures.dir != "":
+		return nil, fmt.Errorf("--%s cannot be combined with --%s", flagFuzz, flagFixtures)
+	case c.ctx.GetBoolFlagValue(flagDryRunSample):
+		return nil, fmt.Errorf("--%s cannot be combined with --%s", flagFuzz, flagDryRunSample)
+	case len(c.ctx.Arguments) > 0:
+		return nil, fmt.Errorf("a json payload cannot be combined with --%s, the payloads are generated", flagFuzz)
+	}
+
+	if seed := c.ctx.GetStringFlagValue(flagFuzzSeed); seed != "" {
+		if options.seed, err = strconv.ParseInt(seed, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid --%s provided, expected an integer", flagFuzzSeed)
+		}
+	} else {
+		options.seed = time.Now().UnixNano()
+	}
+
+	if options.concurrency, err = model.GetConcurrencyParameter(c.ctx); err != nil {
+		return nil, err
+	}
+
+	return options, nil
+}
+
+// generateFuzzPayloads returns random payloads of the request type of the action of the manifest.
+func generateFuzzPayloads(manifest *model.Manifest, actionsMeta common.ActionsMetadata, options *fuzzOptions) ([]map[string]any, error) {
+	actionMeta, err := actionsMeta.FindAction(manifest.Action, manifest.Application)
+	if err != nil {
+		return nil, err
+	}
+
+	if actionMeta.ExecutionRequestType == "" || actionMeta.TypesDefinitions == "" {
+		return nil, fmt.Errorf("the action %s has no request type, payloads cannot be generated", actionMeta.Action.Name)
+	}
+
+	types, err := common.ParseTypesDefinitions(actionMeta.TypesDefinitions)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the types of %s: %w", actionMeta.Action.Name, err)
+	}
+
+	// The payloads are generated before being run, so that a seed always gives the same payloads whatever the concurrency
+	generator := common.NewPayloadGenerator(types, options.seed)
+	payloads := make([]map[string]any, options.runs)
+	for i := range payloads {
+		if payloads[i], err = generator.Generate(actionMeta.ExecutionRequestType); err != nil {
+			return nil, err
+		}
+	}
+	return payloads, nil
+}
+
+// runFuzz runs random payloads in the sandbox and reports the ones making the worker fail.
+func (c *dryRunHandler) runFuzz(manifest *model.Manifest, server *common.Server, actionsMeta common.ActionsMetadata, options *fuzzOptions, output *common.Output) error {
+	payloads, err := generateFuzzPayloads(manifest, actionsMeta, options)
+	if err != nil {
+		return err
+	}
+
+	request, err := c.preparePayload(manifest, server, nil)
+	if err != nil {
+		return err
+	}
+
+	log.Info(fmt.Sprintf("Running %d payloads generated with the seed %d", options.runs, options.seed))
+
+	failures := make([]*fuzzFailure, len(payloads))
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, options.concurrency)
+	for i, payload := range payloads {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			runRequest := *request
+			runRequest.Data = payload
+			failures[i] = c.runFuzzPayload(manifest, server, &runRequest, i)
+		}()
+	}
+	wg.Wait()
+
+	report := &fuzzReport{Seed: options.seed, Runs: options.runs, Failures: []*fuzzFailure{}}
+	for _, failure := range failures {
+		if failure != nil {
+			report.Failures = append(report.Failures, failure)
+		}
+	}
+
+	if options.saveFailures != "" && len(report.Failures) > 0 {
+		if err = saveFuzzFailures(options.saveFailures, report); err != nil {
+			return err
+		}
+	}
+
+	if err = output.Print(&common.Result{
+		Value: report,
+		Items: report.Failures,
+		Table: newFuzzTable(report),
+		Text:  func() error { return printFuzzReport(report) },
+	}); err != nil {
+		return err
+	}
+
+	if len(report.Failures) > 0 {
+		return common.ErrorAlreadyReported(fmt.Errorf("%d of %d payloads failed", len(report.Failures), report.Runs))
+	}
+	return nil
+}
+
+// runFuzzPayload returns a failure when the request fails, when the worker throws, when the response is not an execution result
+// or when the returned value does not match the response type of the action.
+func (c *dryRunHandler) runFuzzPayload(manifest *model.Manifest, server *common.Server, request *model.TestRunRequest, index int) *fuzzFailure {
+	failure := &fuzzFailure{Index: index, Payload: request.Data}
+
+	response, roundTrip, err := c.testRun(manifest, server, request)
+	if err != nil {
+		failure.Reason = err.Error()
+		return failure
+	}
+	failure.Response = response
+
+	result, ok := common.ParseExecutionResponse(response, roundTrip)
+	switch {
+	case !ok:
+		failure.Reason = "invalid response, no data nor execution status"
+	case result.Failed():
+		failure.Reason = "execution status " + result.Status
+	case c.validator != nil:
+		violations := c.validator.Validate(result.Data)
+		if len(violations) == 0 {
+			return nil
+		}
+		messages := make([]string, len(violations))
+		for i, violation := range violations {
+			messages[i] = violation.String()
+		}
+		failure.Reason = fmt.Sprintf("invalid %s, %s", c.validator.TypeName, strings.Join(messages, "; "))
+	default:
+		return nil
+	}
+	return failure
+}
+
+// saveFuzzFailures writes the failing payloads as fixtures, to be replayed with --fixtures once the worker is fixed.
+func saveFuzzFailures(dir string, report *fuzzReport) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("cannot save the failing payloads: %w", err)
+	}
+
+	for _, failure := range report.Failures {
+		file := filepath.Join(dir, fmt.Sprintf("fuzz-%d-%d%s", report.Seed, failure.Index, fixtureExtension))
+		if err := common.WriteSamplePayload(file, failure.Payload); err != nil {
+			return fmt.Errorf("cannot save the failing payloads: %w", err)
+		}
+	}
+
+	log.Info(fmt.Sprintf("%d failing payloads saved to %s", len(report.Failures), dir))
+	return nil
+}
+
+func newFuzzTable(report *fuzzReport) *common.Table {
+	table := &common.Table{Headers: []string{"INDEX", "REASON", "PAYLOAD"}}
+	for _, failure := range report.Failures {
+		payload, _ := json.Marshal(failure.Payload)
+		table.Rows = append(table.Rows, []string{strconv.Itoa(failure.Index), failure.Reason, string(payload)})
+	}
+	return table
+}
+
+func printFuzzReport(report *fuzzReport) error {
+	for _, failure := range report.Failures {
+		payload, err := json.MarshalIndent(failure.Payload, "    ", "  ")
+		if err != nil {
+			return err
+		}
+		if err = common.Print("%s payload #%d: %s\n    %s\n", common.Colorize("FAIL", common.ColorRed), failure.Index, failure.Reason, payload); err != nil {
+			return err
+		}
+	}
+
+	status := common.Colorize("PASS", common.ColorGreen)
+	if len(report.Failures) > 0 {
+		status = common.Colorize("FAIL", common.ColorRed)
+	}
+	return common.Print("\n%s %d of %d payloads failed (seed %d)\n", status, len(report.Failures), report.Runs, report.Seed)
+}
@@ -0,0 +1,167 @@
+//go:build test
+// +build test
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/common/format"
+	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerDryRun_Fuzz(t *testing.T) {
+	// The worker fails without metadata, and returns an invalid response for the files without name
+	serverStub := common.NewServerStub(t).
+		WithWorkers(&model.WorkerDetails{Key: workerKeyForDryRunTest}).
+		WithDefaultActionsMetadataEndpoint().
+		WithGetOneEndpoint().
+		WithTestEndpoint(nil, common.ResponseBodyFunc(func(t *testing.T, requestBody []byte) any {
+			var request model.TestRunRequest
+			require.NoError(t, json.Unmarshal(requestBody, &request))
+			metadata, hasMetadata := request.Data["metadata"].(map[string]any)
+			switch {
+			case !hasMetadata:
+				return map[string]any{"executionStatus": "STATUS_FAIL"}
+			case metadata["name"] == "":
+				return map[string]any{}
+			default:
+				return map[string]any{"data": map[string]any{"status": 1, "message": "proceed"}, "executionStatus": "STATUS_SUCCESS"}
+			}
+		}))
+	common.NewMockWorkerServer(t, serverStub)
+
+	dir, _ := common.PrepareWorkerDirForTest(t)
+
+	runCmd := common.CreateCliRunner(t, GetInitCommand(), GetDryRunCommand())
+	require.NoError(t, runCmd("worker", "init", "BEFORE_DOWNLOAD", workerKeyForDryRunTest))
+
+	manifest, err := common.ReadManifest()
+	require.NoError(t, err)
+	payloads, err := generateFuzzPayloads(manifest, common.LoadSampleActions(t), &fuzzOptions{runs: 30, seed: 5})
+	require.NoError(t, err)
+
+	wantReasons := map[int]string{}
+	for i, payload := range payloads {
+		metadata, hasMetadata := payload["metadata"].(map[string]any)
+		switch {
+		case !hasMetadata:
+			wantReasons[i] = "execution status STATUS_FAIL"
+		case metadata["name"] == "":
+			wantReasons[i] = "invalid response, no data nor execution status"
+		}
+	}
+	require.NotEmpty(t, wantReasons)
+
+	var out bytes.Buffer
+	common.SetCliOut(&out)
+	t.Cleanup(func() { common.SetCliOut(os.Stdout) })
+
+	failuresDir := filepath.Join(dir, "failures")
+	err = runCmd("worker", "dry-run", "--"+flagFuzz, "30", "--"+flagFuzzSeed, "5", "--"+flagFuzzSaveFailures, failuresDir, "--"+format.FlagName, "json")
+	require.EqualError(t, err, fmt.Sprintf("%d of 30 payloads failed", len(wantReasons)))
+
+	var report fuzzReport
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	assert.Equal(t, int64(5), report.Seed)
+	assert.Equal(t, 30, report.Runs)
+
+	gotReasons := map[int]string{}
+	for _, failure := range report.Failures {
+		gotReasons[failure.Index] = failure.Reason
+		assert.Equal(t, common.MustJsonMarshal(t, payloads[failure.Index]), common.MustJsonMarshal(t, failure.Payload))
+
+		saved, err := os.ReadFile(filepath.Join(failuresDir, fmt.Sprintf("fuzz-5-%d.json", failure.Index)))
+		require.NoError(t, err)
+		assert.JSONEq(t, common.MustJsonMarshal(t, payloads[failure.Index]), string(saved))
+	}
+	assert.Equal(t, wantReasons, gotReasons)
+
+	out.Reset()
+	err = runCmd("worker", "dry-run", "--"+flagFuzz, "30", "--"+flagFuzzSeed, "5")
+	require.Error(t, err)
+	assert.Contains(t, out.String(), fmt.Sprintf("FAIL %d of 30 payloads failed (seed 5)\n", len(wantReasons)))
+
+	err = runCmd("worker", "dry-run", "--"+flagFuzzSeed, "5", `{}`)
+	assert.EqualError(t, err, "--seed requires --fuzz")
+
+	err = runCmd("worker", "dry-run", "--"+flagFuzz, "3", `{}`)
+	assert.EqualError(t, err, "a json payload cannot be combined with --fuzz, the payloads are generated")
+}
+
+func TestWorkerDryRun_FuzzOptions(t *testing.T) {
+	common.NewMockWorkerServer(t, common.NewServerStub(t).WithDefaultActionsMetadataEndpoint())
+	dir, _ := common.PrepareWorkerDirForTest(t)
+
+	runCmd := common.CreateCliRunner(t, GetInitCommand(), GetDryRunCommand())
+	require.NoError(t, runCmd("worker", "init", "BEFORE_DOWNLOAD", workerKeyForDryRunTest))
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "negative runs", args: []string{"--" + flagFuzz, "-1"}, wantErr: "invalid --fuzz provided, expected a number of payloads"},
+		{name: "invalid seed", args: []string{"--" + flagFuzz, "2", "--" + flagFuzzSeed, "abc"}, wantErr: "invalid --seed provided, expected an integer"},
+		{name: "save failures without fuzz", args: []string{"--" + flagFuzzSaveFailures, dir, `{}`}, wantErr: "--save-failures requires --fuzz"},
+		{name: "with fixtures", args: []string{"--" + flagFuzz, "2", "--" + flagFixtures, dir}, wantErr: "--fuzz cannot be combined with --fixtures"},
+		{name: "with the sample payload", args: []string{"--" + flagFuzz, "2", "--" + flagDryRunSample}, wantErr: "--fuzz cannot be combined with --sample"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runCmd(append([]string{"worker", "dry-run"}, tt.args...)...)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestGenerateFuzzPayloads(t *testing.T) {
+	actionsMeta := common.LoadSampleActions(t)
+	manifest := &model.Manifest{Action: "BEFORE_DOWNLOAD"}
+
+	payloads, err := generateFuzzPayloads(manifest, actionsMeta, &fuzzOptions{runs: 10, seed: 42})
+	require.NoError(t, err)
+	require.Len(t, payloads, 10)
+
+	again, err := generateFuzzPayloads(manifest, actionsMeta, &fuzzOptions{runs: 10, seed: 42, concurrency: 4})
+	require.NoError(t, err)
+	assert.Equal(t, common.MustJsonMarshal(t, payloads), common.MustJsonMarshal(t, again), "a seed always generates the same payloads")
+
+	other, err := generateFuzzPayloads(manifest, actionsMeta, &fuzzOptions{runs: 10, seed: 43})
+	require.NoError(t, err)
+	assert.NotEqual(t, common.MustJsonMarshal(t, payloads), common.MustJsonMarshal(t, other))
+
+	_, err = generateFuzzPayloads(&model.Manifest{Action: "GENERIC_EVENT"}, actionsMeta, &fuzzOptions{runs: 1})
+	assert.EqualError(t, err, "the action GENERIC_EVENT has no request type, payloads cannot be generated")
+}
+
+func TestSaveFuzzFailures(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "failures", "nested")
+	report := &fuzzReport{Seed: 9, Runs: 5, Failures: []*fuzzFailure{
+		{Index: 1, Payload: map[string]any{"name": ""}},
+		{Index: 4, Payload: map[string]any{"items": []any{}}},
+	}}
+
+	require.NoError(t, saveFuzzFailures(dir, report))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal(t, []string{"fuzz-9-1.json", "fuzz-9-4.json"}, names)
+
+	saved, err := os.ReadFile(filepath.Join(dir, "fuzz-9-4.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"items":[]}`, string(saved), "the failures are saved as fixtures payloads")
+}
@@ -0,0 +1,315 @@
+package commands
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/utils/log"
+
+	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+)
+
+const (
+	flagDryRunWatch     = "watch"
+	watchedManifestFile = "manifest.json"
+)
+
+var (
+	// watchPollInterval is the delay between two checks of the watched files
+	watchPollInterval = 500 * time.Millisecond
+	// watchDebounce is how long the files must be left unchanged before a run, the editors often write a file in several steps
+	watchDebounce = 300 * time.Millisecond
+	// interruptContext is cancelled by Ctrl-C, it bounds the commands running until they are interrupted. The tests cancel it to end them
+	interruptContext = func() (context.Context, context.CancelFunc) {
+		return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	}
+)
+
+// The relative paths of the import and require statements, e.g. './utils' in import { a } from './utils'
+var localImportPattern = regexp.MustCompile(`(?:\bfrom\s*|\bimport\s*\(?\s*|\brequire\(\s*)['"](\.\.?/[^'"]+)['"]`)
+
+// The extensions tried, in order, for an import without extension
+var importExtensions = []string{"", ".ts", ".js", "/index.ts", "/index.js"}
+
+// watchSession runs a worker each time its files change, until it is interrupted.
+type watchSession struct {
+	handler     *dryRunHandler
+	server      *common.Server
+	actionsMeta common.ActionsMetadata
+	output      *common.Output
+	// payloadFile is the file of a @file payload argument, read again at each run
+	payloadFile string
+	// data is the payload when it is not read from a file
+	data map[string]any
+	// password decrypts the secrets of the manifest, it is asked once for the whole session
+	password *string
+	previous json.RawMessage
+	runs     int
+}
+
+// watch runs the worker, then runs it again each time the manifest, the source code, its local imports or the payload file change.
+func (c *dryRunHandler) watch(server *common.Server, actionsMeta common.ActionsMetadata, data map[string]any, output *common.Output) error {
+	session := &watchSession{handler: c, server: server, actionsMeta: actionsMeta, output: output, data: data}
+	if len(c.ctx.Arguments) > 0 {
+		if payload := c.ctx.Arguments[len(c.ctx.Arguments)-1]; strings.HasPrefix(payload, "@") {
+			session.payloadFile = payload[1:]
+		}
+	}
+
+	ctx, cancel := interruptContext()
+	defer cancel()
+
+	snapshot := session.snapshot()
+	session.run(nil)
+
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("Watch stopped")
+			return nil
+		case <-ticker.C:
+		}
+
+		current := session.snapshot()
+		changed := changedFiles(snapshot, current)
+		if len(changed) == 0 {
+			continue
+		}
+
+		// The run starts once the files stop changing
+		for stable := false; !stable; {
+			select {
+			case <-ctx.Done():
+				log.Info("Watch stopped")
+				return nil
+			case <-time.After(watchDebounce):
+			}
+			next := session.snapshot()
+			moreChanges := changedFiles(current, next)
+			for _, file := range moreChanges {
+				if !slices.Contains(changed, file) {
+					changed = append(changed, file)
+				}
+			}
+			current, stable = next, len(moreChanges) == 0
+		}
+
+		snapshot = current
+		slices.Sort(changed)
+		session.run(changed)
+	}
+}
+
+// run runs the worker once and prints its result, with the differences from the previous run.
+// The errors are printed rather than returned, the session goes on until it is interrupted.
+func (s *watchSession) run(changed []string) {
+	s.runs++
+
+	if err := common.ClearScreen(); err != nil {
+		log.Debug(fmt.Sprintf("Cannot clear the screen: %+v", err))
+	}
+
+	header := fmt.Sprintf("[%s] test-run #%d", time.Now().Format(time.TimeOnly), s.runs)
+	if len(changed) > 0 {
+		header += fmt.Sprintf(" (%s changed)", strings.Join(changed, ", "))
+	}
+	if err := s.print("%s\n\n", header); err != nil {
+		return
+	}
+
+	response, roundTrip, err := s.testRun()
+	if err != nil {
+		_ = s.print("%s %s\n", common.Colorize("ERROR", common.ColorRed), err.Error())
+	} else if err = s.output.Print(common.NewExecutionOutput(response, roundTrip)); err != nil {
+		log.Warn(err.Error())
+	}
+
+	if err == nil {
+		if s.handler.validator != nil {
+			_ = s.handler.validator.Report(s.handler.validator.ValidateExecutionResponse(response), false)
+		}
+		if s.previous != nil {
+			s.printChanges(response)
+		}
+		s.previous = response
+	}
+
+	_ = s.print("\nWatching %s for changes, press Ctrl-C to stop\n", strings.Join(s.watchedFiles(), ", "))
+}
+
+// testRun reads the files of the worker again and sends them to the sandbox.
+func (s *watchSession) testRun() (json.RawMessage, time.Duration, error) {
+	manifest, err := common.ReadManifest()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err = common.ValidateManifest(manifest, s.actionsMeta); err != nil {
+		return nil, 0, err
+	}
+
+	data := s.data
+	if s.payloadFile != "" {
+		if data, err = common.NewInputReader(s.handler.ctx).ReadDataFromFile(s.payloadFile); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if !s.handler.ctx.GetBoolFlagValue(model.FlagNoSecrets) && len(manifest.Secrets) > 0 {
+		if s.password == nil {
+			password, err := common.ReadSecretPassword("Secrets Password: ")
+			if err != nil {
+				return nil, 0, err
+			}
+			s.password = &password
+		}
+		if err = common.DecryptManifestSecrets(manifest, *s.password); err != nil {
+			// The password is asked again at the next run, it may be a typo
+			s.password = nil
+			return nil, 0, err
+		}
+	}
+
+	s.handler.validator = getResponseValidator(s.handler.ctx, s.actionsMeta, manifest.Action, manifest.Application)
+
+	payload, err := s.handler.preparePayload(manifest, s.server, data)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return s.handler.testRun(manifest, s.server, payload)
+}
+
+// printChanges prints the differences of a response from the previous one, the logs and the timing excepted.
+func (s *watchSession) printChanges(response json.RawMessage) {
+	ignored, err := common.ParseJSONPathPatterns(defaultFixturesIgnorePaths)
+	if err != nil {
+		log.Debug(err.Error())
+		return
+	}
+
+	differences, err := common.DiffJSON(s.previous, response, ignored)
+	if err != nil {
+		log.Debug(fmt.Sprintf("Cannot compare with the previous run: %+v", err))
+		return
+	}
+
+	if len(differences) == 0 {
+		_ = s.print("\n%s\n", common.Colorize("No changes since the previous run", common.ColorGray))
+		return
+	}
+
+	_ = s.print("\nChanges since the previous run:\n")
+	for _, difference := range differences {
+		var change string
+		switch {
+		case difference.Expected == nil:
+			change = common.Colorize("added "+string(difference.Actual), common.ColorGreen)
+		case difference.Actual == nil:
+			change = common.Colorize("removed "+string(difference.Expected), common.ColorRed)
+		default:
+			change = fmt.Sprintf("%s -> %s", difference.Expected, common.Colorize(string(difference.Actual), common.ColorYellow))
+		}
+		_ = s.print("    %s: %s\n", difference.Path, change)
+	}
+}
+
+func (s *watchSession) print(message string, args ...any) error {
+	err := common.Print(message, args...)
+	if err != nil {
+		log.Warn(err.Error())
+	}
+	return err
+}
+
+// watchedFiles returns the manifest, the source code with its local imports and the payload file.
+// The source code is the one of the current manifest, so that a change of sourceCodePath is followed.
+func (s *watchSession) watchedFiles() []string {
+	files := []string{watchedManifestFile}
+
+	if manifest, err := common.ReadManifest(); err == nil && manifest.SourceCodePath != "" {
+		sourceFile := filepath.Clean(manifest.SourceCodePath)
+		imports := map[string]bool{sourceFile: true}
+		findLocalImports(sourceFile, imports)
+		for file := range imports {
+			files = append(files, file)
+		}
+	}
+
+	if s.payloadFile != "" {
+		files = append(files, filepath.Clean(s.payloadFile))
+	}
+
+	slices.Sort(files[1:])
+	return slices.Compact(files)
+}
+
+// snapshot returns a hash of the content of each watched file, an empty one for the missing files.
+func (s *watchSession) snapshot() map[string]string {
+	snapshot := map[string]string{}
+	for _, file := range s.watchedFiles() {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			snapshot[file] = ""
+			continue
+		}
+		hash := sha256.Sum256(content)
+		snapshot[file] = hex.EncodeToString(hash[:])
+	}
+	return snapshot
+}
+
+// changedFiles returns the files of which the content changed, the added and removed files included.
+func changedFiles(before map[string]string, after map[string]string) []string {
+	var changed []string
+	for file, hash := range after {
+		if previous, found := before[file]; !found || previous != hash {
+			changed = append(changed, file)
+		}
+	}
+	for file := range before {
+		if _, found := after[file]; !found {
+			changed = append(changed, file)
+		}
+	}
+	slices.Sort(changed)
+	return changed
+}
+
+// findLocalImports adds the files imported with a relative path by a source file, and by the files it imports.
+func findLocalImports(sourceFile string, found map[string]bool) {
+	content, err := os.ReadFile(sourceFile)
+	if err != nil {
+		return
+	}
+
+	for _, match := range localImportPattern.FindAllStringSubmatch(string(content), -1) {
+		base := filepath.Join(filepath.Dir(sourceFile), match[1])
+		for _, extension := range importExtensions {
+			file := base + extension
+			if info, err := os.Stat(file); err != nil || info.IsDir() {
+				continue
+			}
+			if !found[file] {
+				found[file] = true
+				findLocalImports(file, found)
+			}
+			break
+		}
+	}
+}
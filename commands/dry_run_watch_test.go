@@ -0,0 +1,205 @@
+//go:build test
+// +build test
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// watchTest is a worker directory with a sandbox stub, in which the tests start 'jf worker dry-run --watch'.
+type watchTest struct {
+	dir      string
+	runCmd   func(args ...string) error
+	requests chan *model.TestRunRequest
+	cancel   context.CancelFunc
+	out      *bytes.Buffer
+}
+
+func newWatchTest(t *testing.T, debounce time.Duration) *watchTest {
+	pollInterval, previousDebounce, newContext := watchPollInterval, watchDebounce, interruptContext
+	t.Cleanup(func() {
+		watchPollInterval, watchDebounce, interruptContext = pollInterval, previousDebounce, newContext
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	watchPollInterval, watchDebounce = 10*time.Millisecond, debounce
+	interruptContext = func() (context.Context, context.CancelFunc) { return ctx, cancel }
+
+	requests := make(chan *model.TestRunRequest, 10)
+	serverStub := common.NewServerStub(t).
+		WithWorkers(&model.WorkerDetails{Key: workerKeyForDryRunTest}).
+		WithDefaultActionsMetadataEndpoint().
+		WithGetOneEndpoint().
+		WithTestEndpoint(nil, common.ResponseBodyFunc(func(t *testing.T, requestBody []byte) any {
+			var request model.TestRunRequest
+			require.NoError(t, json.Unmarshal(requestBody, &request))
+			requests <- &request
+			return map[string]any{"beforeDownload": map[string]any{
+				"data":            map[string]any{"status": 1, "message": request.Data["message"]},
+				"executionStatus": "STATUS_SUCCESS",
+			}}
+		}))
+	common.NewMockWorkerServer(t, serverStub)
+	dir, _ := common.PrepareWorkerDirForTest(t)
+
+	runCmd := common.CreateCliRunner(t, GetInitCommand(), GetDryRunCommand())
+	require.NoError(t, runCmd("worker", "init", "BEFORE_DOWNLOAD", workerKeyForDryRunTest))
+
+	var out bytes.Buffer
+	common.SetCliOut(&out)
+	t.Cleanup(func() { common.SetCliOut(os.Stdout) })
+
+	return &watchTest{dir: dir, runCmd: runCmd, requests: requests, cancel: cancel, out: &out}
+}
+
+func (w *watchTest) writeFile(t *testing.T, name string, content string) {
+	require.NoError(t, os.WriteFile(filepath.Join(w.dir, name), []byte(content), 0644))
+}
+
+func (w *watchTest) nextRequest(t *testing.T) *model.TestRunRequest {
+	select {
+	case request := <-w.requests:
+		return request
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no test-run request sent")
+		return nil
+	}
+}
+
+// start runs the command in the background, the returned channel receives its error once the test calls cancel.
+func (w *watchTest) start(args ...string) chan error {
+	done := make(chan error)
+	go func() {
+		done <- w.runCmd(append([]string{"worker", "dry-run", "--" + flagDryRunWatch}, args...)...)
+	}()
+	return done
+}
+
+func TestWorkerDryRun_Watch(t *testing.T) {
+	w := newWatchTest(t, 20*time.Millisecond)
+
+	w.writeFile(t, "payload.json", `{"message":"one"}`)
+	done := w.start("@payload.json")
+
+	assert.Equal(t, "one", w.nextRequest(t).Data["message"])
+
+	w.writeFile(t, "payload.json", `{"message":"two"}`)
+	assert.Equal(t, "two", w.nextRequest(t).Data["message"])
+
+	source, err := os.ReadFile(filepath.Join(w.dir, "worker.ts"))
+	require.NoError(t, err)
+	w.writeFile(t, "helper.ts", `export const a = 1;`)
+	w.writeFile(t, "worker.ts", "import { a } from './helper';\n"+string(source))
+	w.nextRequest(t)
+
+	w.writeFile(t, "helper.ts", `export const a = 2;`)
+	w.nextRequest(t)
+
+	w.cancel()
+	require.NoError(t, <-done)
+
+	out := w.out.String()
+	assert.Contains(t, out, "test-run #1\n")
+	assert.Contains(t, out, "test-run #2 (payload.json changed)\n")
+	assert.Contains(t, out, "Changes since the previous run:\n    beforeDownload.data.message: \"one\" -> \"two\"\n")
+	assert.Contains(t, out, "test-run #3 (helper.ts, worker.ts changed)\n")
+	assert.Contains(t, out, "test-run #4 (helper.ts changed)\n")
+	assert.Contains(t, out, "No changes since the previous run\n")
+	assert.Contains(t, out, "Watching manifest.json, helper.ts, payload.json, types.ts, worker.ts for changes")
+
+	err = w.runCmd("worker", "dry-run", "--"+flagDryRunWatch, "--"+flagFuzz, "2")
+	assert.EqualError(t, err, "--watch cannot be combined with --fixtures or --fuzz")
+}
+
+func TestWorkerDryRun_WatchDebounce(t *testing.T) {
+	w := newWatchTest(t, 200*time.Millisecond)
+
+	w.writeFile(t, "payload.json", `{"message":"first"}`)
+	done := w.start("@payload.json")
+	w.nextRequest(t)
+
+	// A burst of writes shorter apart than the debounce, as an editor saving a file in several steps
+	for _, message := range []string{"a", "b", "c", "d", "last"} {
+		w.writeFile(t, "payload.json", `{"message":"`+message+`"}`)
+		time.Sleep(30 * time.Millisecond)
+	}
+	manifest, err := os.ReadFile(filepath.Join(w.dir, "manifest.json"))
+	require.NoError(t, err)
+	w.writeFile(t, "manifest.json", string(manifest)+"\n")
+
+	assert.Equal(t, "last", w.nextRequest(t).Data["message"], "the run waits for the files to stop changing")
+	select {
+	case request := <-w.requests:
+		assert.Fail(t, "a single run is expected for a burst of changes", "unexpected run with %v", request.Data)
+	case <-time.After(4 * watchDebounce):
+	}
+
+	w.cancel()
+	require.NoError(t, <-done)
+
+	assert.Contains(t, w.out.String(), "test-run #2 (manifest.json, payload.json changed)\n", "the files changed during the debounce are reported together")
+	assert.NotContains(t, w.out.String(), "test-run #3")
+}
+
+func TestFindLocalImports(t *testing.T) {
+	dir := t.TempDir()
+	writeSource := func(name string, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	writeSource("worker.ts", `
+import { format } from './format';
+import * as lib from "./lib";
+import axios from 'axios';
+const config = require('./config.js');
+const lazy = await import('../outside/lazy');
+import { missing } from './missing';
+`)
+	// format.ts imports the worker back, the cycle must not loop
+	writeSource("format.ts", `import { handler } from './worker';`)
+	writeSource("format.js", `// shadowed by format.ts, the .ts extension is tried first`)
+	writeSource("lib/index.js", `export * from './helpers/strings';`)
+	writeSource("lib/helpers/strings.ts", `export const upper = (s) => s.toUpperCase();`)
+	writeSource("config.js", `module.exports = {};`)
+	writeSource("../outside/lazy.ts", `export const lazy = true;`)
+
+	source := filepath.Join(dir, "worker.ts")
+	found := map[string]bool{source: true}
+	findLocalImports(source, found)
+
+	var files []string
+	for file := range found {
+		relative, err := filepath.Rel(dir, file)
+		require.NoError(t, err)
+		files = append(files, filepath.ToSlash(relative))
+	}
+	assert.ElementsMatch(t, []string{
+		"worker.ts",
+		"format.ts",
+		"lib/index.js",
+		"lib/helpers/strings.ts",
+		"config.js",
+		"../outside/lazy.ts",
+	}, files)
+}
+
+func TestChangedFiles(t *testing.T) {
+	before := map[string]string{"manifest.json": "1", "worker.ts": "2", "helper.ts": "3"}
+	after := map[string]string{"manifest.json": "1", "worker.ts": "4", "payload.json": "5"}
+
+	assert.Equal(t, []string{"helper.ts", "payload.json", "worker.ts"}, changedFiles(before, after))
+	assert.Empty(t, changedFiles(after, after))
+}
@@ -0,0 +1,258 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+
+	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+	"github.com/jfrog/jfrog-cli-platform-services/workerclient"
+)
+
+const (
+	flagExecuteBatch       = "batch"
+	flagExecuteRate        = "rate"
+	flagExecuteStopOnError = "stop-on-error"
+	executeConcurrency     = 4
+	// The status of the lines that could not be executed, and of the responses without an execution status
+	batchStatusError = "ERROR"
+	batchStatusOK    = "OK"
+)
+
+// batchResult is the line printed for each payload of a batch.
+type batchResult struct {
+	// Line is the line of the payload in the batch file, the results are printed as the executions complete
+	Line           int             `json:"line"`
+	Status         string          `json:"status"`
+	DurationMillis int64           `json:"durationMillis"`
+	Error          string          `json:"error,omitempty"`
+	Response       json.RawMessage `json:"response,omitempty"`
+}
+
+func (r *batchResult) failed() bool {
+	result, isExecution := common.ParseExecutionResponse(r.Response, 0)
+	return r.Error != "" || (isExecution && result.Failed())
+}
+
+// batchPayload is a line of a batch file, its data is nil when the line is not a JSON object.
+type batchPayload struct {
+	line int
+	data map[string]any
+	err  error
+}
+
+type executeBatchHandler struct {
+	ctx        *components.Context
+	server     *common.Server
+	workerKey  string
+	projectKey string
+}
+
+// runExecuteBatch executes a worker once per line of a JSON Lines file, with a pool of concurrent requests,
+// and prints a result line per payload as soon as its execution completes.
+func runExecuteBatch(c *components.Context, output *common.Output, batchFile string) error {
+	if !slices.Contains(common.LineFormats, output.Format) {
+		return fmt.Errorf("--%s prints a line per payload, the %s format is not supported", flagExecuteBatch, output.Format)
+	}
+	if len(c.Arguments) > 1 {
+		return fmt.Errorf("a json payload cannot be combined with --%s, the payloads are read from %s", flagExecuteBatch, batchFile)
+	}
+
+	concurrency, err := model.GetConcurrencyParameter(c)
+	if err != nil {
+		return err
+	}
+
+	rate, err := c.GetIntFlagValue(flagExecuteRate)
+	if err != nil || rate < 0 {
+		return fmt.Errorf("invalid --%s provided, expected a number of requests per second", flagExecuteRate)
+	}
+
+	workerKey, projectKey, err := common.ExtractProjectAndKeyFromCommandContext(c, c.Arguments, 0, true)
+	if err != nil {
+		return err
+	}
+
+	server, err := common.GetServerDetails(c)
+	if err != nil {
+		return err
+	}
+
+	if err = common.CheckProjectSupport(c, server, projectKey); err != nil {
+		return err
+	}
+
+	input, err := common.NewInputReader(c).Open(batchFile)
+	if err != nil {
+		return err
+	}
+	defer common.CloseQuietly(input)
+
+	h := &executeBatchHandler{ctx: c, server: server, workerKey: workerKey, projectKey: projectKey}
+	return h.run(input, concurrency, rate, c.GetBoolFlagValue(flagExecuteStopOnError), output)
+}
+
+func (h *executeBatchHandler) run(input io.Reader, concurrency int, rate int, stopOnError bool, output *common.Output) error {
+	start := time.Now()
+
+	// stopped is set on the first failure with --stop-on-error, the payloads read afterward are not sent
+	var stopped atomic.Bool
+	payloads := make(chan *batchPayload)
+	results := make(chan *batchResult)
+
+	var readErr error
+	go func() {
+		defer close(payloads)
+		readErr = readBatchPayloads(input, rate, &stopped, payloads)
+	}()
+
+	var wg sync.WaitGroup
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for payload := range payloads {
+				result := h.execute(payload)
+				// Stopped before the result is sent, so that the payloads read afterward are not sent
+				if stopOnError && result.failed() {
+					stopped.Store(true)
+				}
+				results <- result
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var total, failed int
+	var printErr error
+	for result := range results {
+		total++
+		if result.failed() {
+			failed++
+		}
+		if printErr == nil {
+			printErr = output.PrintLine(&common.Result{
+				Value: result,
+				Table: newBatchResultTable(result),
+				Text:  func() error { return printBatchResultText(result) },
+			})
+		}
+	}
+
+	if printErr != nil {
+		return printErr
+	}
+	if readErr != nil {
+		return readErr
+	}
+
+	log.Info(fmt.Sprintf("Executed %d payloads in %s: %d succeeded, %d failed", total, time.Since(start).Round(time.Millisecond), total-failed, failed))
+	if stopped.Load() {
+		log.Warn(fmt.Sprintf("Stopped at the first failure (--%s), the payloads that were not sent yet were skipped", flagExecuteStopOnError))
+	}
+
+	if failed > 0 {
+		return common.ErrorAlreadyReported(fmt.Errorf("%d of %d payloads failed", failed, total))
+	}
+	return nil
+}
+
+// readBatchPayloads reads the payloads one line at a time, so that a large file is not loaded in memory, the blank lines are skipped.
+// With a rate, the payloads are sent at most rate times per second.
+func readBatchPayloads(input io.Reader, rate int, stopped *atomic.Bool, payloads chan<- *batchPayload) error {
+	var throttle <-chan time.Time
+	if rate > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(rate))
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	reader := bufio.NewReader(input)
+	for line := 1; ; line++ {
+		content, err := reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("cannot read the line %d of the batch file: %w", line, err)
+		}
+
+		if content = bytes.TrimSpace(content); len(content) > 0 {
+			if stopped.Load() {
+				return nil
+			}
+			if throttle != nil {
+				<-throttle
+			}
+			payload := &batchPayload{line: line}
+			if unmarshalErr := json.Unmarshal(content, &payload.data); unmarshalErr != nil || payload.data == nil {
+				payload.err = fmt.Errorf("invalid json payload, expected an object: %s", strconv.Quote(string(content)))
+			}
+			payloads <- payload
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+	}
+}
+
+func (h *executeBatchHandler) execute(payload *batchPayload) *batchResult {
+	result := &batchResult{Line: payload.line, Status: batchStatusError}
+	if payload.err != nil {
+		result.Error = payload.err.Error()
+		return result
+	}
+
+	start := time.Now()
+	response, err := common.CallWorkerClient(h.ctx, h.server, func(ctx context.Context, client *workerclient.Client) (json.RawMessage, error) {
+		return client.Execute(ctx, h.workerKey, h.projectKey, payload.data)
+	})
+	result.DurationMillis = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	if json.Valid(response) {
+		result.Response = response
+	}
+	if execution, isExecution := common.ParseExecutionResponse(response, time.Since(start)); isExecution && execution.Status != "" {
+		result.Status = execution.Status
+		return result
+	}
+	result.Status = batchStatusOK
+	return result
+}
+
+func newBatchResultTable(result *batchResult) *common.Table {
+	return &common.Table{
+		Headers: []string{"LINE", "STATUS", "DURATION_MS", "ERROR"},
+		Rows:    [][]string{{strconv.Itoa(result.Line), result.Status, strconv.FormatInt(result.DurationMillis, 10), result.Error}},
+	}
+}
+
+func printBatchResultText(result *batchResult) error {
+	color := common.ColorGreen
+	if result.failed() {
+		color = common.ColorRed
+	}
+	message := fmt.Sprintf("line %-5d %s %dms", result.Line, common.Colorize(result.Status, color), result.DurationMillis)
+	if result.Error != "" {
+		message += " " + result.Error
+	}
+	return common.Print("%s\n", message)
+}
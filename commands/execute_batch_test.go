@@ -0,0 +1,163 @@
+//go:build test
+// +build test
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/common/format"
+	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerExecute_Batch(t *testing.T) {
+	// The payloads with fail=true make the worker fail
+	var calls atomic.Int64
+	serverStub := common.NewServerStub(t).
+		WithWorkers(&model.WorkerDetails{Key: workerKeyForExecuteTest}).
+		WithDefaultActionsMetadataEndpoint().
+		WithGetOneEndpoint().
+		WithExecuteEndpoint(nil, common.ResponseBodyFunc(func(t *testing.T, requestBody []byte) any {
+			calls.Add(1)
+			var payload map[string]any
+			require.NoError(t, json.Unmarshal(requestBody, &payload))
+			status := "STATUS_SUCCESS"
+			if payload["fail"] == true {
+				status = "STATUS_FAIL"
+			}
+			return map[string]any{"data": payload["id"], "executionStatus": status, "executionDurationMillis": 12}
+		}))
+	common.NewMockWorkerServer(t, serverStub)
+	dir, _ := common.PrepareWorkerDirForTest(t)
+
+	runCmd := common.CreateCliRunner(t, GetInitCommand(), GetExecuteCommand())
+	require.NoError(t, runCmd("worker", "init", "GENERIC_EVENT", workerKeyForExecuteTest))
+
+	var out bytes.Buffer
+	common.SetCliOut(&out)
+	t.Cleanup(func() { common.SetCliOut(os.Stdout) })
+
+	batchFile := filepath.Join(dir, "payloads.jsonl")
+	require.NoError(t, os.WriteFile(batchFile, []byte("{\"id\":1}\n\n{\"id\":2}\n{\"id\":3}\n"), 0644))
+
+	require.NoError(t, runCmd("worker", "execute", "--"+flagExecuteBatch, batchFile, "--"+model.FlagConcurrency, "2"))
+
+	var results []*batchResult
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		result := &batchResult{}
+		require.NoError(t, json.Unmarshal([]byte(line), result), "expected a JSON line, got %s", line)
+		results = append(results, result)
+	}
+	slices.SortFunc(results, func(a, b *batchResult) int { return a.Line - b.Line })
+	require.Len(t, results, 3)
+	assert.Equal(t, []int{1, 3, 4}, []int{results[0].Line, results[1].Line, results[2].Line})
+	for _, result := range results {
+		assert.Equal(t, "STATUS_SUCCESS", result.Status)
+	}
+	assert.JSONEq(t, `{"data":3,"executionStatus":"STATUS_SUCCESS","executionDurationMillis":12}`, string(results[2].Response))
+
+	t.Run("continues after a failure", func(t *testing.T) {
+		out.Reset()
+		common.SetCliIn(strings.NewReader("{\"id\":1,\"fail\":true}\nnot json\n{\"id\":3}\n"))
+		t.Cleanup(func() { common.SetCliIn(os.Stdin) })
+
+		err := runCmd("worker", "execute", "--"+flagExecuteBatch, "-", "--"+model.FlagConcurrency, "1", "--"+format.FlagName, "csv", workerKeyForExecuteTest)
+		assert.EqualError(t, err, "2 of 3 payloads failed")
+		assert.Regexp(t, `^1,STATUS_FAIL,\d+,\n2,ERROR,0,"invalid json payload, expected an object: ""not json"""\n3,STATUS_SUCCESS,\d+,\n$`, out.String())
+	})
+
+	t.Run("stops on error", func(t *testing.T) {
+		out.Reset()
+		calls.Store(0)
+		require.NoError(t, os.WriteFile(batchFile, []byte("{\"fail\":true}\n{\"id\":2}\n{\"id\":3}\n{\"id\":4}\n"), 0644))
+
+		err := runCmd("worker", "execute", "--"+flagExecuteBatch, batchFile, "--"+model.FlagConcurrency, "1", "--"+flagExecuteStopOnError, "--"+format.FlagName, "text")
+		assert.EqualError(t, err, "1 of 2 payloads failed")
+		assert.Equal(t, int64(2), calls.Load(), "the payload read while the first one was executed is sent")
+		assert.Regexp(t, `^line 1     STATUS_FAIL \d+ms\nline 2     STATUS_SUCCESS \d+ms\n$`, out.String())
+	})
+
+	t.Run("limits the rate", func(t *testing.T) {
+		out.Reset()
+		require.NoError(t, os.WriteFile(batchFile, []byte(strings.Repeat("{}\n", 4)), 0644))
+		start := time.Now()
+		require.NoError(t, runCmd("worker", "execute", "--"+flagExecuteBatch, batchFile, "--"+flagExecuteRate, "20", "--"+model.FlagConcurrency, "4"))
+		assert.Greater(t, time.Since(start), 150*time.Millisecond)
+	})
+
+	t.Run("rejects the unsupported options", func(t *testing.T) {
+		err := runCmd("worker", "execute", "--"+flagExecuteBatch, batchFile, "--"+format.FlagName, "table")
+		assert.EqualError(t, err, "--batch prints a line per payload, the table format is not supported")
+
+		err = runCmd("worker", "execute", "--"+flagExecuteBatch, batchFile, workerKeyForExecuteTest, `{}`)
+		assert.EqualError(t, err, "a json payload cannot be combined with --batch, the payloads are read from "+batchFile)
+
+		err = runCmd("worker", "execute", "--"+flagExecuteBatch, filepath.Join(dir, "missing.jsonl"))
+		assert.ErrorContains(t, err, "cannot read "+filepath.Join(dir, "missing.jsonl"))
+	})
+}
+
+// readAllBatchPayloads runs readBatchPayloads and returns the payloads it sent, with the time each one was received.
+func readAllBatchPayloads(t *testing.T, input string, rate int, onPayload func(payload *batchPayload, stopped *atomic.Bool)) ([]*batchPayload, []time.Time) {
+	var stopped atomic.Bool
+	payloads := make(chan *batchPayload)
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(payloads)
+		readErr <- readBatchPayloads(strings.NewReader(input), rate, &stopped, payloads)
+	}()
+
+	var read []*batchPayload
+	var received []time.Time
+	for payload := range payloads {
+		read, received = append(read, payload), append(received, time.Now())
+		if onPayload != nil {
+			onPayload(payload, &stopped)
+		}
+	}
+	require.NoError(t, <-readErr)
+	return read, received
+}
+
+func TestReadBatchPayloads(t *testing.T) {
+	payloads, _ := readAllBatchPayloads(t, "{\"id\":1}\r\n\n   \n[1,2]\nnull\n  {\"id\":5}  \n{\"id\":6}", 0, nil)
+
+	require.Len(t, payloads, 5)
+	assert.Equal(t, []int{1, 4, 5, 6, 7}, []int{payloads[0].line, payloads[1].line, payloads[2].line, payloads[3].line, payloads[4].line}, "the blank lines are skipped but counted")
+	assert.Equal(t, map[string]any{"id": 1.0}, payloads[0].data)
+	assert.EqualError(t, payloads[1].err, `invalid json payload, expected an object: "[1,2]"`)
+	assert.EqualError(t, payloads[2].err, `invalid json payload, expected an object: "null"`)
+	assert.Equal(t, map[string]any{"id": 5.0}, payloads[3].data)
+	assert.Equal(t, map[string]any{"id": 6.0}, payloads[4].data, "the last line does not need a line break")
+}
+
+func TestReadBatchPayloads_StopOnError(t *testing.T) {
+	payloads, _ := readAllBatchPayloads(t, strings.Repeat("{}\n", 10), 0, func(payload *batchPayload, stopped *atomic.Bool) {
+		stopped.Store(true)
+	})
+
+	// The reader may be sending the second payload when the first one stops the batch, no other payload is read afterward
+	assert.LessOrEqual(t, len(payloads), 2)
+}
+
+func TestReadBatchPayloads_Rate(t *testing.T) {
+	start := time.Now()
+	payloads, received := readAllBatchPayloads(t, strings.Repeat("{}\n", 5), 25, nil)
+
+	require.Len(t, payloads, 5)
+	assert.GreaterOrEqual(t, received[0].Sub(start), 30*time.Millisecond, "the first payload waits for the first tick")
+	for i := 1; i < len(received); i++ {
+		assert.GreaterOrEqual(t, received[i].Sub(received[i-1]), 30*time.Millisecond, "at most 25 payloads per second")
+	}
+}
@@ -1,9 +1,10 @@
 package commands
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
-	"net/http"
+	"slices"
+	"time"
 
 	"github.com/jfrog/jfrog-cli-core/v2/common/format"
 	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
@@ -14,6 +15,7 @@ import (
 	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
 
 	"github.com/jfrog/jfrog-cli-platform-services/model"
+	"github.com/jfrog/jfrog-cli-platform-services/workerclient"
 )
 
 func GetExecuteCommand() components.Command {
@@ -37,22 +39,39 @@ Common patterns:
   $ jf worker execute my-worker @./payload.json
   $ jf worker execute @- < payload.json    # worker name read from manifest.json
   $ jf worker execute my-worker '{}' --project my-project
+  $ jf worker execute my-worker '{}' --format text
   $ jf worker execute my-worker '{}' --format table
+  $ jf worker execute my-worker '{}' --query '.data.status' --format csv
+  $ jf worker execute my-worker --batch payloads.jsonl --concurrency 8
+  $ jf worker execute my-worker --batch payloads.jsonl --rate 20 --stop-on-error --format text
+  $ generate-payloads | jf worker execute my-worker --batch - --format csv
 
 Gotchas:
 - Only GENERIC_EVENT workers can be triggered with this command; event-driven workers (BEFORE_UPLOAD, etc.) fire when the underlying event occurs.
 - If you omit the worker name, the name is read from manifest.json and the last argument is treated as the payload.
 - Use '@file' or '@-' to load the payload from a file or stdin instead of inlining JSON.
-
-Related: jf worker deploy, jf worker test-run, jf worker execution-history`,
+- The text format shows the execution status, the duration, the value returned by the worker as indented JSON and its logs, with the errors in red in a terminal.
+- The table and csv formats flatten the response to a row per dotted path (e.g. data.user.name, logs[0].level): status and duration first, then the data fields sorted by name.
+- The duration is the round trip of the request, network included.
+- --batch reads a payload per line (JSON Lines, blank lines skipped) and streams the file, so it can be large or piped. It prints a result per payload, with its line, execution status, duration, error and response, in the order the executions complete rather than the order of the file; use the line to match them. The json format prints JSON Lines, csv prints rows without headers, table and yaml are not supported.
+- With --batch, all the payloads are executed by default and the command fails at the end when one failed (request error, invalid line or a status other than a success); --stop-on-error stops sending new payloads after the first failure, the executions in flight still complete.
+- --concurrency (4 by default) and --rate bound the load a batch puts on the server; the execute endpoint may throttle, in which case lower them or rely on --retries.
+
+Related: jf worker deploy, jf worker test-run, jf worker wait, jf worker execution-history`,
 		Aliases:          []string{"exec", "e"},
-		SupportedFormats: []format.OutputFormat{format.Json, format.Table},
+		SupportedFormats: common.TextOutputFormats,
 		DefaultFormat:    format.Json,
-		Flags: []components.Flag{
+		Flags: slices.Concat([]components.Flag{
 			plugins_common.GetServerIdFlag(),
 			model.GetTimeoutFlag(),
 			model.GetProjectKeyFlag(),
-		},
+			components.NewStringFlag(flagExecuteBatch, "A JSON Lines file, or - for the standard input, of which each line is a payload to execute the worker with.", components.WithStrDefaultValue("")),
+			model.GetConcurrencyFlag("With --batch, the number of executions running at the same time.", executeConcurrency),
+			components.NewStringFlag(flagExecuteRate, "With --batch, the maximum number of executions started per second, 0 for no limit.", components.WithIntDefaultValue(0)),
+			components.NewBoolFlag(flagExecuteStopOnError, "With --batch, stop sending the payloads after the first failure instead of executing them all.", components.WithBoolDefaultValue(false)),
+			model.GetTemplateFlag(),
+			model.GetQueryFlag(),
+		}, model.GetMetadataFlags(), model.GetHTTPClientFlags()),
 		Arguments: []components.Argument{
 			model.GetWorkerKeyArgument(),
 			model.GetJSONPayloadArgument(),
@@ -62,17 +81,13 @@ Related: jf worker deploy, jf worker test-run, jf worker execution-history`,
 }
 
 func runExecuteCommand(c *components.Context) error {
-	outputFormat, err := c.GetOutputFormat()
+	output, err := common.NewOutput(c)
 	if err != nil {
 		return err
 	}
 
-	var contentHandler func([]byte) error
-	switch outputFormat {
-	case format.Json:
-		contentHandler = common.PrintJSON
-	case format.Table:
-		contentHandler = printExecuteResponseAsTable
+	if batchFile := c.GetStringFlagValue(flagExecuteBatch); batchFile != "" {
+		return runExecuteBatch(c, output, batchFile)
 	}
 
 	workerKey, projectKey, err := common.ExtractProjectAndKeyFromCommandContext(c, c.Arguments, 1, true)
@@ -84,11 +99,15 @@ func runExecuteCommand(c *components.Context) error {
 		log.Info("No worker name provided, it will be taken from the manifest. Last argument is considered as a json payload.")
 	}
 
-	server, err := model.GetServerDetails(c)
+	server, err := common.GetServerDetails(c)
 	if err != nil {
 		return err
 	}
 
+	if err = common.CheckProjectSupport(c, server, projectKey); err != nil {
+		return err
+	}
+
 	inputReader := common.NewInputReader(c)
 
 	data, err := inputReader.ReadData()
@@ -96,35 +115,13 @@ func runExecuteCommand(c *components.Context) error {
 		return err
 	}
 
-	body, err := json.Marshal(data)
+	start := time.Now()
+	response, err := common.CallWorkerClient(c, server, func(ctx context.Context, client *workerclient.Client) (json.RawMessage, error) {
+		return client.Execute(ctx, workerKey, projectKey, data)
+	})
 	if err != nil {
 		return err
 	}
 
-	return common.CallWorkerAPI(c, common.APICallParams{
-		Method:      http.MethodPost,
-		ServerURL:   server.GetUrl(),
-		ServerToken: server.GetAccessToken(),
-		OkStatuses:  []int{http.StatusOK},
-		Body:        body,
-		ProjectKey:  projectKey,
-		Path:        []string{"execute", workerKey},
-		OnContent:   contentHandler,
-	})
-}
-
-func printExecuteResponseAsTable(responseBytes []byte) error {
-	var data map[string]any
-	if err := json.Unmarshal(responseBytes, &data); err != nil {
-		return common.PrintJSON(responseBytes)
-	}
-
-	writer := common.NewCsvWriter()
-	for k, v := range data {
-		if err := writer.Write([]string{k, fmt.Sprint(v)}); err != nil {
-			return err
-		}
-	}
-	writer.Flush()
-	return writer.Error()
+	return output.Print(common.NewExecutionOutput(response, time.Since(start)))
 }
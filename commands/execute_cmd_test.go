@@ -246,6 +246,21 @@ func TestWorkerExecute_FormatTable(t *testing.T) {
 		"expected table output to contain response fields, got: %s", outputStr)
 }
 
+func TestWorkerExecute_FormatYamlAndQuery(t *testing.T) {
+	runCmd, out := setupExecuteFormatTest(t)
+
+	require.NoError(t, runCmd("worker", "execute", workerKeyForExecuteTest, "--"+format.FlagName, "yaml", `{}`))
+	assert.Equal(t, "result: done\nstatus: OK\n", out.String())
+
+	out.Reset()
+	require.NoError(t, runCmd("worker", "execute", workerKeyForExecuteTest, "--"+model.FlagQuery, ".status", `{}`))
+	assert.Equal(t, `"OK"`, out.String())
+
+	out.Reset()
+	require.NoError(t, runCmd("worker", "execute", workerKeyForExecuteTest, "--"+format.FlagName, "csv", `{}`))
+	assert.Equal(t, "result,done\nstatus,OK\n", out.String())
+}
+
 func TestWorkerExecute_FormatDefault(t *testing.T) {
 	runCmd, out := setupExecuteFormatTest(t)
 
@@ -0,0 +1,178 @@
+package commands
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/utils/log"
+
+	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+	"github.com/jfrog/jfrog-cli-platform-services/workerclient"
+)
+
+const (
+	flagHistoryFollow         = "follow"
+	followPollIntervalMs      = 5000
+	followInitialEntriesCount = 10
+)
+
+// historyFollower prints the new entries of the execution histories of workers until it is interrupted.
+type historyFollower struct {
+	c           model.IntFlagProvider
+	server      *common.Server
+	workerKeys  []string
+	options     workerclient.ExecutionHistoryOptions
+	filter      *common.ExecutionHistoryFilter
+	output      *common.Output
+	interval    time.Duration
+	initialSize int
+	// printed holds, by execution, the last entry printed. A running execution is printed again once it ends.
+	printed map[string]*model.ExecutionHistoryEntry
+	// since is the start time from which the histories are polled, the entries started before are known and final
+	since time.Time
+}
+
+// follow prints the last entries of the histories, then polls them and prints the new entries, from the oldest to the newest.
+// The errors of the first poll are returned, the next ones are logged and the polling goes on.
+func (f *historyFollower) follow() error {
+	ctx, cancel := interruptContext()
+	defer cancel()
+
+	f.printed = map[string]*model.ExecutionHistoryEntry{}
+	for first := true; ; first = false {
+		entries, err := f.poll(first)
+		switch {
+		case err != nil && first:
+			return err
+		case err != nil:
+			log.Warn(fmt.Sprintf("Cannot read the execution history, next attempt in %s: %s", f.interval, err))
+		default:
+			if err = f.printNewEntries(entries, first); err != nil {
+				return err
+			}
+			f.advance(entries)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Info("Follow stopped")
+			return nil
+		case <-time.After(f.interval):
+		}
+	}
+}
+
+// poll reads the last entries of the histories on the first call, then the entries started since the last running execution,
+// or since the newest completed one, so that the polls read a single page of the servers paginating the histories.
+// The next polls ignore the status filter, which is applied by printNewEntries, to follow the running executions until they end.
+func (f *historyFollower) poll(first bool) ([]*model.ExecutionHistoryEntry, error) {
+	if first {
+		return common.FetchExecutionHistories(f.c, f.server, f.workerKeys, f.options, f.filter, f.initialSize)
+	}
+
+	filter := *f.filter
+	filter.Statuses = nil
+	if f.since.After(filter.Since) {
+		filter.Since = f.since
+	}
+	entries, err := common.FetchExecutionHistories(f.c, f.server, f.workerKeys, f.options, &filter, 0)
+	if err != nil {
+		return nil, err
+	}
+	return filter.Apply(entries), nil
+}
+
+// advance moves the start of the next polls to the oldest running execution, or to the newest completed one,
+// and forgets the entries printed before it, which the next polls do not read anymore.
+func (f *historyFollower) advance(entries []*model.ExecutionHistoryEntry) {
+	var newestCompleted, oldestRunning int64
+	for _, entry := range entries {
+		if entry.Completed() {
+			newestCompleted = max(newestCompleted, entry.StartTimeMillis)
+		} else if oldestRunning == 0 || entry.StartTimeMillis < oldestRunning {
+			oldestRunning = entry.StartTimeMillis
+		}
+	}
+	since := newestCompleted
+	if oldestRunning > 0 && (since == 0 || oldestRunning < since) {
+		since = oldestRunning
+	}
+	if since == 0 || !time.UnixMilli(since).After(f.since) {
+		return
+	}
+
+	f.since = time.UnixMilli(since)
+	for key, entry := range f.printed {
+		if entry.StartTimeMillis < since {
+			delete(f.printed, key)
+		}
+	}
+}
+
+func (f *historyFollower) printNewEntries(entries []*model.ExecutionHistoryEntry, first bool) error {
+	var newEntries []*model.ExecutionHistoryEntry
+	for _, entry := range f.filter.Apply(entries) {
+		if printed, found := f.printed[common.ExecutionHistoryKey(entry)]; !found || (!printed.Completed() && entry.Completed()) {
+			newEntries = append(newEntries, entry)
+		}
+	}
+
+	common.SortExecutionHistory(newEntries)
+	if first && len(newEntries) > f.initialSize {
+		// The older entries are not printed, but they are known so that they are not printed later
+		for _, entry := range newEntries[f.initialSize:] {
+			f.printed[common.ExecutionHistoryKey(entry)] = entry
+		}
+		newEntries = newEntries[:f.initialSize]
+	}
+	slices.Reverse(newEntries)
+
+	for _, entry := range newEntries {
+		f.printed[common.ExecutionHistoryKey(entry)] = entry
+		if err := f.output.PrintLine(&common.Result{
+			Value: entry,
+			Table: newExecutionHistoryTable([]*model.ExecutionHistoryEntry{entry}),
+			Text:  func() error { return printExecutionHistoryLine(entry) },
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printExecutionHistoryLine prints an entry as "<start> <worker> <status> <duration> <version> <triggered by> <trace ID>", with the status in color.
+func printExecutionHistoryLine(entry *model.ExecutionHistoryEntry) error {
+	status := fmt.Sprintf("%-16s", entry.ExecutionStatus)
+	switch {
+	case !entry.Completed():
+		status = common.Colorize(status, common.ColorYellow)
+	case entry.Succeeded():
+		status = common.Colorize(status, common.ColorGreen)
+	default:
+		status = common.Colorize(status, common.ColorRed)
+	}
+
+	duration := "-"
+	if entry.Completed() && entry.EndTimeMillis >= entry.StartTimeMillis {
+		duration = (time.Duration(entry.EndTimeMillis-entry.StartTimeMillis) * time.Millisecond).String()
+	}
+
+	fields := []string{
+		time.UnixMilli(entry.StartTimeMillis).UTC().Format(time.RFC3339),
+		entry.WorkerKey,
+		status,
+		duration,
+	}
+	for _, field := range []string{entry.ExecutedVersion, entry.TriggeredBy, entry.TraceID} {
+		fields = append(fields, cmp.Or(field, "-"))
+	}
+	if entry.TestRun {
+		fields = append(fields, common.Colorize("(test-run)", common.ColorGray))
+	}
+
+	return common.Print("%s\n", strings.Join(fields, "  "))
+}
@@ -0,0 +1,302 @@
+//go:build test
+// +build test
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/common/format"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+	"github.com/jfrog/jfrog-cli-platform-services/workerclient"
+)
+
+func TestExecutionHistory_Follow(t *testing.T) {
+	newEntry := func(workerKey string, traceID string, status string, startTimeMillis int64) *common.ExecutionHistoryEntryStub {
+		return &common.ExecutionHistoryEntryStub{WorkerKey: workerKey, ExecutionStatus: status, StartTimeMillis: startTimeMillis, EndTimeMillis: startTimeMillis + 1500, TraceID: traceID}
+	}
+
+	tests := []struct {
+		name      string
+		args      []string
+		histories map[string][]common.ExecutionHistoryStub
+		traces    []string
+	}{
+		{
+			name: "new entries",
+			args: []string{"my-worker"},
+			histories: map[string][]common.ExecutionHistoryStub{"my-worker": {
+				{newEntry("my-worker", "a", "STATUS_SUCCESS", 1000)},
+				{newEntry("my-worker", "a", "STATUS_SUCCESS", 1000), newEntry("my-worker", "b", "STATUS_FAIL", 2000)},
+				{newEntry("my-worker", "c", "STATUS_SUCCESS", 4000), newEntry("my-worker", "a", "STATUS_SUCCESS", 1000), newEntry("my-worker", "b", "STATUS_FAIL", 2000), newEntry("my-worker", "d", "STATUS_SUCCESS", 3000)},
+			}},
+			traces: []string{"a", "b", "d", "c"},
+		},
+		{
+			name: "running execution printed again once ended",
+			args: []string{"my-worker"},
+			histories: map[string][]common.ExecutionHistoryStub{"my-worker": {
+				{newEntry("my-worker", "a", "STATUS_RUNNING", 1000)},
+				{newEntry("my-worker", "a", "STATUS_RUNNING", 1000)},
+				{newEntry("my-worker", "a", "STATUS_SUCCESS", 1000)},
+			}},
+			traces: []string{"a", "a"},
+		},
+		{
+			name: "initial entries limited",
+			args: []string{"my-worker", "--" + flagHistoryLimit, "2"},
+			histories: map[string][]common.ExecutionHistoryStub{"my-worker": {
+				{newEntry("my-worker", "a", "STATUS_SUCCESS", 1000), newEntry("my-worker", "b", "STATUS_SUCCESS", 2000), newEntry("my-worker", "c", "STATUS_SUCCESS", 3000)},
+				{newEntry("my-worker", "a", "STATUS_SUCCESS", 1000), newEntry("my-worker", "b", "STATUS_SUCCESS", 2000), newEntry("my-worker", "c", "STATUS_SUCCESS", 3000), newEntry("my-worker", "d", "STATUS_SUCCESS", 4000)},
+			}},
+			traces: []string{"b", "c", "d"},
+		},
+		{
+			name: "filtered",
+			args: []string{"my-worker", "--" + flagHistoryStatus, "fail"},
+			histories: map[string][]common.ExecutionHistoryStub{"my-worker": {
+				{newEntry("my-worker", "a", "STATUS_SUCCESS", 1000)},
+				{newEntry("my-worker", "a", "STATUS_SUCCESS", 1000), newEntry("my-worker", "b", "STATUS_FAIL", 2000), newEntry("my-worker", "c", "STATUS_SUCCESS", 3000)},
+			}},
+			traces: []string{"b"},
+		},
+		{
+			name: "several workers",
+			args: []string{"worker-b", "worker-a"},
+			histories: map[string][]common.ExecutionHistoryStub{
+				"worker-a": {
+					{newEntry("worker-a", "a1", "STATUS_SUCCESS", 1000)},
+					{newEntry("worker-a", "a1", "STATUS_SUCCESS", 1000), newEntry("worker-a", "a3", "STATUS_SUCCESS", 3000)},
+				},
+				"worker-b": {
+					{newEntry("worker-b", "b2", "STATUS_FAIL", 2000)},
+					{newEntry("worker-b", "b2", "STATUS_FAIL", 2000), newEntry("worker-b", "b4", "STATUS_SUCCESS", 4000)},
+				},
+			},
+			traces: []string{"a1", "b2", "a3", "b4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serverStub := common.NewServerStub(t).WithGetExecutionHistoryEndpoint()
+			for workerKey, histories := range tt.histories {
+				serverStub.WithWorkerExecutionHistorySequence(workerKey, histories...)
+			}
+			common.NewMockWorkerServer(t, serverStub)
+			common.PrepareWorkerDirForTest(t)
+			interruptAfterHistories(t, serverStub, tt.histories)
+
+			var out bytes.Buffer
+			common.SetCliOut(&out)
+			t.Cleanup(func() { common.SetCliOut(os.Stdout) })
+
+			runCmd := common.CreateCliRunner(t, GetShowExecutionHistoryCommand())
+			args := append([]string{"worker", "execution-history", "--" + flagHistoryFollow, "--" + flagPollInterval, "10", "--" + format.FlagName, "json"}, tt.args...)
+			require.NoError(t, runCmd(args...))
+
+			traces := []string{}
+			for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+				var entry model.ExecutionHistoryEntry
+				require.NoError(t, json.Unmarshal([]byte(line), &entry), "expected JSON Lines, got: %s", out.String())
+				traces = append(traces, entry.TraceID)
+			}
+			assert.Equal(t, tt.traces, traces)
+		})
+	}
+}
+
+func TestExecutionHistory_FollowProjectWide(t *testing.T) {
+	serverStub := common.NewServerStub(t).
+		WithStatefulWorkers().
+		WithWorkers(&model.WorkerDetails{Key: "worker-a", ProjectKey: "my-project"}).
+		WithProjectKey("my-project").
+		WithGetAllEndpoint().
+		WithWorkerExecutionHistorySequence("worker-a",
+			common.ExecutionHistoryStub{{WorkerKey: "worker-a", ExecutionStatus: "STATUS_RUNNING", StartTimeMillis: 1000, TraceID: "a1"}},
+			common.ExecutionHistoryStub{{WorkerKey: "worker-a", ExecutionStatus: "STATUS_FAIL", StartTimeMillis: 1000, EndTimeMillis: 3500, TraceID: "a1"}},
+		).
+		WithGetExecutionHistoryEndpoint()
+	common.NewMockWorkerServer(t, serverStub)
+	common.PrepareWorkerDirForTest(t)
+	interruptAfterHistories(t, serverStub, map[string][]common.ExecutionHistoryStub{"worker-a": make([]common.ExecutionHistoryStub, 2)})
+
+	var out bytes.Buffer
+	common.SetCliOut(&out)
+	t.Cleanup(func() { common.SetCliOut(os.Stdout) })
+
+	runCmd := common.CreateCliRunner(t, GetShowExecutionHistoryCommand())
+	require.NoError(t, runCmd("worker", "execution-history", "--"+flagHistoryFollow, "--"+flagPollInterval, "10", "--"+flagHistoryProjectWide, "--"+model.FlagProjectKey, "my-project"))
+
+	assert.Equal(t, []string{
+		"1970-01-01T00:00:01Z  worker-a  STATUS_RUNNING    -  -  -  a1",
+		"1970-01-01T00:00:01Z  worker-a  STATUS_FAIL       2.5s  -  -  a1",
+	}, strings.Split(strings.TrimSpace(out.String()), "\n"))
+}
+
+func TestExecutionHistory_FollowInvalid(t *testing.T) {
+	_, workerHistory := testExecutionHistoryWorkerHistory(t)
+	runCmd := setupExecutionHistoryFormatTest(t, workerHistory)
+
+	err := runCmd("worker", "execution-history", "--"+flagHistoryFollow, "--"+format.FlagName, "table")
+	assert.EqualError(t, err, "--follow prints an entry per line, the table format is not supported")
+
+	err = runCmd("worker", "execution-history", "--"+flagHistoryFollow, "--"+flagPollInterval, "0")
+	assert.EqualError(t, err, "invalid --poll-interval-ms provided, expected a positive number of milliseconds")
+}
+
+func TestHistoryFollower_Poll(t *testing.T) {
+	history := []*model.ExecutionHistoryEntry{
+		{WorkerKey: "my-worker", ExecutionStatus: "STATUS_RUNNING", StartTimeMillis: 3000, TraceID: "c"},
+		{WorkerKey: "my-worker", ExecutionStatus: "STATUS_SUCCESS", StartTimeMillis: 2000, TraceID: "b"},
+		{WorkerKey: "my-worker", ExecutionStatus: "STATUS_FAIL", StartTimeMillis: 1000, TraceID: "a"},
+	}
+
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		queries = append(queries, req.URL.Query())
+		// The server ignores the filters, the follower applies them
+		_ = json.NewEncoder(res).Encode(history)
+	}))
+	t.Cleanup(server.Close)
+
+	follower := &historyFollower{
+		c:           common.IntFlagMap{},
+		server:      &common.Server{ServerDetails: &config.ServerDetails{Url: server.URL + "/"}},
+		workerKeys:  []string{"my-worker"},
+		options:     workerclient.ExecutionHistoryOptions{WorkerKey: "my-worker"},
+		filter:      &common.ExecutionHistoryFilter{Statuses: []string{"fail"}},
+		initialSize: 10,
+		printed:     map[string]*model.ExecutionHistoryEntry{},
+	}
+
+	entries, err := follower.poll(true)
+	require.NoError(t, err)
+	assert.Len(t, entries, 3)
+	require.Len(t, queries, 1)
+	assert.Equal(t, "STATUS_FAIL", queries[0].Get("status"))
+	assert.False(t, queries[0].Has("since"))
+
+	for _, entry := range entries {
+		follower.printed[common.ExecutionHistoryKey(entry)] = entry
+	}
+	follower.advance(entries)
+	assert.Equal(t, time.UnixMilli(2000), follower.since)
+	assert.NotContains(t, follower.printed, "a")
+
+	// The next polls read the executions started since the oldest running one, or the newest completed one, whatever their status
+	entries, err = follower.poll(false)
+	require.NoError(t, err)
+	require.Len(t, queries, 2)
+	assert.Equal(t, "2000", queries[1].Get("since"))
+	assert.False(t, queries[1].Has("status"))
+	assert.Equal(t, []string{"c", "b"}, []string{entries[0].TraceID, entries[1].TraceID})
+}
+
+func TestHistoryFollower_Advance(t *testing.T) {
+	newEntry := func(traceID string, status string, startTimeMillis int64) *model.ExecutionHistoryEntry {
+		return &model.ExecutionHistoryEntry{ExecutionStatus: status, StartTimeMillis: startTimeMillis, TraceID: traceID}
+	}
+
+	tests := []struct {
+		name        string
+		since       int64
+		entries     []*model.ExecutionHistoryEntry
+		wantSince   int64
+		wantPrinted []string
+	}{
+		{
+			name:        "newest completed execution",
+			entries:     []*model.ExecutionHistoryEntry{newEntry("a", "STATUS_SUCCESS", 1000), newEntry("b", "STATUS_FAIL", 2000), newEntry("c", "STATUS_SUCCESS", 3000)},
+			wantSince:   3000,
+			wantPrinted: []string{"c"},
+		},
+		{
+			name:        "oldest running execution",
+			entries:     []*model.ExecutionHistoryEntry{newEntry("a", "STATUS_SUCCESS", 1000), newEntry("b", "STATUS_RUNNING", 2000), newEntry("c", "STATUS_SUCCESS", 3000)},
+			wantSince:   2000,
+			wantPrinted: []string{"b", "c"},
+		},
+		{
+			name:        "only running executions",
+			entries:     []*model.ExecutionHistoryEntry{newEntry("a", "STATUS_PENDING", 1000), newEntry("b", "STATUS_RUNNING", 2000)},
+			wantSince:   1000,
+			wantPrinted: []string{"a", "b"},
+		},
+		{
+			name:        "never goes back",
+			since:       2500,
+			entries:     []*model.ExecutionHistoryEntry{newEntry("a", "STATUS_SUCCESS", 1000), newEntry("b", "STATUS_SUCCESS", 2000)},
+			wantSince:   2500,
+			wantPrinted: []string{"a", "b"},
+		},
+		{
+			name:      "no entries",
+			since:     2500,
+			wantSince: 2500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			follower := &historyFollower{printed: map[string]*model.ExecutionHistoryEntry{}}
+			if tt.since > 0 {
+				follower.since = time.UnixMilli(tt.since)
+			}
+			for _, entry := range tt.entries {
+				follower.printed[common.ExecutionHistoryKey(entry)] = entry
+			}
+
+			follower.advance(tt.entries)
+			assert.Equal(t, time.UnixMilli(tt.wantSince), follower.since)
+			printed := []string{}
+			for _, entry := range follower.printed {
+				printed = append(printed, entry.TraceID)
+			}
+			assert.ElementsMatch(t, tt.wantPrinted, printed)
+		})
+	}
+}
+
+// interruptAfterHistories simulates a Ctrl-C once every history of the sequences has been returned.
+func interruptAfterHistories(t *testing.T, serverStub *common.ServerStub, histories map[string][]common.ExecutionHistoryStub) {
+	ctx, cancel := context.WithCancel(context.Background())
+	newContext := interruptContext
+	interruptContext = func() (context.Context, context.CancelFunc) { return ctx, cancel }
+	t.Cleanup(func() {
+		cancel()
+		interruptContext = newContext
+	})
+
+	go func() {
+		defer cancel()
+		for {
+			done := true
+			for workerKey, sequence := range histories {
+				done = done && serverStub.ExecutionHistoryRequests(workerKey) >= len(sequence)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(5 * time.Millisecond):
+				if done {
+					return
+				}
+			}
+		}
+	}()
+}
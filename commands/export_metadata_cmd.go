@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/common/format"
+	plugins_common "github.com/jfrog/jfrog-cli-core/v2/plugins/common"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+
+	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+)
+
+func GetExportMetadataCommand() components.Command {
+	return components.Command{
+		Name:        "export-metadata",
+		Description: "Export the actions metadata and the options of the server to a file, for machines that cannot reach the server.",
+		AIDescription: `Export the actions metadata (the same content as 'jf worker list-event --format json') and the server options into a single JSON bundle. The bundle is then passed with --actions-file / --options-file to the commands that need this metadata, on machines that cannot reach the platform (air-gapped build agents).
+
+When to use:
+- Preparing an air-gapped build agent to scaffold workers with 'jf worker init --actions-file metadata.json'.
+- Validating manifests offline against a known set of actions.
+- Snapshotting the actions available on a server, e.g. to compare two environments.
+
+Prerequisites:
+- Configured server (jf c add or jf login), on a machine that can reach it.
+- For project-scoped action sets, pass --project-key.
+
+Common patterns:
+  $ jf worker export-metadata metadata.json
+  $ jf worker export-metadata metadata.json --project-key my-project
+  $ jf worker export-metadata > metadata.json
+  $ jf worker init BEFORE_DOWNLOAD my-worker --actions-file metadata.json    # on the air-gapped machine
+
+Gotchas:
+- Without a file argument the bundle is printed to the standard output, where --format yaml, --query and --template apply; the file is always JSON, the format read by --actions-file and --options-file.
+- The actions depend on the --project-key scope: export one bundle per project if the projects expose different actions.
+- The bundle is not refreshed automatically; export it again after installing or upgrading applications on the server.
+
+Related: jf worker list-event, jf worker init, jf worker deploy`,
+		Aliases:          []string{"em"},
+		SupportedFormats: common.DocumentFormats,
+		DefaultFormat:    format.Json,
+		Flags: slices.Concat([]components.Flag{
+			plugins_common.GetServerIdFlag(),
+			model.GetTimeoutFlag(),
+			model.GetProjectKeyFlag(),
+			model.GetTemplateFlag(),
+			model.GetQueryFlag(),
+		}, model.GetMetadataFlags(), model.GetHTTPClientFlags()),
+		Arguments: []components.Argument{
+			{
+				Name:        "file",
+				Description: "The file to write the bundle to. The bundle is printed to the standard output if omitted.",
+				Optional:    true,
+			},
+		},
+		Action: func(c *components.Context) error {
+			output, err := common.NewOutput(c)
+			if err != nil {
+				return err
+			}
+
+			server, err := common.GetServerDetails(c)
+			if err != nil {
+				return err
+			}
+
+			projectKey := c.GetStringFlagValue(model.FlagProjectKey)
+
+			if err = common.CheckProjectSupport(c, server, projectKey); err != nil {
+				return err
+			}
+
+			actionsMeta, err := common.FetchActions(c, server, projectKey)
+			if err != nil {
+				return err
+			}
+
+			options, err := common.FetchOptions(c, server)
+			if err != nil {
+				return err
+			}
+
+			bundle := &model.MetadataBundle{
+				ServerURL:  server.GetUrl(),
+				ProjectKey: projectKey,
+				ExportedAt: time.Now().UTC(),
+				Actions:    actionsMeta,
+				Options:    options,
+			}
+
+			if len(c.Arguments) == 0 {
+				return output.Print(&common.Result{Value: bundle})
+			}
+
+			return writeMetadataBundle(c.Arguments[0], bundle)
+		},
+	}
+}
+
+func writeMetadataBundle(path string, bundle *model.MetadataBundle) error {
+	content, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.WriteFile(path, append(content, '\n'), 0o644); err != nil {
+		return fmt.Errorf("cannot write the metadata bundle: %w", err)
+	}
+
+	log.Info(fmt.Sprintf("Metadata of %d actions exported to %s", len(bundle.Actions), path))
+
+	return nil
+}
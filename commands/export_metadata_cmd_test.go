@@ -0,0 +1,162 @@
+//go:build test
+// +build test
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/common/format"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+)
+
+func TestExportMetadata(t *testing.T) {
+	server, _ := common.NewMockWorkerServer(t, common.NewServerStub(t).WithDefaultActionsMetadataEndpoint().WithOptionsEndpoint())
+
+	bundleFile := filepath.Join(t.TempDir(), "metadata.json")
+
+	runCmd := common.CreateCliRunner(t, GetExportMetadataCommand())
+	require.NoError(t, runCmd("worker", "export-metadata", bundleFile))
+
+	content, err := os.ReadFile(bundleFile)
+	require.NoError(t, err)
+
+	bundle := new(model.MetadataBundle)
+	require.NoError(t, json.Unmarshal(content, bundle))
+
+	assert.Equal(t, server.BaseUrl(), bundle.ServerURL)
+	assert.Equal(t, common.LoadSampleActions(t), common.ActionsMetadata(bundle.Actions))
+	assert.NotNil(t, bundle.Options)
+	assert.False(t, bundle.ExportedAt.IsZero())
+}
+
+func TestExportMetadata_Stdout(t *testing.T) {
+	common.NewMockWorkerServer(t, common.NewServerStub(t).WithDefaultActionsMetadataEndpoint().WithOptionsEndpoint())
+
+	var out bytes.Buffer
+	common.SetCliOut(&out)
+	t.Cleanup(func() { common.SetCliOut(os.Stdout) })
+
+	runCmd := common.CreateCliRunner(t, GetExportMetadataCommand())
+	require.NoError(t, runCmd("worker", "export-metadata"))
+
+	bundle := new(model.MetadataBundle)
+	require.NoError(t, json.Unmarshal(out.Bytes(), bundle))
+	assert.Len(t, bundle.Actions, len(common.LoadSampleActions(t)))
+}
+
+func TestMetadataFlags(t *testing.T) {
+	// The commands loading the actions or the options metadata, directly or through the capabilities checks
+	for _, command := range []components.Command{
+		GetBenchCommand(),
+		GetCopyCommand(),
+		GetDeployCommand(),
+		GetDryRunCommand(),
+		GetExecuteCommand(),
+		GetExportMetadataCommand(),
+		GetInitCommand(),
+		GetListCommand(),
+		GetListEventsCommand(),
+		GetRemoveCommand(),
+		GetRenameCommand(),
+		GetSamplePayloadCommand(),
+		GetShowExecutionHistoryCommand(),
+		GetWaitCommand(),
+	} {
+		var names []string
+		for _, flag := range command.Flags {
+			names = append(names, flag.GetName())
+		}
+		for _, flag := range model.GetMetadataFlags() {
+			assert.Contains(t, names, flag.GetName(), command.Name)
+		}
+	}
+}
+
+func TestActionsFile(t *testing.T) {
+	server, _ := common.NewMockWorkerServer(t, common.NewServerStub(t).WithDefaultActionsMetadataEndpoint().WithOptionsEndpoint())
+
+	tmpDir := t.TempDir()
+	bundleFile := filepath.Join(tmpDir, "metadata.json")
+	listEventFile := filepath.Join(tmpDir, "actions.json")
+
+	var out bytes.Buffer
+	common.SetCliOut(&out)
+	t.Cleanup(func() { common.SetCliOut(os.Stdout) })
+
+	runCmd := common.CreateCliRunner(t, GetExportMetadataCommand(), GetListEventsCommand(), GetInitCommand())
+
+	require.NoError(t, runCmd("worker", "export-metadata", bundleFile))
+	require.NoError(t, runCmd("worker", "list-event", "--"+format.FlagName, "json"))
+	require.NoError(t, os.WriteFile(listEventFile, out.Bytes(), 0o600))
+
+	// From now on, the server cannot be reached
+	server.Close()
+
+	for _, actionsFile := range []string{bundleFile, listEventFile} {
+		t.Run(filepath.Base(actionsFile), func(t *testing.T) {
+			dir, workerName := common.PrepareWorkerDirForTest(t)
+
+			err := runCmd("worker", "init", "--"+model.FlagActionsFile, actionsFile, "BEFORE_DOWNLOAD", workerName)
+			require.NoError(t, err)
+
+			assert.FileExists(t, filepath.Join(dir, "manifest.json"))
+			assert.FileExists(t, filepath.Join(dir, "types.ts"))
+
+			out.Reset()
+			require.NoError(t, runCmd("worker", "list-event", "--"+model.FlagActionsFile, actionsFile, "--"+format.FlagName, "json"))
+
+			var actions common.ActionsMetadata
+			require.NoError(t, json.Unmarshal(out.Bytes(), &actions))
+			assert.Equal(t, common.LoadSampleActions(t), actions)
+		})
+	}
+}
+
+func TestLoadMetadataFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writeFile := func(name string, content string) string {
+		p := filepath.Join(tmpDir, name)
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o600))
+		return p
+	}
+
+	t.Run("options document", func(t *testing.T) {
+		options, err := common.LoadOptionsFile(writeFile("options.json", `{"isHistoryEnabled":true}`))
+		require.NoError(t, err)
+		assert.True(t, options.IsHistoryEnabled)
+	})
+
+	t.Run("options from bundle", func(t *testing.T) {
+		options, err := common.LoadOptionsFile(writeFile("bundle.json", `{"actions":[],"options":{"isHistoryEnabled":true}}`))
+		require.NoError(t, err)
+		assert.True(t, options.IsHistoryEnabled)
+	})
+
+	t.Run("bundle without options", func(t *testing.T) {
+		p := writeFile("no-options.json", `{"actions":[]}`)
+		_, err := common.LoadOptionsFile(p)
+		assert.EqualError(t, err, "invalid options file "+p+": no options found")
+	})
+
+	t.Run("bundle without actions", func(t *testing.T) {
+		p := writeFile("no-actions.json", `{"options":{}}`)
+		_, err := common.LoadActionsFile(p)
+		assert.EqualError(t, err, "invalid actions file "+p+": no actions found")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := common.LoadActionsFile(filepath.Join(tmpDir, "missing.json"))
+		assert.ErrorContains(t, err, "cannot read the actions file")
+	})
+}
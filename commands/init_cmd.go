@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"slices"
 	"strings"
 	"text/template"
 
@@ -25,7 +26,7 @@ func GetInitCommand() components.Command {
 	return components.Command{
 		Name:        "init",
 		Description: "Initialize a worker",
-		AIDescription: `Scaffold a new worker project in the current working directory. Generates manifest.json, worker.ts, package.json, tsconfig.json, types.ts, and (unless --no-test) worker.spec.ts based on the action's metadata fetched from the server.
+		AIDescription: `Scaffold a new worker project in the current working directory. Generates manifest.json, worker.ts, package.json, tsconfig.json, types.ts, payloads/sample.json, and (unless --no-test) worker.spec.ts based on the action's metadata fetched from the server.
 
 When to use:
 - Starting a new worker locally before editing TypeScript and deploying.
@@ -43,19 +44,23 @@ Common patterns:
 
 Gotchas:
 - Files are written to the current directory, not a subdirectory named after the worker.
+- payloads/sample.json is the sample payload of the action, ready for 'jf worker test-run @payloads/sample.json' or 'jf worker test-run --sample'.
 - Without --force, the command aborts if any target file already exists.
 - The action name is case-sensitive and must match exactly (e.g. BEFORE_UPLOAD, not before_upload).
+- The actions metadata are cached per server for an hour (JFROG_WORKER_CLI_METADATA_CACHE_TTL); pass --refresh-metadata to download them again, e.g. after installing an application.
+- On machines that cannot reach the server, pass --actions-file with the output of 'jf worker export-metadata' or 'jf worker list-event --format json'. No server configuration is needed then.
+- With --offline the server is not called and the metadata cached by a previous command are used.
 
 Related: jf worker list-event, jf worker deploy, jf worker test-run`,
 		Aliases: []string{"i"},
-		Flags: []components.Flag{
+		Flags: slices.Concat([]components.Flag{
 			plugins_common.GetServerIdFlag(),
 			model.GetProjectKeyFlag(),
 			model.GetApplicationFlag(),
 			model.GetNoTestFlag(),
 			model.GetTimeoutFlag(),
 			components.NewBoolFlag(model.FlagForce, "Whether or not to overwrite existing files"),
-		},
+		}, model.GetMetadataFlags(), model.GetHTTPClientFlags()),
 		Arguments: []components.Argument{
 			{Name: "action", Description: "The action that will trigger the worker. Use `jf worker list-event` to see the list of available actions."},
 			{Name: "worker-name", Description: "The name of the worker"},
@@ -97,12 +102,20 @@ func (c *initHandler) run() error {
 }
 
 func (c *initHandler) initWorker(targetDir string, action string, workerName string, projectKey string, force bool, skipTests bool) error {
-	server, err := model.GetServerDetails(c.Context)
-	if err != nil {
+	server := &common.Server{Metadata: common.GetMetadataOptions(c.Context)}
+	// With an actions file, the command works without any configured server
+	if server.Metadata.ActionsFile == "" {
+		var err error
+		if server, err = common.GetServerDetails(c.Context); err != nil {
+			return err
+		}
+	}
+
+	if err := common.CheckProjectSupport(c.Context, server, projectKey); err != nil {
 		return err
 	}
 
-	actionsMeta, err := common.FetchActions(c.Context, server.Url, server.AccessToken, projectKey)
+	actionsMeta, err := common.FetchActions(c.Context, server, projectKey)
 	if err != nil {
 		return err
 	}
@@ -143,6 +156,10 @@ func (c *initHandler) initWorker(targetDir string, action string, workerName str
 		return err
 	}
 
+	if err := c.generateSamplePayload(targetDir, actionMeta, force); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -199,6 +216,21 @@ func (c *initHandler) initGenerator(targetDir string, workerName string, project
 	}
 }
 
+func (c *initHandler) generateSamplePayload(targetDir string, actionMeta *model.ActionMetadata, force bool) error {
+	payloadFilePath := path.Join(targetDir, common.SamplePayloadFile)
+
+	if err := c.checkFileBeforeGenerate(payloadFilePath, !force); err != nil {
+		return err
+	}
+
+	payload, err := common.SamplePayload(actionMeta)
+	if err != nil {
+		return err
+	}
+
+	return common.WriteSamplePayload(payloadFilePath, payload)
+}
+
 func (c *initHandler) generateTypesFile(targetDir string, actionMeta *model.ActionMetadata, force bool) error {
 	typesFilePath := path.Join(targetDir, "types.ts")
 
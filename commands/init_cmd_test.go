@@ -4,6 +4,7 @@
 package commands
 
 import (
+	"cmp"
 	"fmt"
 	"os"
 	"path"
@@ -217,6 +218,13 @@ func testGenerateAction(actionName string, withTests bool, runCommand runCommand
 		gotTsconfigJson, err := os.ReadFile(tsconfigJsonPath)
 		require.NoErrorf(t, err, "Cannot get worker tsconfig.json")
 		assert.Equalf(t, wantTsconfig, string(gotTsconfigJson), "Invalid worker tsconfig.json")
+
+		actionMeta, err := common.LoadSampleActions(t).FindAction(actionName)
+		require.NoError(t, err)
+		wantSamplePayload := cmp.Or(actionMeta.SamplePayload, "{}")
+		gotSamplePayload, err := os.ReadFile(path.Join(dir, common.SamplePayloadFile))
+		require.NoErrorf(t, err, "Cannot get the sample payload")
+		assert.JSONEqf(t, wantSamplePayload, string(gotSamplePayload), "Invalid sample payload")
 	}
 }
 
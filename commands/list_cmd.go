@@ -1,11 +1,16 @@
 package commands
 
 import (
+	"cmp"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"path"
 	"slices"
 	"strings"
+	"sync"
 
 	"github.com/jfrog/jfrog-cli-core/v2/common/format"
 	plugins_common "github.com/jfrog/jfrog-cli-core/v2/plugins/common"
@@ -14,49 +19,130 @@ import (
 
 	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
 	"github.com/jfrog/jfrog-cli-platform-services/model"
+	"github.com/jfrog/jfrog-cli-platform-services/workerclient"
+)
+
+const (
+	flagListState       = "state"
+	flagListDebug       = "debug"
+	flagListMatch       = "match"
+	flagListColumns     = "columns"
+	flagListSort        = "sort"
+	flagListAllProjects = "all-projects"
+
+	listStateEnabled  = "enabled"
+	listStateDisabled = "disabled"
+
+	listDefaultColumns = "key,action,description,enabled"
+	// The number of projects queried at the same time with --all-projects
+	listProjectsConcurrency = 4
 )
 
 type getAllResponse struct {
 	Workers []*model.WorkerDetails `json:"workers"`
 }
 
+// workersDocument is the response of the listing as returned by the server, so that the JSON and YAML outputs hold every field, including the ones unknown to the CLI.
+type workersDocument map[string]json.RawMessage
+
+// listedWorker is a worker with the information that the server does not return with it.
+type listedWorker struct {
+	*model.WorkerDetails
+	application string
+	// The worker as returned by the server
+	raw json.RawMessage
+}
+
+type listColumn struct {
+	name   string
+	header string
+	value  func(wk *listedWorker) string
+}
+
+var listColumns = []*listColumn{
+	{name: "key", header: "KEY", value: func(wk *listedWorker) string { return wk.Key }},
+	{name: "action", header: "ACTION", value: func(wk *listedWorker) string { return wk.Action }},
+	{name: "description", header: "DESCRIPTION", value: func(wk *listedWorker) string { return wk.Description }},
+	{name: "enabled", header: "ENABLED", value: func(wk *listedWorker) string { return fmt.Sprint(wk.Enabled) }},
+	{name: "debug", header: "DEBUG", value: func(wk *listedWorker) string { return fmt.Sprint(wk.Debug) }},
+	{name: "projectKey", header: "PROJECT", value: func(wk *listedWorker) string { return wk.ProjectKey }},
+	{name: "application", header: "APPLICATION", value: func(wk *listedWorker) string { return wk.application }},
+	{name: "filterCriteria", header: "FILTER CRITERIA", value: formatFilterCriteria},
+	{name: "secrets", header: "SECRETS", value: formatSecretKeys},
+}
+
+type listOptions struct {
+	action      string
+	projectKey  string
+	allProjects bool
+	state       string
+	debugOnly   bool
+	match       string
+	application string
+	columns     []*listColumn
+	sortColumn  *listColumn
+	sortDesc    bool
+}
+
 func GetListCommand() components.Command {
 	return components.Command{
 		Name:        "list",
 		Description: "List workers. The default output is a CSV format with columns <name>,<action>,<description>,<enabled>.",
-		AIDescription: `List workers deployed on the JFrog Platform, optionally filtered by action type. Default output is CSV (name, action, description, enabled); JSON output includes full worker details.
+		AIDescription: `List workers deployed on the JFrog Platform, optionally filtered by action type, state, debug mode, key pattern, project or application. Default output is CSV (name, action, description, enabled); --format table prints an aligned table with a header; JSON and YAML outputs include full worker details.
 
 When to use:
 - Discovering existing workers before deploying a new one (to avoid name collisions).
-- Auditing which workers are enabled in an environment.
+- Auditing which workers are enabled, or left in debug mode, in an environment.
 - Finding the exact worker key to pass to 'jf worker execute' or 'jf worker undeploy'.
+- Getting an inventory of the workers of every project with --all-projects.
 
 Prerequisites:
 - Configured server (jf c add or jf login) with read permission on the worker registry.
-- For project-scoped workers, pass --project (or list global workers without it).
+- For project-scoped workers, pass --project-key (or list global workers without it).
+- --all-projects needs a token allowed to list the projects of the platform.
 
 Common patterns:
   $ jf worker list
   $ jf worker list BEFORE_UPLOAD
-  $ jf worker list --project my-project
+  $ jf worker list --project-key my-project
+  $ jf worker list --format table --state disabled
+  $ jf worker list --match 'tmp-*' --debug
+  $ jf worker list --application artifactory --columns key,action,projectKey,secrets --sort -key
+  $ jf worker list --all-projects --format table --columns key,action,projectKey,enabled
   $ jf worker list --format json
-  $ jf worker list GENERIC_EVENT --format json
+  $ jf worker list --template '{{.Key}} {{.Action}} {{.Enabled}}'
+  $ jf worker list --query '.workers[].key' --format csv
 
 Gotchas:
-- Without --project, only globally scoped workers are returned; project workers are listed separately per project.
+- Without --project-key, only globally scoped workers are returned; --all-projects adds the workers of every project, queried concurrently.
+- --columns and --sort apply to the csv and table formats; the JSON and YAML outputs hold the response of the server with all its fields, the workers being filtered and sorted.
+- --template is executed for each worker with the Go field names, e.g. '{{.Key}} {{.Enabled}}'; --query filters the JSON document, e.g. '.workers[] | select(.debug == true) | .key', and switches the fields to their JSON names.
+- Available columns: key, action, description, enabled, debug, projectKey, application, filterCriteria, secrets. Prefix the --sort column with '-' to sort in descending order.
+- The CSV output has no header, for compatibility with scripts; the table output has one.
+- --match is a glob on the worker key ('*', '?', '[a-z]'), quote it so that the shell does not expand it.
+- --application and the application column resolve the action of each worker with the actions metadata (cached, see --refresh-metadata).
 - The deprecated --json flag still works but emits a warning; prefer --format json.
 - The 'action' argument is case-sensitive and must match a name returned by 'jf worker list-event'.
 
 Related: jf worker list-event, jf worker execute, jf worker undeploy`,
 		Aliases:          []string{"ls"},
-		SupportedFormats: []format.OutputFormat{format.Json, format.Table},
-		DefaultFormat:    format.Table,
-		Flags: []components.Flag{
+		SupportedFormats: common.OutputFormats,
+		DefaultFormat:    common.FormatCsv,
+		Flags: slices.Concat([]components.Flag{
 			plugins_common.GetServerIdFlag(),
 			model.GetJSONOutputFlag("Deprecated: use --format json instead."),
 			model.GetTimeoutFlag(),
 			model.GetProjectKeyFlag(),
-		},
+			model.GetTemplateFlag(),
+			model.GetQueryFlag(),
+			components.NewBoolFlag(flagListAllProjects, "List the global workers and the workers of every project.", components.WithBoolDefaultValue(false)),
+			components.NewStringFlag(flagListState, "Only show the workers in this state, enabled or disabled.", components.WithStrDefaultValue("")),
+			components.NewBoolFlag(flagListDebug, "Only show the workers with the debug mode on.", components.WithBoolDefaultValue(false)),
+			components.NewStringFlag(flagListMatch, "Only show the workers whose key matches this glob pattern, e.g. 'tmp-*'.", components.WithStrDefaultValue("")),
+			components.NewStringFlag(model.FlagApplication, "Only show the workers of the actions of this application, e.g. artifactory.", components.WithStrDefaultValue("")),
+			components.NewStringFlag(flagListColumns, "The comma-separated columns to show with the csv and table formats, among key, action, description, enabled, debug, projectKey, application, filterCriteria and secrets.", components.WithStrDefaultValue(listDefaultColumns)),
+			components.NewStringFlag(flagListSort, "The column to sort by, prefixed by '-' for a descending order.", components.WithStrDefaultValue("key")),
+		}, model.GetMetadataFlags(), model.GetHTTPClientFlags()),
 		Arguments: []components.Argument{
 			{
 				Name:        "action",
@@ -65,81 +151,366 @@ Related: jf worker list-event, jf worker execute, jf worker undeploy`,
 			},
 		},
 		Action: func(c *components.Context) error {
-			server, err := model.GetServerDetails(c)
+			server, err := common.GetServerDetails(c)
 			if err != nil {
 				return err
 			}
-			return runListCommand(c, server.GetUrl(), server.GetAccessToken())
+			return runListCommand(c, server)
 		},
 	}
 }
 
-func runListCommand(ctx *components.Context, serverURL string, token string) error {
-	params := make(map[string]string)
+func runListCommand(ctx *components.Context, server *common.Server) error {
+	output, err := common.NewOutput(ctx)
+	if err != nil {
+		return err
+	}
+	if ctx.GetBoolFlagValue(model.FlagJSONOutput) {
+		log.Warn("--json is deprecated, use --format json instead.")
+		output.Format = format.Json
+	}
 
-	var action string
+	options, err := getListOptions(ctx)
+	if err != nil {
+		return err
+	}
 
-	if len(ctx.Arguments) > 0 {
-		action = strings.TrimSpace(ctx.Arguments[0])
+	projectKey := options.projectKey
+	if options.allProjects {
+		projectKey = "*"
 	}
-	if action != "" {
-		params["action"] = action
+	if err = common.CheckProjectSupport(ctx, server, projectKey); err != nil {
+		return err
 	}
 
-	outputFormat, err := ctx.GetOutputFormat()
+	var document workersDocument
+	var listed []*listedWorker
+	if options.allProjects {
+		document, listed, err = listWorkersOfAllProjects(ctx, server, options.action)
+	} else {
+		document, listed, err = listWorkers(ctx, server, options.action, options.projectKey)
+	}
 	if err != nil {
 		return err
 	}
-	if ctx.GetBoolFlagValue(model.FlagJSONOutput) {
-		log.Warn("--json is deprecated, use --format json instead.")
-		outputFormat = format.Json
-	}
-
-	var contentHandler func([]byte) error
-	switch outputFormat {
-	case format.Json:
-		contentHandler = common.PrintJSON
-	case format.Table:
-		contentHandler = printWorkerDetailsAsCsv
-	}
-
-	return common.CallWorkerAPI(ctx, common.APICallParams{
-		Method:      http.MethodGet,
-		ServerURL:   serverURL,
-		ServerToken: token,
-		Query:       params,
-		OkStatuses:  []int{http.StatusOK},
-		Path:        []string{"workers"},
-		ProjectKey:  ctx.GetStringFlagValue(model.FlagProjectKey),
-		OnContent:   contentHandler,
+
+	if err = resolveApplications(ctx, server, listed, options); err != nil {
+		return err
+	}
+
+	listed = filterListedWorkers(listed, options)
+	sortListedWorkers(listed, options)
+
+	details := make([]*model.WorkerDetails, len(listed))
+	raws := make([]json.RawMessage, len(listed))
+	for i, wk := range listed {
+		details[i] = wk.WorkerDetails
+		raws[i] = wk.raw
+	}
+
+	// The workers are replaced by the filtered and sorted ones, the other fields of the response are printed as is
+	if document["workers"], err = json.Marshal(raws); err != nil {
+		return err
+	}
+
+	return output.Print(&common.Result{
+		Value: document,
+		Items: details,
+		Table: newListTable(listed, options.columns),
+	})
+}
+
+func getListOptions(ctx *components.Context) (*listOptions, error) {
+	options := &listOptions{
+		projectKey:  ctx.GetStringFlagValue(model.FlagProjectKey),
+		allProjects: ctx.GetBoolFlagValue(flagListAllProjects),
+		state:       strings.ToLower(strings.TrimSpace(ctx.GetStringFlagValue(flagListState))),
+		debugOnly:   ctx.GetBoolFlagValue(flagListDebug),
+		match:       ctx.GetStringFlagValue(flagListMatch),
+		application: strings.TrimSpace(ctx.GetStringFlagValue(model.FlagApplication)),
+	}
+
+	if len(ctx.Arguments) > 0 {
+		options.action = strings.TrimSpace(ctx.Arguments[0])
+	}
+
+	if options.allProjects && options.projectKey != "" {
+		return nil, fmt.Errorf("--%s cannot be combined with --%s", flagListAllProjects, model.FlagProjectKey)
+	}
+
+	if options.state != "" && options.state != listStateEnabled && options.state != listStateDisabled {
+		return nil, fmt.Errorf("invalid --%s '%s', expected %s or %s", flagListState, options.state, listStateEnabled, listStateDisabled)
+	}
+
+	if options.match != "" {
+		if _, err := path.Match(options.match, ""); err != nil {
+			return nil, fmt.Errorf("invalid --%s pattern '%s': %w", flagListMatch, options.match, err)
+		}
+	}
+
+	columns := ctx.GetStringFlagValue(flagListColumns)
+	if strings.TrimSpace(columns) == "" {
+		columns = listDefaultColumns
+	}
+	for _, name := range strings.Split(columns, ",") {
+		column, err := findListColumn(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		options.columns = append(options.columns, column)
+	}
+
+	sortBy := strings.TrimSpace(ctx.GetStringFlagValue(flagListSort))
+	if sortBy == "" {
+		sortBy = "key"
+	}
+	if strings.HasPrefix(sortBy, "-") {
+		options.sortDesc = true
+		sortBy = sortBy[1:]
+	}
+	sortColumn, err := findListColumn(sortBy)
+	if err != nil {
+		return nil, err
+	}
+	options.sortColumn = sortColumn
+
+	return options, nil
+}
+
+func findListColumn(name string) (*listColumn, error) {
+	for _, column := range listColumns {
+		if strings.EqualFold(column.name, name) {
+			return column, nil
+		}
+	}
+
+	names := make([]string, len(listColumns))
+	for i, column := range listColumns {
+		names[i] = column.name
+	}
+	return nil, fmt.Errorf("unknown column '%s', expected one of %s", name, strings.Join(names, ", "))
+}
+
+// listWorkers returns the response of the server and the workers it holds, decoded while keeping their raw content.
+func listWorkers(ctx *components.Context, server *common.Server, action string, projectKey string) (workersDocument, []*listedWorker, error) {
+	res, err := common.CallWorkerClient(ctx, server, func(c context.Context, client *workerclient.Client) (*workerclient.Response, error) {
+		request := &workerclient.Request{
+			Method:     http.MethodGet,
+			Path:       []string{"workers"},
+			ProjectKey: projectKey,
+			OkStatuses: []int{http.StatusOK},
+		}
+		if action != "" {
+			request.Query = map[string]string{"action": action}
+		}
+		return client.Do(c, request)
 	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	document := workersDocument{}
+	var raws []json.RawMessage
+	if len(res.Body) > 0 {
+		if err = json.Unmarshal(res.Body, &document); err != nil {
+			return nil, nil, fmt.Errorf("cannot decode the workers: %w", err)
+		}
+		if content, hasWorkers := document["workers"]; hasWorkers {
+			if err = json.Unmarshal(content, &raws); err != nil {
+				return nil, nil, fmt.Errorf("cannot decode the workers: %w", err)
+			}
+		}
+	}
+
+	listed := make([]*listedWorker, len(raws))
+	for i, raw := range raws {
+		details := new(model.WorkerDetails)
+		if err = json.Unmarshal(raw, details); err != nil {
+			return nil, nil, fmt.Errorf("cannot decode the workers: %w", err)
+		}
+		listed[i] = &listedWorker{WorkerDetails: details, raw: raw}
+	}
+
+	return document, listed, nil
 }
 
-func printWorkerDetailsAsCsv(responseBytes []byte) error {
-	var err error
-	allWorkers := getAllResponse{}
+// listWorkersOfAllProjects lists the global workers and the workers of each project, a few projects at a time.
+// The returned document is the response of the global listing.
+func listWorkersOfAllProjects(ctx *components.Context, server *common.Server, action string) (workersDocument, []*listedWorker, error) {
+	projects, err := common.CallWorkerClient(ctx, server, func(c context.Context, client *workerclient.Client) ([]*model.Project, error) {
+		return client.ListProjects(c)
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot list the projects: %w", err)
+	}
+
+	// The global workers are listed without project key
+	projectKeys := []string{""}
+	for _, project := range projects {
+		projectKeys = append(projectKeys, project.ProjectKey)
+	}
+
+	documents := make([]workersDocument, len(projectKeys))
+	results := make([][]*listedWorker, len(projectKeys))
+	errs := make([]error, len(projectKeys))
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, listProjectsConcurrency)
+
+	for i, projectKey := range projectKeys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			document, workers, listErr := listWorkers(ctx, server, action, projectKey)
+			if listErr != nil {
+				if projectKey != "" {
+					listErr = fmt.Errorf("cannot list the workers of project '%s': %w", projectKey, listErr)
+				}
+				errs[i] = listErr
+				return
+			}
+
+			for _, wk := range workers {
+				if wk.ProjectKey == "" && projectKey != "" {
+					wk.ProjectKey = projectKey
+					wk.raw = withProjectKey(wk.raw, projectKey)
+				}
+			}
+			documents[i], results[i] = document, workers
+		}()
+	}
+
+	wg.Wait()
+
+	if err = errors.Join(errs...); err != nil {
+		return nil, nil, err
+	}
+
+	var workers []*listedWorker
+	for _, result := range results {
+		workers = append(workers, result...)
+	}
+
+	return documents[0], workers, nil
+}
 
-	err = json.Unmarshal(responseBytes, &allWorkers)
+// withProjectKey adds the project key to a worker that the server returned without it, the worker is kept as is if it cannot be decoded.
+func withProjectKey(raw json.RawMessage, projectKey string) json.RawMessage {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return raw
+	}
+	fields["projectKey"], _ = json.Marshal(projectKey)
+	content, err := json.Marshal(fields)
 	if err != nil {
+		return raw
+	}
+	return content
+}
+
+// resolveApplications resolves the applications of the workers when they are used, to avoid fetching the actions otherwise.
+// The actions are fetched for the project of each worker, as a project may have its own actions.
+func resolveApplications(ctx *components.Context, server *common.Server, listed []*listedWorker, options *listOptions) error {
+	needsApplication := options.application != "" || options.sortColumn.name == "application" ||
+		slices.ContainsFunc(options.columns, func(column *listColumn) bool { return column.name == "application" })
+	if !needsApplication || len(listed) == 0 {
 		return nil
 	}
 
-	writer := common.NewCsvWriter()
+	// The applications of the actions, per project
+	applications := map[string]map[string]string{}
+	for _, wk := range listed {
+		projectApplications, fetched := applications[wk.ProjectKey]
+		if !fetched {
+			actions, err := common.FetchActions(ctx, server, wk.ProjectKey)
+			if err != nil {
+				return err
+			}
+			projectApplications = map[string]string{}
+			for _, action := range actions {
+				if _, exists := projectApplications[action.Action.Name]; !exists {
+					projectApplications[action.Action.Name] = action.Action.Application
+				}
+			}
+			applications[wk.ProjectKey] = projectApplications
+		}
+		wk.application = projectApplications[wk.Action]
+	}
+
+	return nil
+}
 
-	slices.SortFunc(allWorkers.Workers, func(a, b *model.WorkerDetails) int {
-		return strings.Compare(a.Key, b.Key)
+func filterListedWorkers(workers []*listedWorker, options *listOptions) []*listedWorker {
+	return slices.DeleteFunc(workers, func(wk *listedWorker) bool {
+		if options.state == listStateEnabled && !wk.Enabled {
+			return true
+		}
+		if options.state == listStateDisabled && wk.Enabled {
+			return true
+		}
+		if options.debugOnly && !wk.Debug {
+			return true
+		}
+		if options.match != "" {
+			// The pattern was validated beforehand
+			if matched, _ := path.Match(options.match, wk.Key); !matched {
+				return true
+			}
+		}
+		return options.application != "" && !strings.EqualFold(options.application, wk.application)
 	})
+}
 
-	for _, wk := range allWorkers.Workers {
-		err = writer.Write([]string{
-			wk.Key, wk.Action, wk.Description, fmt.Sprint(wk.Enabled),
-		})
-		if err != nil {
-			return err
+// sortListedWorkers sorts by the selected column, then by key and project for a stable output.
+func sortListedWorkers(workers []*listedWorker, options *listOptions) {
+	slices.SortStableFunc(workers, func(a, b *listedWorker) int {
+		order := strings.Compare(options.sortColumn.value(a), options.sortColumn.value(b))
+		if options.sortDesc {
+			order = -order
 		}
+		return cmp.Or(order, strings.Compare(a.Key, b.Key), strings.Compare(a.ProjectKey, b.ProjectKey))
+	})
+}
+
+// newListTable shows the selected columns, the csv output has no header for compatibility with the scripts.
+func newListTable(workers []*listedWorker, columns []*listColumn) *common.Table {
+	table := &common.Table{NoCsvHeaders: true}
+	for _, column := range columns {
+		table.Headers = append(table.Headers, column.header)
 	}
+	for _, wk := range workers {
+		table.Rows = append(table.Rows, listRow(wk, columns))
+	}
+	return table
+}
 
-	writer.Flush()
+func listRow(wk *listedWorker, columns []*listColumn) []string {
+	row := make([]string, len(columns))
+	for i, column := range columns {
+		row[i] = column.value(wk)
+	}
+	return row
+}
+
+func formatFilterCriteria(wk *listedWorker) string {
+	if wk.FilterCriteria == nil {
+		return ""
+	}
+	content, err := json.Marshal(wk.FilterCriteria)
+	if err != nil {
+		return ""
+	}
+	return string(content)
+}
 
-	return writer.Error()
+func formatSecretKeys(wk *listedWorker) string {
+	keys := make([]string, 0, len(wk.Secrets))
+	for _, secret := range wk.Secrets {
+		keys = append(keys, secret.Key)
+	}
+	slices.Sort(keys)
+	return strings.Join(keys, " ")
 }
@@ -6,6 +6,8 @@ package commands
 import (
 	"bytes"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"sort"
 	"strings"
@@ -13,6 +15,7 @@ import (
 	"time"
 
 	"github.com/jfrog/jfrog-cli-core/v2/common/format"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
 
 	"github.com/stretchr/testify/assert"
@@ -220,3 +223,238 @@ func sortWorkers(workers []*model.WorkerDetails) {
 		return workers[i].Key < workers[j].Key
 	})
 }
+
+func TestWorkerList_FiltersAndColumns(t *testing.T) {
+	workers := []*model.WorkerDetails{
+		{Key: "tmp-upload", Action: "BEFORE_UPLOAD", Description: "upload", Enabled: true, Debug: true,
+			FilterCriteria: &model.FilterCriteria{ArtifactFilterCriteria: &model.ArtifactFilterCriteria{RepoKeys: []string{"repo-1"}}},
+			Secrets:        []*model.Secret{{Key: "sec-2"}, {Key: "sec-1"}}},
+		{Key: "tmp-download", Action: "BEFORE_DOWNLOAD", Description: "download", Enabled: false},
+		{Key: "prod-event", Action: "GENERIC_EVENT", Description: "event", Enabled: true},
+	}
+
+	tests := []struct {
+		name        string
+		commandArgs []string
+		wantOutput  string
+		wantErr     string
+	}{
+		{
+			name:       "default",
+			wantOutput: "prod-event,GENERIC_EVENT,event,true\ntmp-download,BEFORE_DOWNLOAD,download,false\ntmp-upload,BEFORE_UPLOAD,upload,true\n",
+		},
+		{
+			name:        "enabled",
+			commandArgs: []string{"--" + flagListState, "enabled", "--" + flagListColumns, "key"},
+			wantOutput:  "prod-event\ntmp-upload\n",
+		},
+		{
+			name:        "disabled",
+			commandArgs: []string{"--" + flagListState, "disabled", "--" + flagListColumns, "key"},
+			wantOutput:  "tmp-download\n",
+		},
+		{
+			name:        "debug",
+			commandArgs: []string{"--" + flagListDebug, "--" + flagListColumns, "key,debug"},
+			wantOutput:  "tmp-upload,true\n",
+		},
+		{
+			name:        "match and sort descending",
+			commandArgs: []string{"--" + flagListMatch, "tmp-*", "--" + flagListSort, "-key", "--" + flagListColumns, "key"},
+			wantOutput:  "tmp-upload\ntmp-download\n",
+		},
+		{
+			name:        "sort by action",
+			commandArgs: []string{"--" + flagListSort, "action", "--" + flagListColumns, "action,key"},
+			wantOutput:  "BEFORE_DOWNLOAD,tmp-download\nBEFORE_UPLOAD,tmp-upload\nGENERIC_EVENT,prod-event\n",
+		},
+		{
+			name:        "application",
+			commandArgs: []string{"--" + model.FlagApplication, "worker", "--" + flagListColumns, "key,application"},
+			wantOutput:  "prod-event,worker\n",
+		},
+		{
+			name:        "filter criteria and secrets",
+			commandArgs: []string{"--" + flagListMatch, "tmp-upload", "--" + flagListColumns, "filterCriteria,secrets"},
+			wantOutput:  "\"{\"\"artifactFilterCriteria\"\":{\"\"repoKeys\"\":[\"\"repo-1\"\"]}}\",sec-1 sec-2\n",
+		},
+		{
+			name:        "table",
+			commandArgs: []string{"--" + format.FlagName, "table", "--" + flagListColumns, "key,action,enabled"},
+			wantOutput: "KEY           ACTION           ENABLED\n" +
+				"prod-event    GENERIC_EVENT    true\n" +
+				"tmp-download  BEFORE_DOWNLOAD  false\n" +
+				"tmp-upload    BEFORE_UPLOAD    true\n",
+		},
+		{
+			name:        "unknown column",
+			commandArgs: []string{"--" + flagListColumns, "key,owner"},
+			wantErr:     "unknown column 'owner', expected one of key, action, description, enabled, debug, projectKey, application, filterCriteria, secrets",
+		},
+		{
+			name:        "invalid state",
+			commandArgs: []string{"--" + flagListState, "paused"},
+			wantErr:     "invalid --state 'paused', expected enabled or disabled",
+		},
+		{
+			name:        "all projects with a project key",
+			commandArgs: []string{"--" + flagListAllProjects, "--" + model.FlagProjectKey, "proj-1"},
+			wantErr:     "--all-projects cannot be combined with --project-key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serverStub := common.NewServerStub(t).
+				WithGetAllEndpoint().
+				WithWorkers(workers...).
+				WithDefaultActionsMetadataEndpoint()
+			common.NewMockWorkerServer(t, serverStub)
+
+			var out bytes.Buffer
+			common.SetCliOut(&out)
+			t.Cleanup(func() { common.SetCliOut(os.Stdout) })
+
+			runCmd := common.CreateCliRunner(t, GetListCommand())
+			err := runCmd(append([]string{"worker", "list"}, tt.commandArgs...)...)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOutput, out.String())
+		})
+	}
+}
+
+func TestWorkerList_AllProjects(t *testing.T) {
+	serverStub := common.NewServerStub(t).
+		WithStatefulWorkers().
+		WithGetAllEndpoint().
+		WithProjectsEndpoint("proj-1", "proj-2", "proj-3", "proj-4", "proj-5").
+		WithWorkers(
+			&model.WorkerDetails{Key: "wk-global", Action: "GENERIC_EVENT"},
+			&model.WorkerDetails{Key: "wk-1", Action: "GENERIC_EVENT", ProjectKey: "proj-1"},
+			&model.WorkerDetails{Key: "wk-2", Action: "BEFORE_UPLOAD", ProjectKey: "proj-2"},
+			&model.WorkerDetails{Key: "wk-5", Action: "GENERIC_EVENT", ProjectKey: "proj-5"},
+		)
+	common.NewMockWorkerServer(t, serverStub)
+
+	var out bytes.Buffer
+	common.SetCliOut(&out)
+	t.Cleanup(func() { common.SetCliOut(os.Stdout) })
+
+	runCmd := common.CreateCliRunner(t, GetListCommand())
+	require.NoError(t, runCmd("worker", "list", "--"+flagListAllProjects, "--"+flagListColumns, "projectKey,key,action", "GENERIC_EVENT"))
+
+	assert.Equal(t, "proj-1,wk-1,GENERIC_EVENT\nproj-5,wk-5,GENERIC_EVENT\n,wk-global,GENERIC_EVENT\n", out.String())
+}
+
+func TestWorkerList_OutputOptions(t *testing.T) {
+	tests := []struct {
+		name        string
+		commandArgs []string
+		wantOutput  string
+		wantErr     string
+	}{
+		{
+			name:        "template",
+			commandArgs: []string{"--" + model.FlagTemplate, "{{.Key}} {{.Enabled}}"},
+			wantOutput:  "wk-0 true\nwk-1 false\n",
+		},
+		{
+			name:        "query",
+			commandArgs: []string{"--" + model.FlagQuery, "[.workers[] | select(.enabled == false) | .key]", "--" + format.FlagName, "json"},
+			wantOutput:  "[\n  \"wk-1\"\n]",
+		},
+		{
+			name:        "query as csv",
+			commandArgs: []string{"--" + model.FlagQuery, ".workers[].key", "--" + format.FlagName, "csv"},
+			wantOutput:  "wk-0\nwk-1\n",
+		},
+		{
+			name:        "yaml",
+			commandArgs: []string{"--" + model.FlagQuery, ".workers[0] | keys", "--" + format.FlagName, "yaml"},
+			wantOutput:  "- action\n- debug\n- description\n- enabled\n- key\n- projectKey\n- secrets\n- sourceCode\n",
+		},
+		{
+			name:        "template with another format",
+			commandArgs: []string{"--" + model.FlagTemplate, "{{.Key}}", "--" + format.FlagName, "json"},
+			wantErr:     "--template cannot be used with --format json",
+		},
+		{
+			name:        "template format without template",
+			commandArgs: []string{"--" + format.FlagName, "template"},
+			wantErr:     "--format template requires --template",
+		},
+		{
+			name:        "invalid query",
+			commandArgs: []string{"--" + model.FlagQuery, ".workers[?]"},
+			wantErr:     "invalid query '.workers[?]': unexpected token \"?\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serverStub := common.NewServerStub(t).WithGetAllEndpoint().WithWorkers(testWorkers...)
+			common.NewMockWorkerServer(t, serverStub)
+
+			var out bytes.Buffer
+			common.SetCliOut(&out)
+			t.Cleanup(func() { common.SetCliOut(os.Stdout) })
+
+			runCmd := common.CreateCliRunner(t, GetListCommand())
+			err := runCmd(append([]string{"worker", "list"}, tt.commandArgs...)...)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOutput, out.String())
+		})
+	}
+}
+
+func TestWorkerList_KeepsServerFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("Content-Type", "application/json")
+		switch req.URL.Path {
+		case "/worker/api/v1/workers":
+			assert.Equal(t, "proj-1", req.URL.Query().Get("projectKey"))
+			_, _ = res.Write([]byte(`{"workers":[` +
+				`{"key":"wk-b","action":"PROJECT_EVENT","enabled":true,"projectKey":"proj-1","createdBy":"admin"},` +
+				`{"key":"wk-a","action":"PROJECT_EVENT","enabled":false,"projectKey":"proj-1","createdBy":"user"}` +
+				`],"total":2}`))
+		case "/worker/api/v2/actions":
+			// The project actions are only returned with the project key
+			if req.URL.Query().Get("projectKey") != "proj-1" {
+				_, _ = res.Write([]byte(`[]`))
+				return
+			}
+			_, _ = res.Write([]byte(`[{"action":{"application":"my-app","name":"PROJECT_EVENT"}}]`))
+		default:
+			res.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	common.TestSetEnv(t, model.EnvKeyServerURL, server.URL)
+	common.TestSetEnv(t, model.EnvKeyAccessToken, "a-token")
+	common.TestSetEnv(t, coreutils.HomeDir, t.TempDir())
+
+	var out bytes.Buffer
+	common.SetCliOut(&out)
+	t.Cleanup(func() { common.SetCliOut(os.Stdout) })
+
+	runCmd := common.CreateCliRunner(t, GetListCommand())
+
+	require.NoError(t, runCmd("worker", "list", "--"+model.FlagProjectKey, "proj-1", "--"+format.FlagName, "json"))
+	assert.JSONEq(t, `{"workers":[`+
+		`{"key":"wk-a","action":"PROJECT_EVENT","enabled":false,"projectKey":"proj-1","createdBy":"user"},`+
+		`{"key":"wk-b","action":"PROJECT_EVENT","enabled":true,"projectKey":"proj-1","createdBy":"admin"}`+
+		`],"total":2}`, out.String())
+
+	out.Reset()
+	require.NoError(t, runCmd("worker", "list", "--"+model.FlagProjectKey, "proj-1", "--"+model.FlagApplication, "my-app", "--"+model.FlagQuery, ".workers[].createdBy", "--"+format.FlagName, "csv"))
+	assert.Equal(t, "user\nadmin\n", out.String())
+}
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"slices"
 	"strings"
 
 	"github.com/jfrog/jfrog-cli-core/v2/common/format"
@@ -29,68 +30,67 @@ Prerequisites:
 Common patterns:
   $ jf worker list-event                       # comma-separated names (legacy output)
   $ jf worker list-event --format table        # NAME,APPLICATION,DESCRIPTION CSV
+  $ jf worker list-event --format aligned      # the same columns as an aligned table
   $ jf worker list-event --format json         # full action metadata, including TypeScript types
-  $ jf worker list-event --project my-project --format table
+  $ jf worker list-event --project my-project --format aligned
+  $ jf worker list-event --query '.[] | select(.action.application == "artifactory") | .action.name' --format csv
 
 Gotchas:
-- Without --format, the output is a plain comma-separated list (kept for backwards compatibility); pass --format table or --format json for structured output.
+- Without --format, the output is a plain comma-separated list (kept for backwards compatibility); pass --format table, aligned, csv, json or yaml for structured output.
+- For compatibility with scripts, --format table prints CSV with a header, like --format csv; --format aligned prints an aligned table.
+- --template gets each action metadata with the Go field names, e.g. '{{.Action.Name}}: {{.Description}}'.
 - The set of actions depends on the server's installed applications and on the --project scope.
+- The actions metadata are cached per server for an hour (JFROG_WORKER_CLI_METADATA_CACHE_TTL); pass --refresh-metadata to download them again, e.g. after installing an application.
+- On machines that cannot reach the server, pass --actions-file with the output of 'jf worker export-metadata' or 'jf worker list-event --format json'.
+- With --offline the server is not called and the metadata cached by a previous command are used.
 
 Related: jf worker init, jf worker list`,
 		Aliases:          []string{"le"},
-		SupportedFormats: []format.OutputFormat{format.Json, format.Table},
+		SupportedFormats: append(slices.Clone(common.OutputFormats), common.FormatAligned),
 		DefaultFormat:    format.None,
-		Flags: []components.Flag{
+		Flags: slices.Concat([]components.Flag{
 			plugins_common.GetServerIdFlag(),
 			model.GetTimeoutFlag(),
 			model.GetProjectKeyFlag(),
-		},
+			model.GetTemplateFlag(),
+			model.GetQueryFlag(),
+		}, model.GetMetadataFlags(), model.GetHTTPClientFlags()),
 		Action: func(c *components.Context) error {
-			server, err := model.GetServerDetails(c)
-			if err != nil {
-				return err
+			server := &common.Server{Metadata: common.GetMetadataOptions(c)}
+			if server.Metadata.ActionsFile == "" {
+				var err error
+				if server, err = common.GetServerDetails(c); err != nil {
+					return err
+				}
 			}
 
 			projectKey := c.GetStringFlagValue(model.FlagProjectKey)
 
-			outputFormat, err := c.GetOutputFormat()
+			output, err := common.NewOutput(c)
 			if err != nil {
 				return err
 			}
 
-			actionsMeta, err := common.FetchActions(c, server.Url, server.AccessToken, projectKey)
+			if err = common.CheckProjectSupport(c, server, projectKey); err != nil {
+				return err
+			}
+
+			actionsMeta, err := common.FetchActions(c, server, projectKey)
 			if err != nil {
 				return err
 			}
 
-			if outputFormat == format.None {
+			if output.Format == format.None {
 				// Old behavior: no --format flag
 				return common.Print("%s", strings.Join(actionsMeta.ActionsNames(), ", "))
 			}
-			switch outputFormat {
-			case format.Json:
-				return common.PrintJSONValue(actionsMeta)
-			default:
-				return printListEventTable(actionsMeta)
+
+			table := &common.Table{Headers: []string{"NAME", "APPLICATION", "DESCRIPTION"}, CsvAsTable: true}
+			for _, action := range actionsMeta {
+				table.Rows = append(table.Rows, []string{action.Action.Name, action.Action.Application, action.Description})
 			}
-		},
-	}
-}
 
-func printListEventTable(actionsMeta common.ActionsMetadata) error {
-	writer := common.NewCsvWriter()
-	if err := writer.Write([]string{"NAME", "APPLICATION", "DESCRIPTION"}); err != nil {
-		return err
-	}
-	for _, action := range actionsMeta {
-		if err := writer.Write([]string{
-			action.Action.Name,
-			action.Action.Application,
-			action.Description,
-		}); err != nil {
-			return err
-		}
+			return output.Print(&common.Result{Value: actionsMeta, Table: table})
+		},
 	}
-	writer.Flush()
-	return writer.Error()
 }
@@ -126,3 +126,62 @@ func TestWorkerListEvent_FormatUnsupported(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "only the following output formats are supported")
 }
+
+func TestWorkerListEvent_Offline(t *testing.T) {
+	server, _ := common.NewMockWorkerServer(t, common.NewServerStub(t).WithDefaultActionsMetadataEndpoint())
+
+	runCmd := common.CreateCliRunner(t, GetListEventsCommand())
+
+	err := runCmd("worker", "list-event", "--"+model.FlagOffline)
+	require.EqualError(t, err, "no cached actions metadata for "+server.BaseUrl()+", run the command once without --offline")
+
+	require.NoError(t, runCmd("worker", "list-event"))
+
+	server.Close()
+
+	var out bytes.Buffer
+	common.SetCliOut(&out)
+	t.Cleanup(func() { common.SetCliOut(os.Stdout) })
+
+	require.NoError(t, runCmd("worker", "list-event", "--"+model.FlagOffline))
+	assert.Equal(t, strings.Join(common.LoadSampleActionEvents(t), ", "), strings.TrimSpace(out.String()))
+}
+
+func TestWorkerListEvent_FormatAligned(t *testing.T) {
+	actions := common.ActionsMetadata{
+		{Action: model.Action{Name: "BEFORE_DOWNLOAD", Application: "artifactory"}, Description: "Before a download"},
+		{Action: model.Action{Name: "GENERIC_EVENT", Application: "worker"}, Description: "Called on demand"},
+	}
+
+	tests := []struct {
+		format     string
+		wantOutput string
+	}{
+		{
+			format:     "table",
+			wantOutput: "NAME,APPLICATION,DESCRIPTION\nBEFORE_DOWNLOAD,artifactory,Before a download\nGENERIC_EVENT,worker,Called on demand\n",
+		},
+		{
+			format:     "csv",
+			wantOutput: "NAME,APPLICATION,DESCRIPTION\nBEFORE_DOWNLOAD,artifactory,Before a download\nGENERIC_EVENT,worker,Called on demand\n",
+		},
+		{
+			format:     "aligned",
+			wantOutput: "NAME             APPLICATION  DESCRIPTION\nBEFORE_DOWNLOAD  artifactory  Before a download\nGENERIC_EVENT    worker       Called on demand\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			common.NewMockWorkerServer(t, common.NewServerStub(t).WithActionsMetadataEndpoint(actions))
+
+			var out bytes.Buffer
+			common.SetCliOut(&out)
+			t.Cleanup(func() { common.SetCliOut(os.Stdout) })
+
+			runCmd := common.CreateCliRunner(t, GetListEventsCommand())
+			require.NoError(t, runCmd("worker", "list-event", "--"+format.FlagName, tt.format))
+			assert.Equal(t, tt.wantOutput, out.String())
+		})
+	}
+}
@@ -1,11 +1,20 @@
 package commands
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"os"
+	"path"
+	"path/filepath"
 	"slices"
+	"strings"
+	"time"
 
 	"github.com/jfrog/jfrog-cli-core/v2/common/format"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
 
 	plugins_common "github.com/jfrog/jfrog-cli-core/v2/plugins/common"
@@ -13,85 +22,383 @@ import (
 	"github.com/jfrog/jfrog-client-go/utils/log"
 
 	"github.com/jfrog/jfrog-cli-platform-services/model"
+	"github.com/jfrog/jfrog-cli-platform-services/workerclient"
 )
 
+const (
+	flagRemoveMatch     = "match"
+	flagRemoveAction    = "action"
+	flagRemoveYes       = "yes"
+	flagRemoveDryRun    = "dry-run"
+	flagRemoveBackupDir = "backup-dir"
+	removeBackupDir     = "worker-cli/backups"
+)
+
+type removeCommandHandler struct {
+	ctx        *components.Context
+	server     *common.Server
+	projectKey string
+	// Whether the workers were selected with --match or --action, rather than by key
+	bulk bool
+}
+
+// removeTarget is a worker to remove, with its details when the server returned them.
+type removeTarget struct {
+	key     string
+	details *model.WorkerDetails
+}
+
+type removeResult struct {
+	Key        string `json:"key"`
+	ProjectKey string `json:"projectKey,omitempty"`
+	Action     string `json:"action,omitempty"`
+	Status     string `json:"status"`
+	BackupFile string `json:"backupFile,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
 func GetRemoveCommand() components.Command {
 	return components.Command{
 		Name:        "undeploy",
-		Description: "Undeploy a worker",
-		AIDescription: `Delete a deployed worker from the JFrog Platform. The worker stops handling events immediately; its source, secrets, and schedule are removed server-side. Local files (manifest.json, worker.ts) are not touched.
+		Description: "Undeploy one or several workers",
+		AIDescription: `Delete deployed workers from the JFrog Platform. The workers stop handling events immediately; their source, secrets, and schedule are removed server-side. Local files (manifest.json, worker.ts) are not touched. The details of each worker are saved to a local backup file before it is deleted.
 
 When to use:
 - Decommissioning a worker that is no longer needed.
-- Cleaning up test workers from a non-production environment.
+- Cleaning up test workers from a non-production environment, e.g. every worker whose key starts with 'tmp-'.
 - Removing a misconfigured worker before redeploying.
 
 Prerequisites:
-- Configured server (jf c add or jf login) with delete permission on the worker.
-- If the worker is namespaced under a project, run from a directory whose manifest.json declares the project, or pass the worker key directly.
+- Configured server (jf c add or jf login) with delete permission on the workers.
+- For project-scoped workers, pass --project-key, or run from a directory whose manifest.json declares the project.
 
 Common patterns:
   $ jf worker undeploy my-worker
   $ jf worker undeploy           # worker name taken from manifest.json
+  $ jf worker undeploy wk-1 wk-2 wk-3 --project-key my-project
+  $ jf worker undeploy --match 'tmp-*' --dry-run
+  $ jf worker undeploy --match 'tmp-*' --action BEFORE_UPLOAD --yes
   $ jf worker undeploy my-worker --format json
 
 Gotchas:
-- This operation is irreversible from the server; redeploy with 'jf worker deploy' to restore.
+- This operation is irreversible from the server; the backup files (under the JFrog CLI home directory, or --backup-dir) keep the details and source code of the deleted workers.
+- A confirmation is asked in a terminal; without a terminal, removing several workers requires --yes.
+- --match is a glob on the worker key ('*', '?', '[a-z]'), quote it so that the shell does not expand it.
+- --dry-run lists the workers that would be removed, without asking for a confirmation nor removing anything.
 - Execution history is retained server-side and remains visible via 'jf worker execution-history' for a configured retention period.
-- The command exits cleanly even if the worker did not exist when run with --format json (the response body is empty/204).
+- With --format json or yaml a single worker prints the status of the deletion (204), several workers print one result per worker; --template '{{.Key}} {{.Status}}' prints a line per worker.
 
 Related: jf worker deploy, jf worker list`,
 		Aliases:          []string{"rm"},
-		SupportedFormats: []format.OutputFormat{format.Json},
-		Flags: []components.Flag{
+		SupportedFormats: common.DocumentFormats,
+		Flags: slices.Concat([]components.Flag{
 			plugins_common.GetServerIdFlag(),
 			model.GetTimeoutFlag(),
-		},
+			model.GetProjectKeyFlag(),
+			model.GetTemplateFlag(),
+			model.GetQueryFlag(),
+			components.NewStringFlag(flagRemoveMatch, "Remove the workers whose key matches this glob pattern, e.g. 'tmp-*'.", components.WithStrDefaultValue("")),
+			components.NewStringFlag(flagRemoveAction, "Only remove the workers bound to this action, e.g. BEFORE_UPLOAD.", components.WithStrDefaultValue("")),
+			components.NewBoolFlag(flagRemoveYes, "Do not ask for a confirmation.", components.WithBoolDefaultValue(false)),
+			components.NewBoolFlag(flagRemoveDryRun, "List the workers that would be removed, without removing them.", components.WithBoolDefaultValue(false)),
+			components.NewStringFlag(flagRemoveBackupDir, "The directory where the details of the removed workers are saved. Defaults to the worker-cli/backups directory of the JFrog CLI home.", components.WithStrDefaultValue("")),
+		}, model.GetMetadataFlags(), model.GetHTTPClientFlags()),
 		Arguments: []components.Argument{
-			model.GetWorkerKeyArgument(),
+			{
+				Name:        "worker-key",
+				Optional:    true,
+				Description: "The keys of the workers to remove. If not provided, and neither --match nor --action is used, the key is read from the `manifest.json` in the current directory.",
+			},
 		},
 		Action: runRemoveCommand,
 	}
 }
 
 func runRemoveCommand(c *components.Context) error {
-	workerKey, _, err := common.ExtractProjectAndKeyFromCommandContext(c, c.Arguments, 0, false)
+	output, err := common.NewOutput(c)
 	if err != nil {
 		return err
 	}
 
-	server, err := model.GetServerDetails(c)
-	if err != nil {
-		return err
+	match := c.GetStringFlagValue(flagRemoveMatch)
+	action := c.GetStringFlagValue(flagRemoveAction)
+	bulk := match != "" || action != ""
+
+	if bulk && len(c.Arguments) > 0 {
+		return fmt.Errorf("worker keys cannot be combined with --%s or --%s", flagRemoveMatch, flagRemoveAction)
+	}
+	if match != "" {
+		if _, err := path.Match(match, ""); err != nil {
+			return fmt.Errorf("invalid --%s pattern '%s': %w", flagRemoveMatch, match, err)
+		}
 	}
 
-	var responseStatus int
-	var contentHandler common.APIContentHandler
-	if slices.Contains(c.FlagsUsed, format.FlagName) {
-		if _, fmtErr := c.GetOutputFormat(); fmtErr != nil {
-			return fmtErr
+	keys := slices.Clone(c.Arguments)
+	projectKey := c.GetStringFlagValue(model.FlagProjectKey)
+	if !bulk {
+		workerKey, manifestProjectKey, err := common.ExtractProjectAndKeyFromCommandContext(c, c.Arguments, 0, false)
+		if err != nil {
+			return err
 		}
-		contentHandler = func(body []byte) error {
-			return common.PrintJSONOrStatus(responseStatus, body)
+		if len(keys) == 0 {
+			keys = []string{workerKey}
 		}
+		projectKey = manifestProjectKey
 	}
 
-	log.Info(fmt.Sprintf("Removing worker '%s' ...", workerKey))
+	server, err := common.GetServerDetails(c)
+	if err != nil {
+		return err
+	}
 
-	err = common.CallWorkerAPI(c, common.APICallParams{
-		Method:        http.MethodDelete,
-		ServerURL:     server.GetUrl(),
-		ServerToken:   server.GetAccessToken(),
-		OkStatuses:    []int{http.StatusNoContent},
-		Path:          []string{"workers", workerKey},
-		OnContent:     contentHandler,
-		CaptureStatus: &responseStatus,
-	})
+	// The timeout is checked before the workers are fetched, to report an invalid value as is
+	if _, err = model.GetTimeoutParameter(c); err != nil {
+		return err
+	}
+
+	if err = common.CheckProjectSupport(c, server, projectKey); err != nil {
+		return err
+	}
+
+	h := &removeCommandHandler{
+		ctx:        c,
+		server:     server,
+		projectKey: projectKey,
+		bulk:       bulk,
+	}
+
+	var targets []*removeTarget
+	if bulk {
+		targets, err = h.findMatchingWorkers(match, action)
+	} else {
+		targets, err = h.fetchWorkers(keys)
+	}
 	if err != nil {
 		return err
 	}
 
-	log.Info(fmt.Sprintf("Worker '%s' removed", workerKey))
+	if len(targets) == 0 {
+		log.Info("No worker to remove")
+		return output.Print(&common.Result{Value: []*removeResult{}})
+	}
+
+	if c.GetBoolFlagValue(flagRemoveDryRun) {
+		return h.printDryRun(targets, output)
+	}
+
+	if confirmed, err := h.confirm(targets); err != nil || !confirmed {
+		return err
+	}
+
+	results, err := h.remove(targets)
+
+	var printErr error
+	if h.bulk || len(targets) > 1 {
+		printErr = output.Print(&common.Result{Value: results})
+	} else if err == nil {
+		printErr = output.Print(&common.Result{Value: common.StatusContent{StatusCode: http.StatusNoContent}})
+	}
+	if printErr != nil {
+		return printErr
+	}
+
+	return err
+}
+
+func (h *removeCommandHandler) findMatchingWorkers(match string, action string) ([]*removeTarget, error) {
+	workers, err := common.CallWorkerClient(h.ctx, h.server, func(ctx context.Context, client *workerclient.Client) ([]*model.WorkerDetails, error) {
+		return client.ListWorkers(ctx, workerclient.ListWorkersOptions{Action: action, ProjectKey: h.projectKey})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []*removeTarget
+	for _, worker := range workers {
+		if match != "" {
+			// The pattern was validated beforehand
+			if matched, _ := path.Match(match, worker.Key); !matched {
+				continue
+			}
+		}
+		targets = append(targets, &removeTarget{key: worker.Key, details: worker})
+	}
+
+	slices.SortFunc(targets, func(a, b *removeTarget) int {
+		return strings.Compare(a.key, b.key)
+	})
+
+	return targets, nil
+}
+
+// fetchWorkers reads the details of the workers to back them up.
+// A worker that is not found is still removed, so that the server reports the error.
+func (h *removeCommandHandler) fetchWorkers(keys []string) ([]*removeTarget, error) {
+	var targets []*removeTarget
+	for _, key := range keys {
+		if slices.ContainsFunc(targets, func(t *removeTarget) bool { return t.key == key }) {
+			continue
+		}
+		details, err := common.FetchWorkerDetails(h.ctx, h.server, key, h.projectKey)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, &removeTarget{key: key, details: details})
+	}
+	return targets, nil
+}
+
+func (h *removeCommandHandler) printDryRun(targets []*removeTarget, output *common.Output) error {
+	results := make([]*removeResult, 0, len(targets))
+	for _, target := range targets {
+		results = append(results, h.newResult(target, "dry-run"))
+	}
+
+	if output.Format != format.None {
+		return output.Print(&common.Result{Value: results})
+	}
 
+	for _, result := range results {
+		action := result.Action
+		if action == "" {
+			action = "unknown action"
+		}
+		if err := common.Print("Would remove worker '%s' (%s)%s\n", result.Key, action, inProject(result.ProjectKey)); err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+// confirm asks the user before removing the workers, except with --yes.
+// Without a terminal, a single worker is removed as before, several workers require --yes.
+func (h *removeCommandHandler) confirm(targets []*removeTarget) (bool, error) {
+	if h.ctx.GetBoolFlagValue(flagRemoveYes) {
+		return true, nil
+	}
+
+	if !common.IsInteractive() {
+		if h.bulk || len(targets) > 1 {
+			return false, fmt.Errorf("refusing to remove %d workers without a confirmation, use --%s to confirm or --%s to list them", len(targets), flagRemoveYes, flagRemoveDryRun)
+		}
+		return true, nil
+	}
+
+	keys := make([]string, len(targets))
+	for i, target := range targets {
+		keys[i] = target.key
+	}
+
+	question := fmt.Sprintf("Remove worker '%s'%s", keys[0], inProject(h.projectKey))
+	if len(keys) > 1 {
+		question = fmt.Sprintf("Remove %d workers%s: %s", len(keys), inProject(h.projectKey), strings.Join(keys, ", "))
+	}
+
+	confirmed, err := common.Confirm(question)
+	if err == nil && !confirmed {
+		log.Info("Undeploy cancelled")
+	}
+	return confirmed, err
+}
+
+// remove backs up then removes each worker, a worker that cannot be backed up is not removed.
+func (h *removeCommandHandler) remove(targets []*removeTarget) ([]*removeResult, error) {
+	backupDir, err := h.getBackupDir()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*removeResult
+	var errs []error
+
+	for _, target := range targets {
+		result := h.newResult(target, "removed")
+		results = append(results, result)
+
+		if target.details != nil {
+			if result.BackupFile, err = saveWorkerBackup(backupDir, target.details); err != nil {
+				result.Status, result.Error = "failed", err.Error()
+				errs = append(errs, fmt.Errorf("worker '%s' not removed, cannot back it up: %w", target.key, err))
+				continue
+			}
+			log.Info(fmt.Sprintf("Worker '%s' saved to %s", target.key, result.BackupFile))
+		} else {
+			log.Warn(fmt.Sprintf("No details found for worker '%s', it will not be backed up", target.key))
+		}
+
+		log.Info(fmt.Sprintf("Removing worker '%s' ...", target.key))
+
+		err = common.RunWorkerClient(h.ctx, h.server, func(ctx context.Context, client *workerclient.Client) error {
+			return client.DeleteWorker(ctx, target.key, h.projectKey)
+		})
+		if err != nil {
+			result.Status, result.Error = "failed", err.Error()
+			errs = append(errs, err)
+			continue
+		}
+
+		log.Info(fmt.Sprintf("Worker '%s' removed", target.key))
+	}
+
+	if len(errs) == 1 && len(targets) == 1 {
+		return results, errs[0]
+	}
+	if len(errs) > 0 {
+		return results, fmt.Errorf("%d of %d workers not removed: %w", len(errs), len(targets), errors.Join(errs...))
+	}
+
+	return results, nil
+}
+
+func (h *removeCommandHandler) newResult(target *removeTarget, status string) *removeResult {
+	result := &removeResult{Key: target.key, ProjectKey: h.projectKey, Status: status}
+	if target.details != nil {
+		result.Action = target.details.Action
+		if target.details.ProjectKey != "" {
+			result.ProjectKey = target.details.ProjectKey
+		}
+	}
+	return result
+}
+
+func (h *removeCommandHandler) getBackupDir() (string, error) {
+	if backupDir := h.ctx.GetStringFlagValue(flagRemoveBackupDir); backupDir != "" {
+		return backupDir, nil
+	}
+	homeDir, err := coreutils.GetJfrogHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot locate the backup directory: %w", err)
+	}
+	return filepath.Join(homeDir, removeBackupDir), nil
+}
+
+// saveWorkerBackup writes the details of a worker to <dir>/[<project>-]<key>-<timestamp>.json.
+func saveWorkerBackup(dir string, details *model.WorkerDetails) (string, error) {
+	content, err := json.MarshalIndent(details, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	if err = os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+
+	name := details.Key
+	if details.ProjectKey != "" {
+		name = details.ProjectKey + "-" + name
+	}
+	backupFile := filepath.Join(dir, fmt.Sprintf("%s-%s.json", name, time.Now().UTC().Format("20060102T150405.000Z")))
+
+	if err = os.WriteFile(backupFile, append(content, '\n'), 0o600); err != nil {
+		return "", err
+	}
+
+	return backupFile, nil
+}
+
+func inProject(projectKey string) string {
+	if projectKey == "" {
+		return ""
+	}
+	return fmt.Sprintf(" in project '%s'", projectKey)
+}
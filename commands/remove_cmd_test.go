@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -150,3 +151,193 @@ func TestWorkerRemove_NoFormat(t *testing.T) {
 	require.NoError(t, runCmd("worker", "undeploy"))
 	assert.Empty(t, out.String(), "expected no JSON output when --format is not set, got: %s", out.String())
 }
+
+func TestWorkerRemove_Bulk(t *testing.T) {
+	workers := []*model.WorkerDetails{
+		{Key: "tmp-1", Action: "BEFORE_UPLOAD", ProjectKey: "proj-1", SourceCode: "export default async () => ({ status: 'OK' })"},
+		{Key: "tmp-2", Action: "BEFORE_DOWNLOAD", ProjectKey: "proj-1"},
+		{Key: "prod-1", Action: "BEFORE_UPLOAD", ProjectKey: "proj-1"},
+	}
+
+	tests := []struct {
+		name             string
+		commandArgs      []string
+		interactiveInput string
+		wantErr          string
+		wantRemaining    []string
+		wantBackups      int
+	}{
+		{
+			name:          "several keys",
+			commandArgs:   []string{"tmp-1", "tmp-2", "--" + flagRemoveYes},
+			wantRemaining: []string{"prod-1"},
+			wantBackups:   2,
+		},
+		{
+			name:          "match",
+			commandArgs:   []string{"--" + flagRemoveMatch, "tmp-*", "--" + flagRemoveYes},
+			wantRemaining: []string{"prod-1"},
+			wantBackups:   2,
+		},
+		{
+			name:          "match and action",
+			commandArgs:   []string{"--" + flagRemoveMatch, "tmp-*", "--" + flagRemoveAction, "BEFORE_UPLOAD", "--" + flagRemoveYes},
+			wantRemaining: []string{"prod-1", "tmp-2"},
+			wantBackups:   1,
+		},
+		{
+			name:          "dry run",
+			commandArgs:   []string{"--" + flagRemoveMatch, "*", "--" + flagRemoveDryRun},
+			wantRemaining: []string{"prod-1", "tmp-1", "tmp-2"},
+		},
+		{
+			name:             "confirmed",
+			commandArgs:      []string{"--" + flagRemoveMatch, "tmp-*"},
+			interactiveInput: "y\n",
+			wantRemaining:    []string{"prod-1"},
+			wantBackups:      2,
+		},
+		{
+			name:             "cancelled",
+			commandArgs:      []string{"--" + flagRemoveMatch, "tmp-*"},
+			interactiveInput: "n\n",
+			wantRemaining:    []string{"prod-1", "tmp-1", "tmp-2"},
+		},
+		{
+			name:          "refused without a terminal",
+			commandArgs:   []string{"tmp-1", "tmp-2"},
+			wantErr:       "refusing to remove 2 workers without a confirmation, use --yes to confirm or --dry-run to list them",
+			wantRemaining: []string{"prod-1", "tmp-1", "tmp-2"},
+		},
+		{
+			name:          "keys and match",
+			commandArgs:   []string{"tmp-1", "--" + flagRemoveMatch, "tmp-*"},
+			wantErr:       "worker keys cannot be combined with --match or --action",
+			wantRemaining: []string{"prod-1", "tmp-1", "tmp-2"},
+		},
+		{
+			name:          "invalid pattern",
+			commandArgs:   []string{"--" + flagRemoveMatch, "tmp-["},
+			wantErr:       "invalid --match pattern 'tmp-[': syntax error in pattern",
+			wantRemaining: []string{"prod-1", "tmp-1", "tmp-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubWorkers := make([]*model.WorkerDetails, len(workers))
+			for i, worker := range workers {
+				stubWorkers[i] = &model.WorkerDetails{}
+				*stubWorkers[i] = *worker
+			}
+
+			serverStub := common.NewServerStub(t).
+				WithStatefulWorkers().
+				WithProjectKey("proj-1").
+				WithWorkers(stubWorkers...).
+				WithGetOneEndpoint().
+				WithGetAllEndpoint().
+				WithDeleteEndpoint()
+			common.NewMockWorkerServer(t, serverStub)
+
+			common.PrepareWorkerDirForTest(t)
+
+			if tt.interactiveInput != "" {
+				common.SetInteractiveInput(t, tt.interactiveInput)
+			}
+
+			var out bytes.Buffer
+			common.SetCliOut(&out)
+			t.Cleanup(func() { common.SetCliOut(os.Stdout) })
+
+			backupDir := t.TempDir()
+
+			runCmd := common.CreateCliRunner(t, GetRemoveCommand())
+			err := runCmd(append([]string{"worker", "undeploy", "--" + model.FlagProjectKey, "proj-1", "--" + flagRemoveBackupDir, backupDir}, tt.commandArgs...)...)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.wantErr)
+			}
+
+			var remaining []string
+			for _, worker := range workers {
+				if serverStub.GetWorker(worker.Key) != nil {
+					remaining = append(remaining, worker.Key)
+				}
+			}
+			assert.ElementsMatch(t, tt.wantRemaining, remaining)
+
+			backups, err := filepath.Glob(filepath.Join(backupDir, "*.json"))
+			require.NoError(t, err)
+			assert.Len(t, backups, tt.wantBackups)
+		})
+	}
+}
+
+func TestWorkerRemove_Backup(t *testing.T) {
+	worker := &model.WorkerDetails{Key: "wk-1", Action: "BEFORE_UPLOAD", ProjectKey: "proj-1", SourceCode: "export default async () => ({ status: 'OK' })"}
+
+	common.NewMockWorkerServer(t, common.NewServerStub(t).
+		WithProjectKey("proj-1").
+		WithWorkers(worker).
+		WithGetOneEndpoint().
+		WithDeleteEndpoint())
+
+	common.PrepareWorkerDirForTest(t)
+
+	var out bytes.Buffer
+	common.SetCliOut(&out)
+	t.Cleanup(func() { common.SetCliOut(os.Stdout) })
+
+	backupDir := t.TempDir()
+
+	runCmd := common.CreateCliRunner(t, GetRemoveCommand())
+	require.NoError(t, runCmd("worker", "undeploy", "wk-1", "--"+model.FlagProjectKey, "proj-1", "--"+flagRemoveBackupDir, backupDir, "--"+flagRemoveYes, "--"+format.FlagName, "json"))
+
+	assert.JSONEq(t, `{"status_code":204,"content":""}`, out.String())
+
+	backups, err := filepath.Glob(filepath.Join(backupDir, "proj-1-wk-1-*.json"))
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+
+	content, err := os.ReadFile(backups[0])
+	require.NoError(t, err)
+
+	var saved model.WorkerDetails
+	require.NoError(t, json.Unmarshal(content, &saved))
+	assert.Equal(t, *worker, saved)
+}
+
+func TestWorkerRemove_BulkFormatJSON(t *testing.T) {
+	common.NewMockWorkerServer(t, common.NewServerStub(t).
+		WithWorkers(&model.WorkerDetails{Key: "wk-1", Action: "BEFORE_UPLOAD"}).
+		WithGetOneEndpoint().
+		WithDeleteEndpoint())
+
+	common.PrepareWorkerDirForTest(t)
+
+	var out bytes.Buffer
+	common.SetCliOut(&out)
+	t.Cleanup(func() { common.SetCliOut(os.Stdout) })
+
+	backupDir := t.TempDir()
+
+	runCmd := common.CreateCliRunner(t, GetRemoveCommand())
+	err := runCmd("worker", "undeploy", "wk-1", "wk-2", "--"+flagRemoveBackupDir, backupDir, "--"+flagRemoveYes, "--"+format.FlagName, "json")
+	require.Error(t, err)
+	assert.Regexp(t, `^1 of 2 workers not removed: `, err.Error())
+
+	var results []*removeResult
+	require.NoError(t, json.Unmarshal(out.Bytes(), &results))
+	require.Len(t, results, 2)
+
+	assert.Equal(t, "wk-1", results[0].Key)
+	assert.Equal(t, "removed", results[0].Status)
+	assert.NotEmpty(t, results[0].BackupFile)
+
+	assert.Equal(t, "wk-2", results[1].Key)
+	assert.Equal(t, "failed", results[1].Status)
+	assert.Empty(t, results[1].BackupFile)
+	assert.NotEmpty(t, results[1].Error)
+}
@@ -0,0 +1,186 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	plugins_common "github.com/jfrog/jfrog-cli-core/v2/plugins/common"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+
+	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+)
+
+type renameCommandHandler struct {
+	*copyCommandHandler
+	// The operations to apply, in reverse order, to restore the state prior to the rename
+	rollbacks []func() error
+}
+
+func GetRenameCommand() components.Command {
+	return components.Command{
+		Name:        "rename",
+		Description: "Rename a deployed worker",
+		AIDescription: `Change the key of a deployed worker. The worker is recreated server-side under the new key and the old one is removed; no local files are read or written.
+
+When to use:
+- Fixing a typo in a worker key.
+- Aligning worker keys with a naming convention.
+
+Prerequisites:
+- The worker must already be deployed and the new key must not be in use.
+- Configured server (jf c add or jf login) with permission to create and delete workers in the target scope (project or platform).
+
+Common patterns:
+  $ jf worker rename my-wroker my-worker
+  $ jf worker rename my-worker my-new-worker --project-key my-project
+
+Gotchas:
+- The new worker is created disabled and checked before the enabled state is moved from the old worker to the new one; the old worker is removed last.
+- If any step fails, the completed steps are rolled back: the old worker keeps its state and the new one is removed.
+- Execution history is kept under the old key.
+- The rename is refused before any change when the server does not return the value of a secret (omitted or masked), since the secret would be lost; use 'jf worker copy', which skips these secrets, then redeploy them.
+- Update manifest.json with the new key before the next 'jf worker deploy', or the old worker will be recreated.
+
+Related: jf worker copy, jf worker deploy, jf worker undeploy`,
+		Aliases: []string{"mv"},
+		Flags: slices.Concat([]components.Flag{
+			plugins_common.GetServerIdFlag(),
+			model.GetTimeoutFlag(),
+			model.GetProjectKeyFlag(),
+		}, model.GetMetadataFlags(), model.GetHTTPClientFlags()),
+		Arguments: []components.Argument{
+			{Name: "worker-key", Description: "The current key of the worker."},
+			{Name: "new-worker-key", Description: "The new key of the worker."},
+		},
+		Action: func(c *components.Context) error {
+			h, oldKey, newKey, err := newCopyCommandHandler(c)
+			if err != nil {
+				return err
+			}
+			return (&renameCommandHandler{copyCommandHandler: h}).rename(oldKey, newKey)
+		},
+	}
+}
+
+func (h *renameCommandHandler) rename(oldKey, newKey string) error {
+	source, err := h.fetchSourceAndCheckTarget(oldKey, newKey)
+	if err != nil {
+		return err
+	}
+
+	// The old worker is updated, then removed, with the secrets it was read with: a secret without its value would be lost
+	for _, secret := range source.Secrets {
+		if !secretValueReturned(secret) {
+			return fmt.Errorf("cannot rename worker '%s': the value of secret '%s' was not returned by the server and would be lost", oldKey, secret.Key)
+		}
+	}
+
+	log.Info(fmt.Sprintf("Renaming worker '%s' to '%s'", oldKey, newKey))
+
+	if err = h.doRename(source, newKey); err != nil {
+		return h.rollback(err)
+	}
+
+	log.Info(fmt.Sprintf("Worker '%s' renamed to '%s'", oldKey, newKey))
+
+	return nil
+}
+
+func (h *renameCommandHandler) doRename(source *model.WorkerDetails, newKey string) error {
+	request, err := h.prepareCopyRequest(source, newKey)
+	if err != nil {
+		return err
+	}
+
+	request.Enabled = false
+
+	if err = h.createWorker(request); err != nil {
+		return fmt.Errorf("cannot create worker '%s': %w", newKey, err)
+	}
+	h.onRollback(func() error {
+		return h.deleteWorker(newKey, request.ProjectKey)
+	})
+
+	if err = h.checkCreatedWorker(request); err != nil {
+		return err
+	}
+
+	if source.Enabled {
+		oldRequest, err := h.prepareCopyRequest(source, source.Key)
+		if err != nil {
+			return err
+		}
+
+		oldRequest.Enabled = false
+		if err = h.updateWorker(oldRequest); err != nil {
+			return fmt.Errorf("cannot disable worker '%s': %w", source.Key, err)
+		}
+		h.onRollback(func() error {
+			oldRequest.Enabled = true
+			return h.updateWorker(oldRequest)
+		})
+
+		request.Enabled = true
+		if err = h.updateWorker(request); err != nil {
+			return fmt.Errorf("cannot enable worker '%s': %w", newKey, err)
+		}
+	}
+
+	if err = h.deleteWorker(source.Key, h.sourceProjectKey(source)); err != nil {
+		return fmt.Errorf("cannot remove worker '%s': %w", source.Key, err)
+	}
+
+	return nil
+}
+
+// checkCreatedWorker makes sure the server returns the worker that was just created.
+func (h *renameCommandHandler) checkCreatedWorker(request *model.WorkerRequest) error {
+	created, err := common.FetchWorkerDetails(h.ctx, h.server, request.Key, request.ProjectKey)
+	if err != nil {
+		return err
+	}
+
+	if created == nil {
+		return fmt.Errorf("worker '%s' was not found after its creation", request.Key)
+	}
+
+	// The source code may be returned base64 encoded, or not, whatever the way it was sent
+	createdSourceCode, err := common.DecodeSourceCode(created.SourceCode)
+	if err != nil {
+		return err
+	}
+	requestSourceCode, err := common.DecodeSourceCode(request.SourceCode)
+	if err != nil {
+		return err
+	}
+
+	if createdSourceCode != requestSourceCode || created.Action != request.Action.Name {
+		return fmt.Errorf("worker '%s' was not created as expected", request.Key)
+	}
+
+	return nil
+}
+
+func (h *renameCommandHandler) onRollback(rollback func() error) {
+	h.rollbacks = append(h.rollbacks, rollback)
+}
+
+func (h *renameCommandHandler) rollback(cause error) error {
+	if len(h.rollbacks) == 0 {
+		return cause
+	}
+
+	log.Warn(fmt.Sprintf("Rename failed, rolling back: %+v", cause))
+
+	errs := []error{cause}
+	for i := len(h.rollbacks) - 1; i >= 0; i-- {
+		if err := h.rollbacks[i](); err != nil {
+			errs = append(errs, fmt.Errorf("rollback failed: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,127 @@
+//go:build test
+// +build test
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+)
+
+func TestRenameCommand(t *testing.T) {
+	tests := []struct {
+		name        string
+		commandArgs []string
+		serverStub  *common.ServerStub
+		wantErr     string
+		assert      func(t *testing.T, stub *common.ServerStub)
+	}{
+		{
+			name:        "rename enabled worker",
+			commandArgs: []string{"wk-0", "wk-1"},
+			serverStub: common.NewServerStub(t).
+				WithUpdateEndpoint(nil).
+				WithDeleteEndpoint().
+				WithWorkers(&model.WorkerDetails{Key: "wk-0", Action: "GENERIC_EVENT", Enabled: true, SourceCode: "code"}),
+			assert: func(t *testing.T, stub *common.ServerStub) {
+				assert.Nil(t, stub.GetWorker("wk-0"))
+				require.NotNil(t, stub.GetWorker("wk-1"))
+				assert.True(t, stub.GetWorker("wk-1").Enabled)
+				assert.Equal(t, "code", stub.GetWorker("wk-1").SourceCode)
+			},
+		},
+		{
+			name:        "rename disabled worker",
+			commandArgs: []string{"wk-0", "wk-1"},
+			serverStub: common.NewServerStub(t).
+				WithDeleteEndpoint().
+				WithWorkers(&model.WorkerDetails{Key: "wk-0", Action: "GENERIC_EVENT", Enabled: false}),
+			assert: func(t *testing.T, stub *common.ServerStub) {
+				assert.Nil(t, stub.GetWorker("wk-0"))
+				require.NotNil(t, stub.GetWorker("wk-1"))
+				assert.False(t, stub.GetWorker("wk-1").Enabled)
+			},
+		},
+		{
+			name:        "rollback when the enabled state cannot be swapped",
+			commandArgs: []string{"wk-0", "wk-1"},
+			serverStub: common.NewServerStub(t).
+				WithDeleteEndpoint().
+				WithWorkers(&model.WorkerDetails{Key: "wk-0", Action: "GENERIC_EVENT", Enabled: true}),
+			wantErr: "cannot disable worker 'wk-0'",
+			assert: func(t *testing.T, stub *common.ServerStub) {
+				require.NotNil(t, stub.GetWorker("wk-0"))
+				assert.True(t, stub.GetWorker("wk-0").Enabled)
+				assert.Nil(t, stub.GetWorker("wk-1"))
+			},
+		},
+		{
+			name:        "rollback when the old worker cannot be removed",
+			commandArgs: []string{"wk-0", "wk-1"},
+			serverStub: common.NewServerStub(t).
+				WithUpdateEndpoint(nil).
+				WithWorkers(&model.WorkerDetails{Key: "wk-0", Action: "GENERIC_EVENT", Enabled: true}),
+			wantErr: "cannot remove worker 'wk-0'",
+			assert: func(t *testing.T, stub *common.ServerStub) {
+				require.NotNil(t, stub.GetWorker("wk-0"))
+				assert.True(t, stub.GetWorker("wk-0").Enabled)
+			},
+		},
+		{
+			name:        "fails before any change if a secret value is not returned",
+			commandArgs: []string{"wk-0", "wk-1"},
+			serverStub: common.NewServerStub(t).
+				WithUpdateEndpoint(nil).
+				WithDeleteEndpoint().
+				WithWorkers(&model.WorkerDetails{Key: "wk-0", Action: "GENERIC_EVENT", Enabled: true, Secrets: []*model.Secret{{Key: "sec-1", Value: "val-1"}, {Key: "sec-2", Value: "****"}}}),
+			wantErr: "cannot rename worker 'wk-0': the value of secret 'sec-2' was not returned by the server and would be lost",
+			assert: func(t *testing.T, stub *common.ServerStub) {
+				require.NotNil(t, stub.GetWorker("wk-0"))
+				assert.True(t, stub.GetWorker("wk-0").Enabled)
+				assert.Nil(t, stub.GetWorker("wk-1"))
+			},
+		},
+		{
+			name:        "fails if target exists",
+			commandArgs: []string{"wk-0", "wk-1"},
+			serverStub: common.NewServerStub(t).
+				WithWorkers(&model.WorkerDetails{Key: "wk-0"}, &model.WorkerDetails{Key: "wk-1"}),
+			wantErr: "worker 'wk-1' already exists",
+			assert: func(t *testing.T, stub *common.ServerStub) {
+				assert.NotNil(t, stub.GetWorker("wk-0"))
+				assert.NotNil(t, stub.GetWorker("wk-1"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			common.NewMockWorkerServer(t,
+				tt.serverStub.
+					WithT(t).
+					WithStatefulWorkers().
+					WithDefaultActionsMetadataEndpoint().
+					WithGetOneEndpoint().
+					WithCreateEndpoint(nil),
+			)
+
+			runCmd := common.CreateCliRunner(t, GetRenameCommand())
+
+			err := runCmd(append([]string{"worker", "rename"}, tt.commandArgs...)...)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			tt.assert(t, tt.serverStub)
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/jfrog/jfrog-cli-core/v2/common/format"
+	plugins_common "github.com/jfrog/jfrog-cli-core/v2/plugins/common"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+
+	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+)
+
+const (
+	flagSamplePayloadSet  = "set"
+	flagSamplePayloadSave = "save"
+)
+
+func GetSamplePayloadCommand() components.Command {
+	return components.Command{
+		Name:        "sample-payload",
+		Description: "Print or save the sample payload of an action.",
+		AIDescription: `Print the sample payload that the server provides for an action, optionally with some values overridden, or save it to a file. The payload has the shape the worker receives at runtime, so it is a starting point for 'jf worker test-run' and for the fixtures of 'jf worker test-run --fixtures'.
+
+When to use:
+- Writing a payload for 'jf worker test-run' without reading the types of the action.
+- Creating variants of a payload for fixtures, e.g. one per repository key.
+- Checking the fields an action delivers before writing a worker.
+
+Prerequisites:
+- Configured server (jf c add or jf login), or --actions-file / --offline.
+- Without an ACTION argument, a manifest.json in the current directory: its action, application and project key are used.
+
+Common patterns:
+  $ jf worker sample-payload BEFORE_DOWNLOAD
+  $ jf worker sample-payload                                  # the action of manifest.json
+  $ jf worker sample-payload BEFORE_UPLOAD --set metadata.repoPath.key=libs-release
+  $ jf worker sample-payload BEFORE_UPLOAD --set 'metadata.repoPath.key=libs-release;metadata.contentLength=2048'
+  $ jf worker sample-payload --save fixtures/release.json --set metadata.repoPath.key=libs-release
+  $ jf worker sample-payload BEFORE_DOWNLOAD --format yaml
+
+Gotchas:
+- Several overrides are separated by ';' in a single --set, the last --set flag wins otherwise.
+- An override value is read as JSON when it is valid JSON (numbers, booleans, null, objects, quoted strings), else as a string: use --set 'a.b="true"' for the string "true".
+- The missing objects of an overridden path are created; an array element can be appended with the length of the array as index, e.g. items[2] on an array of two elements.
+- With --save the payload is written as JSON to the file, its directories are created and an existing file is overwritten.
+- An action provided by several applications needs --application.
+- The actions metadata are cached per server for an hour (JFROG_WORKER_CLI_METADATA_CACHE_TTL); pass --refresh-metadata to download them again.
+
+Related: jf worker test-run, jf worker init, jf worker list-event`,
+		Aliases:          []string{"sp"},
+		SupportedFormats: common.DocumentFormats,
+		DefaultFormat:    format.Json,
+		Flags: slices.Concat([]components.Flag{
+			plugins_common.GetServerIdFlag(),
+			model.GetTimeoutFlag(),
+			model.GetProjectKeyFlag(),
+			model.GetApplicationFlag(),
+			components.NewStringFlag(flagSamplePayloadSet, "Override values of the payload with <path>=<value>, separated by ';'.", components.WithStrDefaultValue("")),
+			components.NewStringFlag(flagSamplePayloadSave, "A file where the payload is saved instead of printed.", components.WithStrDefaultValue("")),
+			model.GetTemplateFlag(),
+			model.GetQueryFlag(),
+		}, model.GetMetadataFlags(), model.GetHTTPClientFlags()),
+		Arguments: []components.Argument{
+			{
+				Name:        "action",
+				Description: "The action of the payload, the action of manifest.json if omitted. Use `jf worker list-event` to see the list of available actions.",
+				Optional:    true,
+			},
+		},
+		Action: runSamplePayloadCommand,
+	}
+}
+
+func runSamplePayloadCommand(c *components.Context) error {
+	output, err := common.NewOutput(c)
+	if err != nil {
+		return err
+	}
+
+	action, application, projectKey, err := getSamplePayloadAction(c)
+	if err != nil {
+		return err
+	}
+
+	server := &common.Server{Metadata: common.GetMetadataOptions(c)}
+	// With an actions file, the command works without any configured server
+	if server.Metadata.ActionsFile == "" {
+		var err error
+		if server, err = common.GetServerDetails(c); err != nil {
+			return err
+		}
+	}
+
+	if err = common.CheckProjectSupport(c, server, projectKey); err != nil {
+		return err
+	}
+
+	actionsMeta, err := common.FetchActions(c, server, projectKey)
+	if err != nil {
+		return err
+	}
+
+	actionMeta, err := actionsMeta.FindAction(action, application)
+	if err != nil {
+		return err
+	}
+
+	payload, err := common.SamplePayload(actionMeta)
+	if err != nil {
+		return err
+	}
+
+	for _, expression := range c.GetStringsArrFlagValue(flagSamplePayloadSet) {
+		if expression == "" {
+			continue
+		}
+		if err = common.SetPayloadValue(payload, expression); err != nil {
+			return err
+		}
+	}
+
+	if file := c.GetStringFlagValue(flagSamplePayloadSave); file != "" {
+		if err = common.WriteSamplePayload(file, payload); err != nil {
+			return err
+		}
+		log.Info(fmt.Sprintf("Sample payload of %s saved to %s", actionMeta.Action.Name, file))
+		return nil
+	}
+
+	return output.Print(&common.Result{Value: payload})
+}
+
+// getSamplePayloadAction returns the action of the argument, else the one of the manifest with its application and project key.
+// The flags take precedence over the manifest.
+func getSamplePayloadAction(c *components.Context) (string, string, string, error) {
+	application := c.GetStringFlagValue(model.FlagApplication)
+	projectKey := c.GetStringFlagValue(model.FlagProjectKey)
+
+	if len(c.Arguments) > 0 {
+		return c.Arguments[0], application, projectKey, nil
+	}
+
+	manifest, err := common.ReadManifest()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", "", "", errors.New("the action is missing, pass it as argument or run the command from a worker directory")
+		}
+		return "", "", "", err
+	}
+
+	if application == "" {
+		application = manifest.Application
+	}
+	if projectKey == "" {
+		projectKey = manifest.ProjectKey
+	}
+	return manifest.Action, application, projectKey, nil
+}
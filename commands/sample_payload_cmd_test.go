@@ -0,0 +1,145 @@
+//go:build test
+// +build test
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/common/format"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+)
+
+func TestSamplePayloadCommand(t *testing.T) {
+	samplePayload := func(actionName string, overrides ...string) map[string]any {
+		actionMeta, err := common.LoadSampleActions(t).FindAction(actionName)
+		require.NoError(t, err)
+		payload, err := common.SamplePayload(actionMeta)
+		require.NoError(t, err)
+		for _, override := range overrides {
+			require.NoError(t, common.SetPayloadValue(payload, override))
+		}
+		return payload
+	}
+
+	tests := []struct {
+		name          string
+		commandArgs   []string
+		inWorkerDir   string
+		wantPayload   map[string]any
+		wantSavedFile string
+		wantErr       string
+	}{
+		{
+			name:        "action argument",
+			commandArgs: []string{"BEFORE_UPLOAD"},
+			wantPayload: samplePayload("BEFORE_UPLOAD"),
+		},
+		{
+			name:        "action of the manifest",
+			inWorkerDir: "BEFORE_DOWNLOAD",
+			wantPayload: samplePayload("BEFORE_DOWNLOAD"),
+		},
+		{
+			name:        "overrides",
+			commandArgs: []string{"BEFORE_UPLOAD", "--" + flagSamplePayloadSet, "metadata.repoPath.key=libs-release;metadata.contentLength=2048"},
+			wantPayload: samplePayload("BEFORE_UPLOAD", "metadata.repoPath.key=libs-release", "metadata.contentLength=2048"),
+		},
+		{
+			name:          "save",
+			commandArgs:   []string{"BEFORE_UPLOAD", "--" + flagSamplePayloadSet, "metadata.repoPath.key=libs-release"},
+			wantSavedFile: filepath.Join("fixtures", "release.json"),
+			wantPayload:   samplePayload("BEFORE_UPLOAD", "metadata.repoPath.key=libs-release"),
+		},
+		{
+			name:        "invalid override",
+			commandArgs: []string{"BEFORE_UPLOAD", "--" + flagSamplePayloadSet, "metadata.repoPath.key[0]=x"},
+			wantErr:     "cannot set 'metadata.repoPath.key[0]': [0] is applied to a string",
+		},
+		{
+			name:    "missing action",
+			wantErr: "the action is missing, pass it as argument or run the command from a worker directory",
+		},
+		{
+			name:        "unknown action",
+			commandArgs: []string{"HACK_SYSTEM"},
+			wantErr:     "action 'HACK_SYSTEM' not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			common.NewMockWorkerServer(t, common.NewServerStub(t).WithDefaultActionsMetadataEndpoint())
+			dir, workerName := common.PrepareWorkerDirForTest(t)
+
+			runCmd := common.CreateCliRunner(t, GetInitCommand(), GetSamplePayloadCommand())
+			if tt.inWorkerDir != "" {
+				require.NoError(t, runCmd("worker", "init", tt.inWorkerDir, workerName))
+			}
+
+			var out bytes.Buffer
+			common.SetCliOut(&out)
+			t.Cleanup(func() { common.SetCliOut(os.Stdout) })
+
+			args := append([]string{"worker", "sample-payload"}, tt.commandArgs...)
+			if tt.wantSavedFile != "" {
+				args = append(args, "--"+flagSamplePayloadSave, filepath.Join(dir, tt.wantSavedFile))
+			}
+
+			err := runCmd(args...)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			content := out.Bytes()
+			if tt.wantSavedFile != "" {
+				assert.Empty(t, out.String())
+				content, err = os.ReadFile(filepath.Join(dir, tt.wantSavedFile))
+				require.NoError(t, err)
+			}
+
+			var gotPayload map[string]any
+			require.NoError(t, json.Unmarshal(content, &gotPayload))
+			assert.Equal(t, tt.wantPayload, gotPayload)
+		})
+	}
+}
+
+func TestWorkerDryRun_Sample(t *testing.T) {
+	actionMeta, err := common.LoadSampleActions(t).FindAction("BEFORE_DOWNLOAD")
+	require.NoError(t, err)
+	wantPayload, err := common.SamplePayload(actionMeta)
+	require.NoError(t, err)
+
+	serverStub := common.NewServerStub(t).
+		WithWorkers(&model.WorkerDetails{Key: workerKeyForDryRunTest}).
+		WithDefaultActionsMetadataEndpoint().
+		WithGetOneEndpoint().
+		WithTestEndpoint(validateTestPayloadData(wantPayload), map[string]any{"data": "done", "executionStatus": "STATUS_SUCCESS"})
+	common.NewMockWorkerServer(t, serverStub)
+	common.PrepareWorkerDirForTest(t)
+
+	runCmd := common.CreateCliRunner(t, GetInitCommand(), GetDryRunCommand())
+	require.NoError(t, runCmd("worker", "init", "BEFORE_DOWNLOAD", workerKeyForDryRunTest))
+
+	var out bytes.Buffer
+	common.SetCliOut(&out)
+	t.Cleanup(func() { common.SetCliOut(os.Stdout) })
+
+	require.NoError(t, runCmd("worker", "dry-run", "--"+flagDryRunSample, "--"+format.FlagName, "json"))
+	assert.JSONEq(t, `{"data":"done","executionStatus":"STATUS_SUCCESS"}`, out.String())
+
+	err = runCmd("worker", "dry-run", "--"+flagDryRunSample, `{}`)
+	assert.EqualError(t, err, "a json payload cannot be combined with --sample")
+}
@@ -1,9 +1,11 @@
 package commands
 
 import (
-	"encoding/json"
+	"cmp"
+	"context"
 	"fmt"
-	"net/http"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/jfrog/jfrog-cli-core/v2/common/format"
@@ -11,20 +13,20 @@ import (
 	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
 	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
 	"github.com/jfrog/jfrog-cli-platform-services/model"
+	"github.com/jfrog/jfrog-cli-platform-services/workerclient"
+	"github.com/jfrog/jfrog-client-go/utils/log"
 )
 
-type executionHistoryEntry struct {
-	WorkerKey        string `json:"workerKey"`
-	WorkerType       string `json:"workerType"`
-	WorkerProjectKey string `json:"workerProjectKey"`
-	ExecutionStatus  string `json:"executionStatus"`
-	StartTimeMillis  int64  `json:"startTimeMillis"`
-	EndTimeMillis    int64  `json:"endTimeMillis"`
-	TriggeredBy      string `json:"triggeredBy"`
-	TestRun          bool   `json:"testRun"`
-	ExecutedVersion  string `json:"executedVersion"`
-	TraceID          string `json:"traceId"`
-}
+const (
+	flagHistoryWithTestRuns = "with-test-runs"
+	flagHistoryStatus       = "status"
+	flagHistorySince        = "since"
+	flagHistoryUntil        = "until"
+	flagHistoryVersion      = "version"
+	flagHistoryTriggeredBy  = "triggered-by"
+	flagHistoryLimit        = "limit"
+	flagHistoryProjectWide  = "project-wide"
+)
 
 func GetShowExecutionHistoryCommand() components.Command {
 	return components.Command{
@@ -47,84 +49,244 @@ Common patterns:
   $ jf worker execution-history               # worker name read from manifest.json
   $ jf worker execution-history my-worker --with-test-runs
   $ jf worker execution-history my-worker --project my-project --format table
+  $ jf worker execution-history my-worker --format csv > history.csv
+  $ jf worker execution-history my-worker --query '.[] | select(.executionStatus != "STATUS_SUCCESS") | .traceId' --format csv
+  $ jf worker execution-history my-worker --status fail,timeout --since 24h --limit 20
+  $ jf worker execution-history my-worker --since 2024-01-15 --until 2024-01-16 --version 1.2.0
+  $ jf worker execution-history --project-wide --project my-project --triggered-by admin --format aligned
+  $ jf worker execution-history my-worker --follow
+  $ jf worker execution-history worker-a worker-b --follow --status fail --poll-interval-ms 2000
+  $ jf worker execution-history --project-wide --project my-project --follow --format json | jq .traceId
 
 Gotchas:
 - Test runs (from 'jf worker test-run') are excluded by default; pass --with-test-runs to include them.
-- Default output is JSON; pass --format table for a CSV view with human-readable timestamps in UTC.
+- Default output is JSON; pass --format table for a CSV view with human-readable timestamps in UTC, like --format csv, or --format aligned for an aligned table. --format table keeps printing CSV for the scripts parsing it.
+- The timestamps stay in milliseconds with --format json, yaml, with --query and with --template, e.g. '{{.TraceID}} {{.ExecutionStatus}}'.
+- The entries are sorted from the newest execution to the oldest one, and --limit keeps the newest ones after filtering.
+- The filters are sent to the server (since, until and status query parameters) and applied again by the CLI, for the servers ignoring them. The servers paginating the history are read page by page (limit and offset), the others return it at once; while the pages go from the newest execution to the oldest one, the paging stops once --limit entries match or an execution started before --since is reached. --status ignores the case and the STATUS_ prefix (fail matches STATUS_FAIL), --since and --until bound the start time and accept RFC 3339 times, dates in UTC or durations before now (30m, 12h, 7d).
+- --project-wide lists the workers of the project and merges their histories, it needs a project key (--project-key or the manifest) and no worker key.
+- Several worker keys may be passed at once, their histories are merged like with --project-wide.
+- --follow prints the --limit newest entries (10 by default), then polls the history every --poll-interval-ms (5s by default) and prints the new entries, oldest first, until Ctrl-C. The entries are deduplicated by trace ID; a running execution is printed again once it ends. The output is one colored line per entry by default, --format json prints JSON Lines, and csv, text and --template are also supported, not table, aligned nor yaml. A failed poll is logged and retried at the next interval. After the first poll, only the executions started since the oldest running one, or the newest completed one, are requested, whatever --status.
 - History retention is controlled by the server and may be limited.
+- The command refuses to run (exit code 18) when the server reports the history as disabled, or when --project-key is used with an Artifactory version older than the one required for projects.
 
-Related: jf worker execute, jf worker deploy, jf worker test-run`,
+Related: jf worker execute, jf worker wait, jf worker deploy, jf worker test-run`,
 		Aliases:          []string{"exec-hist", "eh"},
-		SupportedFormats: []format.OutputFormat{format.Json, format.Table},
+		SupportedFormats: append(slices.Clone(common.TextOutputFormats), common.FormatAligned),
 		DefaultFormat:    format.Json,
-		Flags: []components.Flag{
+		Flags: slices.Concat([]components.Flag{
 			plugins_common.GetServerIdFlag(),
 			model.GetTimeoutFlag(),
 			model.GetProjectKeyFlag(),
+			model.GetTemplateFlag(),
+			model.GetQueryFlag(),
 			components.NewBoolFlag(
-				"with-test-runs",
+				flagHistoryWithTestRuns,
 				"Whether to include test-runs entries.",
 				components.WithBoolDefaultValue(false),
 			),
-		},
+			components.NewStringFlag(flagHistoryStatus, "Only the executions with one of these comma-separated statuses, e.g. STATUS_FAIL or fail.", components.WithStrDefaultValue("")),
+			components.NewStringFlag(flagHistorySince, "Only the executions started at or after this time: RFC 3339, a date, or a duration before now such as 12h or 7d.", components.WithStrDefaultValue("")),
+			components.NewStringFlag(flagHistoryUntil, "Only the executions started before this time: RFC 3339, a date, or a duration before now such as 12h or 7d.", components.WithStrDefaultValue("")),
+			components.NewStringFlag(flagHistoryVersion, "Only the executions of this version of the worker.", components.WithStrDefaultValue("")),
+			components.NewStringFlag(flagHistoryTriggeredBy, "Only the executions triggered by this user or event, ignoring the case.", components.WithStrDefaultValue("")),
+			components.NewStringFlag(flagHistoryLimit, "The maximum number of entries printed, the newest ones; 0 for all of them.", components.WithIntDefaultValue(0)),
+			components.NewBoolFlag(flagHistoryProjectWide, "Read the history of every worker of the project instead of a single worker.", components.WithBoolDefaultValue(false)),
+			components.NewBoolFlag(flagHistoryFollow, "Poll the history and print the new entries as they come, until Ctrl-C.", components.WithBoolDefaultValue(false)),
+			components.NewStringFlag(flagPollInterval, "With --follow, the delay between two polls of the history in milliseconds.", components.WithIntDefaultValue(followPollIntervalMs)),
+		}, model.GetMetadataFlags(), model.GetHTTPClientFlags()),
 		Arguments: []components.Argument{
-			model.GetWorkerKeyArgument(),
+			{
+				Name:        "worker-key",
+				Optional:    true,
+				Description: "The keys of the workers, separated by spaces. If not provided the worker will be read from the `manifest.json` in the current directory.",
+			},
 		},
-		Action: func(c *components.Context) error {
-			outputFormat, err := c.GetOutputFormat()
-			if err != nil {
-				return err
-			}
+		Action: runExecutionHistoryCommand,
+	}
+}
 
-			var contentHandler func([]byte) error
-			switch outputFormat {
-			case format.Json:
-				contentHandler = common.PrintJSON
-			case format.Table:
-				contentHandler = printExecutionHistoryTable
-			}
+func runExecutionHistoryCommand(c *components.Context) error {
+	output, err := common.NewOutput(c)
+	if err != nil {
+		return err
+	}
 
-			workerKey, projectKey, err := common.ExtractProjectAndKeyFromCommandContext(c, c.Arguments, 1, false)
-			if err != nil {
-				return err
-			}
+	filter, err := parseExecutionHistoryFilter(c)
+	if err != nil {
+		return err
+	}
 
-			server, err := model.GetServerDetails(c)
-			if err != nil {
-				return err
-			}
+	limit, err := c.GetIntFlagValue(flagHistoryLimit)
+	if err != nil || limit < 0 {
+		return fmt.Errorf("invalid --%s provided, expected a positive number", flagHistoryLimit)
+	}
 
-			query := map[string]string{
-				"workerKey": workerKey,
-			}
+	follow := c.GetBoolFlagValue(flagHistoryFollow)
+	var pollInterval int
+	if follow {
+		if pollInterval, err = c.GetIntFlagValue(flagPollInterval); err != nil || pollInterval < 1 {
+			return fmt.Errorf("invalid --%s provided, expected a positive number of milliseconds", flagPollInterval)
+		}
+		// The entries are printed as they come, the text lines are read by humans first
+		if !slices.Contains(c.FlagsUsed, format.FlagName) && c.GetStringFlagValue(model.FlagQuery) == "" && c.GetStringFlagValue(model.FlagTemplate) == "" {
+			output.Format = common.FormatText
+		}
+		if !slices.Contains(common.LineFormats, output.Format) {
+			return fmt.Errorf("--%s prints an entry per line, the %s format is not supported", flagHistoryFollow, output.Format)
+		}
+	}
 
-			if c.GetBoolFlagValue("with-test-runs") {
-				query["showTestRun"] = "true"
-			}
+	server, err := common.GetServerDetails(c)
+	if err != nil {
+		return err
+	}
+
+	workerKeys, projectKey, err := getExecutionHistoryWorkers(c, server)
+	if err != nil {
+		return err
+	}
+
+	options := workerclient.ExecutionHistoryOptions{
+		ProjectKey:  projectKey,
+		ShowTestRun: c.GetBoolFlagValue(flagHistoryWithTestRuns),
+	}
+
+	if follow {
+		follower := &historyFollower{
+			c:           c,
+			server:      server,
+			workerKeys:  workerKeys,
+			options:     options,
+			filter:      filter,
+			output:      output,
+			interval:    time.Duration(pollInterval) * time.Millisecond,
+			initialSize: cmp.Or(limit, followInitialEntriesCount),
+		}
+		return follower.follow()
+	}
+
+	entries, err := common.FetchExecutionHistories(c, server, workerKeys, options, filter, limit)
+	if err != nil {
+		return err
+	}
 
-			return common.CallWorkerAPI(c, common.APICallParams{
-				Method:      http.MethodGet,
-				ServerURL:   server.GetUrl(),
-				ServerToken: server.GetAccessToken(),
-				OkStatuses:  []int{http.StatusOK},
-				ProjectKey:  projectKey,
-				Query:       query,
-				Path:        []string{"execution_history"},
-				OnContent:   contentHandler,
-			})
+	entries = filter.Apply(entries)
+	common.SortExecutionHistory(entries)
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	return output.Print(&common.Result{
+		Value: entries,
+		Table: newExecutionHistoryTable(entries),
+		Text: func() error {
+			for _, entry := range entries {
+				if err := printExecutionHistoryLine(entry); err != nil {
+					return err
+				}
+			}
+			return nil
 		},
+	})
+}
+
+// parseExecutionHistoryFilter reads the filter flags of the history commands.
+func parseExecutionHistoryFilter(c *components.Context) (*common.ExecutionHistoryFilter, error) {
+	filter := &common.ExecutionHistoryFilter{
+		Version:     c.GetStringFlagValue(flagHistoryVersion),
+		TriggeredBy: c.GetStringFlagValue(flagHistoryTriggeredBy),
+	}
+
+	for _, status := range strings.Split(c.GetStringFlagValue(flagHistoryStatus), ",") {
+		if status = strings.TrimSpace(status); status != "" {
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+
+	now := time.Now()
+	var err error
+	if since := c.GetStringFlagValue(flagHistorySince); since != "" {
+		if filter.Since, err = common.ParseHistoryTime(since, now); err != nil {
+			return nil, fmt.Errorf("--%s: %w", flagHistorySince, err)
+		}
+	}
+	if until := c.GetStringFlagValue(flagHistoryUntil); until != "" {
+		if filter.Until, err = common.ParseHistoryTime(until, now); err != nil {
+			return nil, fmt.Errorf("--%s: %w", flagHistoryUntil, err)
+		}
 	}
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && !filter.Since.Before(filter.Until) {
+		return nil, fmt.Errorf("--%s must be before --%s", flagHistorySince, flagHistoryUntil)
+	}
+
+	return filter, nil
 }
 
-func printExecutionHistoryTable(responseBytes []byte) error {
-	var entries []executionHistoryEntry
-	if err := json.Unmarshal(responseBytes, &entries); err != nil {
-		return err
+// getExecutionHistoryWorkers returns the workers of which the history is read: the workers given as arguments, the one of the manifest,
+// or every worker of the project with --project-wide.
+func getExecutionHistoryWorkers(c *components.Context, server *common.Server) ([]string, string, error) {
+	if len(c.Arguments) > 1 && !c.GetBoolFlagValue(flagHistoryProjectWide) {
+		projectKey := getExecutionHistoryProjectKey(c)
+		if err := common.CheckCapabilities(c, server, common.RequireExecutionHistory(), common.RequireProjectSupport(projectKey)); err != nil {
+			return nil, "", err
+		}
+		return slices.Compact(slices.Sorted(slices.Values(c.Arguments))), projectKey, nil
+	}
+
+	if !c.GetBoolFlagValue(flagHistoryProjectWide) {
+		workerKey, projectKey, err := common.ExtractProjectAndKeyFromCommandContext(c, c.Arguments, 0, false)
+		if err != nil {
+			return nil, "", err
+		}
+		if err = common.CheckCapabilities(c, server, common.RequireExecutionHistory(), common.RequireProjectSupport(projectKey)); err != nil {
+			return nil, "", err
+		}
+		return []string{workerKey}, projectKey, nil
+	}
+
+	if len(c.Arguments) > 0 {
+		return nil, "", fmt.Errorf("a worker key cannot be combined with --%s, the history of every worker of the project is read", flagHistoryProjectWide)
+	}
+
+	projectKey := getExecutionHistoryProjectKey(c)
+	if projectKey == "" {
+		return nil, "", fmt.Errorf("--%s requires a project, pass --%s or run the command in the directory of a worker of the project", flagHistoryProjectWide, model.FlagProjectKey)
+	}
+
+	if err := common.CheckCapabilities(c, server, common.RequireExecutionHistory(), common.RequireProjectSupport(projectKey)); err != nil {
+		return nil, "", err
+	}
+
+	workers, err := common.CallWorkerClient(c, server, func(ctx context.Context, client *workerclient.Client) ([]*model.WorkerDetails, error) {
+		return client.ListWorkers(ctx, workerclient.ListWorkersOptions{ProjectKey: projectKey})
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	workerKeys := make([]string, len(workers))
+	for i, worker := range workers {
+		workerKeys[i] = worker.Key
 	}
+	log.Debug(fmt.Sprintf("Reading the execution history of the %d workers of %s", len(workerKeys), projectKey))
 
-	writer := common.NewCsvWriter()
+	return workerKeys, projectKey, nil
+}
+
+// getExecutionHistoryProjectKey returns the project of --project-key, else the one of the manifest when there is one.
+func getExecutionHistoryProjectKey(c *components.Context) string {
+	if projectKey := c.GetStringFlagValue(model.FlagProjectKey); projectKey != "" {
+		return projectKey
+	}
+	if manifest, err := common.ReadManifest(); err == nil {
+		return manifest.ProjectKey
+	}
+	return ""
+}
 
-	if err := writer.Write([]string{
+func newExecutionHistoryTable(entries []*model.ExecutionHistoryEntry) *common.Table {
+	table := &common.Table{Headers: []string{
 		"Worker Key",
 		"Worker Type",
 		"Project Key",
@@ -135,14 +297,12 @@ func printExecutionHistoryTable(responseBytes []byte) error {
 		"Test Run",
 		"Executed Version",
 		"Trace ID",
-	}); err != nil {
-		return err
-	}
+	}, CsvAsTable: true}
 
 	for _, entry := range entries {
 		startedAt := time.UnixMilli(entry.StartTimeMillis).UTC().Format(time.RFC3339)
 		endedAt := time.UnixMilli(entry.EndTimeMillis).UTC().Format(time.RFC3339)
-		if err := writer.Write([]string{
+		table.Rows = append(table.Rows, []string{
 			entry.WorkerKey,
 			entry.WorkerType,
 			entry.WorkerProjectKey,
@@ -153,10 +313,8 @@ func printExecutionHistoryTable(responseBytes []byte) error {
 			fmt.Sprint(entry.TestRun),
 			entry.ExecutedVersion,
 			entry.TraceID,
-		}); err != nil {
-			return err
-		}
+		})
 	}
-	writer.Flush()
-	return writer.Error()
+
+	return table
 }
@@ -6,6 +6,7 @@ package commands
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"os"
 	"strings"
 	"testing"
@@ -96,6 +97,26 @@ func TestExecutionHistory(t *testing.T) {
 			serverStub:  common.NewServerStub(t).WithDelay(2 * time.Second).WithGetExecutionHistoryEndpoint(),
 			assert:      common.AssertOutputError("request timed out after 500ms"),
 		},
+		{
+			name: "fails if the history is disabled",
+			serverStub: common.NewServerStub(t).
+				WithCustomOptionsEndpoint(func(options *model.OptionsMetadata) {
+					options.IsHistoryEnabled = false
+				}).
+				WithArtifactoryVersionEndpoint("7.104.2").
+				WithGetExecutionHistoryEndpoint(),
+			commandArgs: []string{"a-worker"},
+			assert:      common.AssertOutputErrorRegexp(`^the execution history is not enabled on this server \(Artifactory 7\.104\.2\)\nHint: `),
+		},
+		{
+			name: "fails if projects are not supported",
+			serverStub: common.NewServerStub(t).
+				WithOptionsEndpoint().
+				WithArtifactoryVersionEndpoint("7.90.1").
+				WithGetExecutionHistoryEndpoint(),
+			commandArgs: []string{"--" + model.FlagProjectKey, "my-project", "a-worker"},
+			assert:      common.AssertOutputErrorRegexp(`^the server does not support projects, Artifactory 7\.95\.0 or later is required \(found 7\.90\.1\)`),
+		},
 		{
 			name:        "fails if invalid timeout",
 			commandArgs: []string{"--" + model.FlagTimeout, "abc", `{}`},
@@ -207,6 +228,21 @@ func TestWorkerExecutionHistory_FormatTable(t *testing.T) {
 	assert.False(t, json.Valid([]byte(strings.TrimSpace(outputStr))), "table output should not be JSON, got: %s", outputStr)
 	assert.Contains(t, outputStr, "OK", "expected execution status in table output, got: %s", outputStr)
 	assert.Contains(t, outputStr, testExecHistoryWorkerKey, "expected worker key in table output, got: %s", outputStr)
+	assert.True(t, strings.HasPrefix(outputStr, "Worker Key,Worker Type,Project Key,Status,"), "table output should be CSV, got: %s", outputStr)
+}
+
+func TestWorkerExecutionHistory_FormatAligned(t *testing.T) {
+	_, workerHistory := testExecutionHistoryWorkerHistory(t)
+	runCmd := setupExecutionHistoryFormatTest(t, workerHistory)
+
+	var out bytes.Buffer
+	common.SetCliOut(&out)
+	t.Cleanup(func() { common.SetCliOut(os.Stdout) })
+
+	require.NoError(t, runCmd("worker", "execution-history", "--"+format.FlagName, string(common.FormatAligned)))
+	outputStr := out.String()
+	assert.NotContains(t, outputStr, ",", "aligned output should not be CSV, got: %s", outputStr)
+	assert.Contains(t, outputStr, testExecHistoryWorkerKey, "expected worker key in aligned output, got: %s", outputStr)
 }
 
 func TestWorkerExecutionHistory_FormatDefault(t *testing.T) {
@@ -229,3 +265,143 @@ func TestWorkerExecutionHistory_FormatUnsupported(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "only the following output formats are supported")
 }
+
+func TestWorkerExecutionHistory_Filters(t *testing.T) {
+	startedAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	newEntry := func(traceID string, status string, hoursAfter int, version string, triggeredBy string) *common.ExecutionHistoryEntryStub {
+		return &common.ExecutionHistoryEntryStub{
+			WorkerKey:       testExecHistoryWorkerKey,
+			ExecutionStatus: status,
+			StartTimeMillis: startedAt.Add(time.Duration(hoursAfter) * time.Hour).UnixMilli(),
+			ExecutedVersion: version,
+			TriggeredBy:     triggeredBy,
+			TraceID:         traceID,
+		}
+	}
+	history := common.ExecutionHistoryStub{
+		newEntry("a", "STATUS_SUCCESS", 0, "1.0.0", "admin"),
+		newEntry("c", "STATUS_FAIL", 2, "1.1.0", "scheduler"),
+		newEntry("b", "STATUS_FAIL", 1, "1.0.0", "admin"),
+		newEntry("d", "STATUS_SUCCESS", 3, "1.1.0", "admin"),
+	}
+	runCmd := setupExecutionHistoryFormatTest(t, history)
+
+	tests := []struct {
+		name   string
+		args   []string
+		traces []string
+	}{
+		{name: "sorted newest first", traces: []string{"d", "c", "b", "a"}},
+		{name: "status", args: []string{"--" + flagHistoryStatus, "fail"}, traces: []string{"c", "b"}},
+		{name: "statuses", args: []string{"--" + flagHistoryStatus, "STATUS_FAIL, STATUS_SUCCESS"}, traces: []string{"d", "c", "b", "a"}},
+		{name: "time range", args: []string{"--" + flagHistorySince, "2024-01-15T11:00:00Z", "--" + flagHistoryUntil, "2024-01-15T13:00:00Z"}, traces: []string{"c", "b"}},
+		{name: "version", args: []string{"--" + flagHistoryVersion, "1.0.0"}, traces: []string{"b", "a"}},
+		{name: "triggered by", args: []string{"--" + flagHistoryTriggeredBy, "Admin", "--" + flagHistoryStatus, "success"}, traces: []string{"d", "a"}},
+		{name: "limit keeps the newest", args: []string{"--" + flagHistoryLimit, "3", "--" + flagHistoryTriggeredBy, "admin"}, traces: []string{"d", "b", "a"}},
+		{name: "no match", args: []string{"--" + flagHistorySince, "1h"}, traces: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			common.SetCliOut(&out)
+			t.Cleanup(func() { common.SetCliOut(os.Stdout) })
+
+			require.NoError(t, runCmd(append([]string{"worker", "execution-history"}, tt.args...)...))
+
+			var entries []*model.ExecutionHistoryEntry
+			require.NoError(t, json.Unmarshal(out.Bytes(), &entries))
+			traces := []string{}
+			for _, entry := range entries {
+				traces = append(traces, entry.TraceID)
+			}
+			assert.Equal(t, tt.traces, traces)
+		})
+	}
+
+	err := runCmd("worker", "execution-history", "--"+flagHistorySince, "yesterday")
+	assert.ErrorContains(t, err, "--since: invalid time yesterday")
+
+	err = runCmd("worker", "execution-history", "--"+flagHistorySince, "1h", "--"+flagHistoryUntil, "2h")
+	assert.EqualError(t, err, "--since must be before --until")
+
+	err = runCmd("worker", "execution-history", "--"+flagHistoryLimit, "-1")
+	assert.EqualError(t, err, "invalid --limit provided, expected a positive number")
+}
+
+func TestWorkerExecutionHistory_Pagination(t *testing.T) {
+	history := common.ExecutionHistoryStub{}
+	for i := range 2*common.ExecutionHistoryPageSize + 50 {
+		history = append(history, &common.ExecutionHistoryEntryStub{WorkerKey: "my-worker", ExecutionStatus: "STATUS_SUCCESS", StartTimeMillis: int64(i), TraceID: fmt.Sprint(i)})
+	}
+
+	for _, paginated := range []bool{true, false} {
+		t.Run(fmt.Sprintf("paginated %t", paginated), func(t *testing.T) {
+			serverStub := common.NewServerStub(t).
+				WithWorkerExecutionHistory("my-worker", history).
+				WithGetExecutionHistoryEndpoint()
+			if paginated {
+				serverStub.WithExecutionHistoryPagination()
+			}
+			common.NewMockWorkerServer(t, serverStub)
+			common.PrepareWorkerDirForTest(t)
+
+			var out bytes.Buffer
+			common.SetCliOut(&out)
+			t.Cleanup(func() { common.SetCliOut(os.Stdout) })
+
+			runCmd := common.CreateCliRunner(t, GetShowExecutionHistoryCommand())
+			require.NoError(t, runCmd("worker", "execution-history", "--"+flagHistoryLimit, "1000", "my-worker"))
+
+			var entries []*model.ExecutionHistoryEntry
+			require.NoError(t, json.Unmarshal(out.Bytes(), &entries))
+			require.Len(t, entries, len(history))
+			assert.Equal(t, "249", entries[0].TraceID)
+			assert.Equal(t, "0", entries[len(entries)-1].TraceID)
+		})
+	}
+}
+
+func TestWorkerExecutionHistory_ProjectWide(t *testing.T) {
+	serverStub := common.NewServerStub(t).
+		WithStatefulWorkers().
+		WithWorkers(
+			&model.WorkerDetails{Key: "worker-a", ProjectKey: "my-project"},
+			&model.WorkerDetails{Key: "worker-b", ProjectKey: "my-project"},
+			&model.WorkerDetails{Key: "global-worker"},
+		).
+		WithProjectKey("my-project").
+		WithGetAllEndpoint().
+		WithWorkerExecutionHistory("worker-a", common.ExecutionHistoryStub{
+			{WorkerKey: "worker-a", ExecutionStatus: "STATUS_SUCCESS", StartTimeMillis: 1, TraceID: "a1"},
+			{WorkerKey: "worker-a", ExecutionStatus: "STATUS_FAIL", StartTimeMillis: 3, TraceID: "a3"},
+		}).
+		WithWorkerExecutionHistory("worker-b", common.ExecutionHistoryStub{
+			{WorkerKey: "worker-b", ExecutionStatus: "STATUS_FAIL", StartTimeMillis: 2, TraceID: "b2"},
+		}).
+		WithWorkerExecutionHistory("global-worker", common.ExecutionHistoryStub{
+			{WorkerKey: "global-worker", ExecutionStatus: "STATUS_FAIL", StartTimeMillis: 4, TraceID: "g4"},
+		}).
+		WithGetExecutionHistoryEndpoint()
+	common.NewMockWorkerServer(t, serverStub)
+	common.PrepareWorkerDirForTest(t)
+
+	var out bytes.Buffer
+	common.SetCliOut(&out)
+	t.Cleanup(func() { common.SetCliOut(os.Stdout) })
+
+	runCmd := common.CreateCliRunner(t, GetShowExecutionHistoryCommand())
+	require.NoError(t, runCmd("worker", "execution-history", "--"+flagHistoryProjectWide, "--"+model.FlagProjectKey, "my-project", "--"+flagHistoryStatus, "fail", "--"+format.FlagName, "csv"))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[1], "worker-a")
+	assert.Contains(t, lines[1], "a3")
+	assert.Contains(t, lines[2], "worker-b")
+
+	err := runCmd("worker", "execution-history", "--"+flagHistoryProjectWide)
+	assert.EqualError(t, err, "--project-wide requires a project, pass --project-key or run the command in the directory of a worker of the project")
+
+	err = runCmd("worker", "execution-history", "--"+flagHistoryProjectWide, "--"+model.FlagProjectKey, "my-project", "worker-a")
+	assert.EqualError(t, err, "a worker key cannot be combined with --project-wide, the history of every worker of the project is read")
+}
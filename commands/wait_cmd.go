@@ -0,0 +1,180 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/common/format"
+	plugins_common "github.com/jfrog/jfrog-cli-core/v2/plugins/common"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+
+	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+	"github.com/jfrog/jfrog-cli-platform-services/workerclient"
+)
+
+const (
+	flagPollInterval   = "poll-interval-ms"
+	flagWaitMaxWait    = "max-wait-ms"
+	waitPollIntervalMs = 1000
+	waitMaxWaitMs      = 10 * 60 * 1000
+)
+
+// waitMaxPollInterval caps the backoff, so that the end of a long execution is not noticed too late
+var waitMaxPollInterval = 30 * time.Second
+
+func GetWaitCommand() components.Command {
+	return components.Command{
+		Name:        "wait",
+		Description: "Wait for the end of a worker execution.",
+		AIDescription: `Poll the execution history of a worker until the execution with a trace ID reaches a final status, then print its history entry. The exit code tells whether the execution succeeded, so that a script can block on an execution it did not start itself, e.g. one triggered by an event.
+
+When to use:
+- Waiting in a CI job for the end of an execution still running in 'jf worker execution-history'.
+- Blocking a script until a known execution, e.g. a trace ID found in 'jf worker execution-history', is done.
+
+Prerequisites:
+- Configured server (jf c add or jf login) with read access to the execution history, and a server recording it.
+- The key of the worker, as argument or from the manifest.json of the current directory, since the history is read per worker.
+
+Common patterns:
+  $ jf worker wait my-worker 5f2c0a7e9b1d4c3a
+  $ jf worker wait 5f2c0a7e9b1d4c3a              # worker name read from manifest.json
+  $ jf worker wait my-worker "$(jf worker execution-history my-worker --status running --limit 1 --template '{{.TraceID}}')"
+  $ jf worker wait my-worker 5f2c0a7e9b1d4c3a --max-wait-ms 3600000 --format table
+  $ jf worker wait my-worker 5f2c0a7e9b1d4c3a --query '.executionStatus'
+
+Gotchas:
+- The history is checked after --poll-interval-ms (1s by default), then the delay grows by half at each check, up to 30s; the progress is logged on the standard error.
+- The command exits with 0 when the execution succeeded, 1 when it ended with another status (the entry is printed in both cases), and 15 when it did not end within --max-wait-ms (10 minutes by default). Ctrl-C stops the wait, not the execution.
+- An execution may take a few seconds to appear in the history; a trace ID that never appears, e.g. one of another worker, only fails at --max-wait-ms.
+- --timeout-ms and --retries apply to each check of the history, not to the whole wait. A check still failing after the retries with a timeout, a server error or a network error is logged and the wait goes on; the other errors, e.g. 401, 403 or 404, end it.
+- The history is read page by page until the execution is found, then only from the start time of the execution, so that an execution is found in a long history.
+
+Related: jf worker execute, jf worker execution-history`,
+		SupportedFormats: common.OutputFormats,
+		DefaultFormat:    format.Json,
+		Flags: slices.Concat([]components.Flag{
+			plugins_common.GetServerIdFlag(),
+			model.GetTimeoutFlag(),
+			model.GetProjectKeyFlag(),
+			components.NewStringFlag(flagPollInterval, "The delay before the first check of the execution history in milliseconds, it grows at each check.", components.WithIntDefaultValue(waitPollIntervalMs)),
+			components.NewStringFlag(flagWaitMaxWait, "How long to wait for the end of the execution in milliseconds.", components.WithIntDefaultValue(waitMaxWaitMs)),
+			model.GetTemplateFlag(),
+			model.GetQueryFlag(),
+		}, model.GetMetadataFlags(), model.GetHTTPClientFlags()),
+		Arguments: []components.Argument{
+			model.GetWorkerKeyArgument(),
+			{Name: "trace-id", Description: "The trace ID of the execution, as found in 'jf worker execution-history'."},
+		},
+		Action: runWaitCommand,
+	}
+}
+
+func runWaitCommand(c *components.Context) error {
+	output, err := common.NewOutput(c)
+	if err != nil {
+		return err
+	}
+
+	if len(c.Arguments) == 0 {
+		return errors.New("missing trace ID argument")
+	}
+	traceID := c.Arguments[len(c.Arguments)-1]
+
+	pollInterval, err := c.GetIntFlagValue(flagPollInterval)
+	if err != nil || pollInterval < 1 {
+		return fmt.Errorf("invalid --%s provided, expected a positive number of milliseconds", flagPollInterval)
+	}
+
+	maxWait, err := c.GetIntFlagValue(flagWaitMaxWait)
+	if err != nil || maxWait < 1 {
+		return fmt.Errorf("invalid --%s provided, expected a positive number of milliseconds", flagWaitMaxWait)
+	}
+
+	workerKey, projectKey, err := common.ExtractProjectAndKeyFromCommandContext(c, c.Arguments, 1, false)
+	if err != nil {
+		return err
+	}
+
+	server, err := common.GetServerDetails(c)
+	if err != nil {
+		return err
+	}
+
+	if err = common.CheckCapabilities(c, server, common.RequireExecutionHistory(), common.RequireProjectSupport(projectKey)); err != nil {
+		return err
+	}
+
+	entry, err := waitForExecution(c, server, workerKey, projectKey, traceID,
+		time.Duration(pollInterval)*time.Millisecond, time.Duration(maxWait)*time.Millisecond)
+	if err != nil {
+		return err
+	}
+
+	if err = output.Print(&common.Result{Value: entry, Table: newExecutionHistoryTable([]*model.ExecutionHistoryEntry{entry})}); err != nil {
+		return err
+	}
+
+	if !entry.Succeeded() {
+		return common.ErrorAlreadyReported(fmt.Errorf("the execution %s of %s ended with %s", traceID, workerKey, entry.ExecutionStatus))
+	}
+	return nil
+}
+
+// waitForExecution polls the execution history of a worker until the entry of an execution has a final status.
+// The delay between two checks grows by half at each check, up to waitMaxPollInterval.
+// The history is read page by page until the execution is found, then from its start time only.
+// A check failing with a transient error (timeout, server or network error) is logged and the wait goes on, the other errors end it.
+func waitForExecution(c model.IntFlagProvider, server *common.Server, workerKey string, projectKey string, traceID string, pollInterval time.Duration, maxWait time.Duration) (*model.ExecutionHistoryEntry, error) {
+	ctx, cancel := interruptContext()
+	defer cancel()
+
+	deadline := time.Now().Add(maxWait)
+	status := "not in the execution history yet"
+	filter := &common.ExecutionHistoryFilter{}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("the wait was interrupted, the execution %s was %s", traceID, status)
+		case <-time.After(min(pollInterval, time.Until(deadline))):
+		}
+
+		entries, err := common.FetchExecutionHistory(c, server, workerclient.ExecutionHistoryOptions{WorkerKey: workerKey, ProjectKey: projectKey}, filter, 0)
+		if err != nil && !isTransientError(err) {
+			return nil, err
+		}
+		if err != nil {
+			log.Warn(fmt.Sprintf("Cannot read the execution history of %s: %s", workerKey, err))
+		}
+
+		for _, entry := range entries {
+			if entry.TraceID != traceID {
+				continue
+			}
+			if entry.Completed() {
+				return entry, nil
+			}
+			filter.Since = time.UnixMilli(entry.StartTimeMillis)
+			status = entry.ExecutionStatus
+			if status == "" {
+				status = "not started"
+			}
+		}
+
+		if !time.Now().Before(deadline) {
+			return nil, common.TimeoutError("the execution %s did not end within %s, it was %s", traceID, maxWait, status)
+		}
+
+		pollInterval = min(pollInterval*3/2, waitMaxPollInterval)
+		log.Info(fmt.Sprintf("The execution %s is %s, next check in %s", traceID, status, min(pollInterval, time.Until(deadline)).Round(time.Millisecond)))
+	}
+}
+
+// isTransientError tells whether a failed call may succeed later: the timeouts, the server errors and the network errors.
+func isTransientError(err error) bool {
+	return slices.Contains([]int{common.ExitCodeTimeout, common.ExitCodeServerError, common.ExitCodeNetworkError}, common.ExitCodeOf(err))
+}
@@ -0,0 +1,209 @@
+//go:build test
+// +build test
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jfrog/jfrog-cli-platform-services/commands/common"
+	"github.com/jfrog/jfrog-cli-platform-services/model"
+)
+
+func TestWait(t *testing.T) {
+	running := &common.ExecutionHistoryEntryStub{WorkerKey: "my-worker", ExecutionStatus: "STATUS_RUNNING", TraceID: "trace-1"}
+	succeeded := &common.ExecutionHistoryEntryStub{WorkerKey: "my-worker", ExecutionStatus: "STATUS_SUCCESS", TraceID: "trace-1", ExecutedVersion: "1.0.0"}
+	failed := &common.ExecutionHistoryEntryStub{WorkerKey: "my-worker", ExecutionStatus: "STATUS_FAIL", TraceID: "trace-1"}
+	other := &common.ExecutionHistoryEntryStub{WorkerKey: "my-worker", ExecutionStatus: "STATUS_FAIL", TraceID: "trace-0"}
+
+	tests := []struct {
+		name        string
+		histories   []common.ExecutionHistoryStub
+		commandArgs []string
+		wantEntry   *common.ExecutionHistoryEntryStub
+		wantErr     string
+		wantExit    int
+		// The number of requests of the history
+		wantRequests int
+	}{
+		{
+			name:         "waits for the end of a successful execution",
+			histories:    []common.ExecutionHistoryStub{{other}, {other, running}, {other, running}, {other, succeeded}},
+			commandArgs:  []string{"my-worker", "trace-1"},
+			wantEntry:    succeeded,
+			wantRequests: 4,
+		},
+		{
+			name:         "fails on a failed execution",
+			histories:    []common.ExecutionHistoryStub{{running}, {failed}},
+			commandArgs:  []string{"my-worker", "trace-1"},
+			wantEntry:    failed,
+			wantErr:      "the execution trace-1 of my-worker ended with STATUS_FAIL",
+			wantExit:     common.ExitCodeError,
+			wantRequests: 2,
+		},
+		{
+			name:        "fails when the execution does not end in time",
+			histories:   []common.ExecutionHistoryStub{{running}},
+			commandArgs: []string{"--" + flagWaitMaxWait, "100", "my-worker", "trace-1"},
+			wantErr:     "the execution trace-1 did not end within 100ms, it was STATUS_RUNNING",
+			wantExit:    common.ExitCodeTimeout,
+		},
+		{
+			name:        "fails when the execution is not found in time",
+			histories:   []common.ExecutionHistoryStub{{other}},
+			commandArgs: []string{"--" + flagWaitMaxWait, "50", "my-worker", "trace-1"},
+			wantErr:     "the execution trace-1 did not end within 50ms, it was not in the execution history yet",
+			wantExit:    common.ExitCodeTimeout,
+		},
+		{
+			name:        "fails without trace ID",
+			commandArgs: []string{},
+			wantErr:     "missing trace ID argument",
+			wantExit:    common.ExitCodeError,
+		},
+		{
+			name:        "fails with an invalid interval",
+			commandArgs: []string{"--" + flagPollInterval, "0", "my-worker", "trace-1"},
+			wantErr:     "invalid --poll-interval-ms provided, expected a positive number of milliseconds",
+			wantExit:    common.ExitCodeError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serverStub := common.NewServerStub(t).
+				WithQueryParam("workerKey", "my-worker", common.EndpointExecutionHistory).
+				WithGetExecutionHistoryEndpoint()
+			if len(tt.histories) > 0 {
+				serverStub.WithWorkerExecutionHistorySequence("my-worker", tt.histories...)
+			}
+			common.NewMockWorkerServer(t, serverStub)
+			common.PrepareWorkerDirForTest(t)
+
+			maxPollInterval := waitMaxPollInterval
+			waitMaxPollInterval = 20 * time.Millisecond
+			t.Cleanup(func() { waitMaxPollInterval = maxPollInterval })
+
+			var out bytes.Buffer
+			common.SetCliOut(&out)
+			t.Cleanup(func() { common.SetCliOut(os.Stdout) })
+
+			runCmd := common.CreateCliRunner(t, GetWaitCommand())
+			err := runCmd(append([]string{"worker", "wait", "--" + flagPollInterval, "10"}, tt.commandArgs...)...)
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Equal(t, tt.wantExit, common.ExitCodeOf(err))
+			} else {
+				require.NoError(t, err)
+			}
+
+			if tt.wantEntry != nil {
+				var got common.ExecutionHistoryEntryStub
+				require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+				assert.Equal(t, tt.wantEntry, &got)
+			} else {
+				assert.Empty(t, out.String())
+			}
+
+			if tt.wantRequests > 0 {
+				assert.Equal(t, tt.wantRequests, serverStub.ExecutionHistoryRequests("my-worker"))
+			}
+		})
+	}
+}
+
+func TestWait_Interrupted(t *testing.T) {
+	serverStub := common.NewServerStub(t).
+		WithWorkerExecutionHistorySequence("my-worker", common.ExecutionHistoryStub{{WorkerKey: "my-worker", ExecutionStatus: "STATUS_RUNNING", TraceID: "trace-1"}}).
+		WithGetExecutionHistoryEndpoint()
+	common.NewMockWorkerServer(t, serverStub)
+	common.PrepareWorkerDirForTest(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	newContext := interruptContext
+	interruptContext = func() (context.Context, context.CancelFunc) { return ctx, cancel }
+	t.Cleanup(func() { interruptContext = newContext })
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	runCmd := common.CreateCliRunner(t, GetWaitCommand())
+	err := runCmd("worker", "wait", "--"+flagPollInterval, "10", "my-worker", "trace-1")
+	assert.EqualError(t, err, "the wait was interrupted, the execution trace-1 was STATUS_RUNNING")
+}
+
+func TestWaitForExecution(t *testing.T) {
+	running := &model.ExecutionHistoryEntry{WorkerKey: "my-worker", ExecutionStatus: "STATUS_RUNNING", StartTimeMillis: 2000, TraceID: "trace-1"}
+	succeeded := &model.ExecutionHistoryEntry{WorkerKey: "my-worker", ExecutionStatus: "STATUS_SUCCESS", StartTimeMillis: 2000, TraceID: "trace-1"}
+
+	tests := []struct {
+		name      string
+		responses []any
+		wantErr   string
+		wantExit  int
+		// The since query parameter of each request, empty when not sent
+		wantSince []string
+	}{
+		{
+			name:      "goes on after a transient error",
+			responses: []any{http.StatusServiceUnavailable, []*model.ExecutionHistoryEntry{running}, http.StatusBadGateway, []*model.ExecutionHistoryEntry{succeeded}},
+			wantSince: []string{"", "", "2000", "2000"},
+		},
+		{
+			name:      "ends on a non transient error",
+			responses: []any{[]*model.ExecutionHistoryEntry{running}, http.StatusForbidden},
+			wantErr:   "returned an unexpected status code 403",
+			wantExit:  common.ExitCodeForbidden,
+			wantSince: []string{"", "2000"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var queries []url.Values
+			server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				queries = append(queries, req.URL.Query())
+				switch response := tt.responses[min(len(queries), len(tt.responses))-1].(type) {
+				case int:
+					res.WriteHeader(response)
+				default:
+					_ = json.NewEncoder(res).Encode(response)
+				}
+			}))
+			t.Cleanup(server.Close)
+
+			maxPollInterval := waitMaxPollInterval
+			waitMaxPollInterval = 20 * time.Millisecond
+			t.Cleanup(func() { waitMaxPollInterval = maxPollInterval })
+
+			entry, err := waitForExecution(common.IntFlagMap{model.FlagRetries: 0}, &common.Server{ServerDetails: &config.ServerDetails{Url: server.URL + "/"}},
+				"my-worker", "", "trace-1", 10*time.Millisecond, time.Minute)
+
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				assert.Equal(t, succeeded.ExecutionStatus, entry.ExecutionStatus)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.Equal(t, tt.wantExit, common.ExitCodeOf(err))
+			}
+
+			var since []string
+			for _, query := range queries {
+				since = append(since, query.Get("since"))
+			}
+			assert.Equal(t, tt.wantSince, since)
+		})
+	}
+}
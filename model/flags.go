@@ -26,6 +26,8 @@ const (
 	FlagChangesDescription = "changes-description"
 	FlagChangesCommitSha   = "changes-commitsha"
 	FlagBase64             = "base64"
+	FlagConcurrency        = "concurrency"
+	FlagStrict             = "strict"
 	defaultTimeoutMillis   = 5000
 )
 
@@ -34,6 +36,10 @@ var (
 	EnvKeyAccessToken     = "JFROG_WORKER_CLI_DEV_ACCESS_TOKEN"
 	EnvKeySecretsPassword = "JFROG_WORKER_CLI_DEV_SECRETS_PASSWORD"
 	EnvKeyAddSecretValue  = "JFROG_WORKER_CLI_DEV_ADD_SECRET_VALUE"
+	// EnvKeyCACertsPath A PEM file, or a directory of PEM files, with certificates to trust on top of the system ones and the JFrog CLI ones.
+	EnvKeyCACertsPath = "JFROG_WORKER_CLI_CA_CERTS_PATH"
+	// EnvKeyInsecureTLS Set to 'true' to skip the TLS verification of the server certificate.
+	EnvKeyInsecureTLS = "JFROG_WORKER_CLI_INSECURE_TLS"
 )
 
 type IntFlagProvider interface {
@@ -53,6 +59,17 @@ func GetTimeoutFlag() components.StringFlag {
 	return components.NewStringFlag(FlagTimeout, "The request timeout in milliseconds", components.WithIntDefaultValue(defaultTimeoutMillis))
 }
 
+// GetHTTPClientFlags returns the flags configuring how the commands call the server: the retries and the HTTP trace.
+// Every command calling the server appends them to its flags.
+func GetHTTPClientFlags() []components.Flag {
+	return []components.Flag{
+		GetRetriesFlag(),
+		GetRetryBackoffFlag(),
+		GetTraceHTTPFlag(),
+		GetTraceIncludeSourceFlag(),
+	}
+}
+
 func GetProjectKeyFlag() components.StringFlag {
 	return components.NewStringFlag(FlagProjectKey, "A project key to use for the request", components.WithStrDefaultValue(""))
 }
@@ -100,6 +117,23 @@ func GetTimeoutParameter(c IntFlagProvider) (time.Duration, error) {
 	return time.Duration(value) * time.Millisecond, nil
 }
 
+func GetStrictFlag() components.BoolFlag {
+	return components.NewBoolFlag(FlagStrict, "Fail when the value returned by the worker does not match the response type of its action.", components.WithBoolDefaultValue(false))
+}
+
+func GetConcurrencyFlag(description string, defaultValue int) components.StringFlag {
+	return components.NewStringFlag(FlagConcurrency, description, components.WithIntDefaultValue(defaultValue))
+}
+
+// GetConcurrencyParameter returns the number of requests that can be sent at the same time, at least one.
+func GetConcurrencyParameter(c IntFlagProvider) (int, error) {
+	value, err := c.GetIntFlagValue(FlagConcurrency)
+	if err != nil || value < 1 {
+		return 0, errors.New("invalid concurrency provided, expected a positive number")
+	}
+	return value, nil
+}
+
 func GetApplicationFlag() components.StringFlag {
 	return components.NewStringFlag(
 		FlagApplication,
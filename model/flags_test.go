@@ -1,3 +1,6 @@
+//go:build test
+// +build test
+
 package model
 
 import (
@@ -47,6 +50,20 @@ func TestGetTimeoutParameter(t *testing.T) {
 	}
 }
 
+func TestGetConcurrencyParameter(t *testing.T) {
+	got, err := GetConcurrencyParameter(intFlagProviderStub{FlagConcurrency: {val: 3}})
+	require.NoError(t, err)
+	assert.Equal(t, 3, got)
+
+	for _, invalid := range []intFlagProviderStub{
+		{FlagConcurrency: {val: 0}},
+		{FlagConcurrency: {err: errors.New("parse error")}},
+	} {
+		_, err = GetConcurrencyParameter(invalid)
+		assert.EqualError(t, err, "invalid concurrency provided, expected a positive number")
+	}
+}
+
 type intFlagProviderStub map[string]struct {
 	val int
 	err error
@@ -64,3 +81,19 @@ func (p intFlagProviderStub) GetIntFlagValue(name string) (int, error) {
 	}
 	return 0, fmt.Errorf("flag %s used but not provided", name)
 }
+
+func TestGetHTTPClientFlags(t *testing.T) {
+	var names []string
+	for _, flag := range GetHTTPClientFlags() {
+		names = append(names, flag.GetName())
+	}
+	assert.Equal(t, []string{FlagRetries, FlagRetryBackoff, FlagTraceHTTP, FlagTraceIncludeSource}, names)
+}
+
+func TestGetMetadataFlags(t *testing.T) {
+	var names []string
+	for _, flag := range GetMetadataFlags() {
+		names = append(names, flag.GetName())
+	}
+	assert.Equal(t, []string{FlagRefreshMetadata, FlagOffline, FlagActionsFile, FlagOptionsFile}, names)
+}
@@ -1,21 +1,12 @@
 package model
 
-type ArtifactFilterCriteria struct {
-	RepoKeys     []string `json:"repoKeys,omitempty"`
-	AnyLocal     bool     `json:"anyLocal,omitempty"`
-	AnyFederated bool     `json:"anyFederated,omitempty"`
-	AnyRemote    bool     `json:"anyRemote,omitempty"`
-}
-
-type ScheduleFilterCriteria struct {
-	Cron     string `json:"cron,omitempty"`
-	Timezone string `json:"timezone,omitempty"`
-}
+import "github.com/jfrog/jfrog-cli-platform-services/workerclient"
 
-type FilterCriteria struct {
-	ArtifactFilterCriteria *ArtifactFilterCriteria `json:"artifactFilterCriteria,omitempty"`
-	Schedule               *ScheduleFilterCriteria `json:"schedule,omitempty"`
-}
+type (
+	ArtifactFilterCriteria = workerclient.ArtifactFilterCriteria
+	ScheduleFilterCriteria = workerclient.ScheduleFilterCriteria
+	FilterCriteria         = workerclient.FilterCriteria
+)
 
 type Secrets map[string]string
 
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	FlagRefreshMetadata     = "refresh-metadata"
+	FlagOffline             = "offline"
+	defaultMetadataCacheTTL = time.Hour
+)
+
+// EnvKeyMetadataCacheTTL How long the actions and options metadata are used without asking the server, e.g. '30m'.
+// With '0' the metadata are revalidated at every call, and the cache is only used with --offline.
+var EnvKeyMetadataCacheTTL = "JFROG_WORKER_CLI_METADATA_CACHE_TTL"
+
+func GetRefreshMetadataFlag() components.BoolFlag {
+	return components.NewBoolFlag(
+		FlagRefreshMetadata,
+		"Download the actions and options metadata from the server, instead of using the ones cached under the JFrog CLI home directory.",
+		components.WithBoolDefaultValue(false),
+	)
+}
+
+func GetOfflineFlag() components.BoolFlag {
+	return components.NewBoolFlag(
+		FlagOffline,
+		"Do not call the server for the actions and options metadata, use the ones cached by a previous command.",
+		components.WithBoolDefaultValue(false),
+	)
+}
+
+// GetMetadataCacheTTL returns the TTL set with EnvKeyMetadataCacheTTL, or the default one.
+func GetMetadataCacheTTL() time.Duration {
+	value, isSet := os.LookupEnv(EnvKeyMetadataCacheTTL)
+	if !isSet || value == "" {
+		return defaultMetadataCacheTTL
+	}
+
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl < 0 {
+		log.Warn(fmt.Sprintf("Invalid %s '%s', using the default value %s", EnvKeyMetadataCacheTTL, value, defaultMetadataCacheTTL))
+		return defaultMetadataCacheTTL
+	}
+
+	return ttl
+}
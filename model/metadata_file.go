@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+)
+
+const (
+	FlagActionsFile = "actions-file"
+	FlagOptionsFile = "options-file"
+)
+
+// MetadataBundle is the file written by 'jf worker export-metadata', to be used with --actions-file and --options-file where the server cannot be reached.
+type MetadataBundle struct {
+	ServerURL  string            `json:"serverUrl,omitempty"`
+	ProjectKey string            `json:"projectKey,omitempty"`
+	ExportedAt time.Time         `json:"exportedAt"`
+	Actions    []*ActionMetadata `json:"actions"`
+	Options    *OptionsMetadata  `json:"options,omitempty"`
+}
+
+// GetMetadataFlags returns the flags telling where the actions and options metadata are read from: the cache, the server or files.
+// Every command loading the actions or the options, including through the capabilities checks, appends them to its flags.
+func GetMetadataFlags() []components.Flag {
+	return []components.Flag{
+		GetRefreshMetadataFlag(),
+		GetOfflineFlag(),
+		GetActionsFileFlag(),
+		GetOptionsFileFlag(),
+	}
+}
+
+func GetActionsFileFlag() components.StringFlag {
+	return components.NewStringFlag(
+		FlagActionsFile,
+		"Read the actions metadata from this file instead of the server. Accepts the output of 'jf worker list-event --format json' or of 'jf worker export-metadata'.",
+		components.WithStrDefaultValue(""),
+	)
+}
+
+func GetOptionsFileFlag() components.StringFlag {
+	return components.NewStringFlag(
+		FlagOptionsFile,
+		"Read the server options from this file instead of the server. Accepts the output of 'jf worker export-metadata', or the options document returned by the server.",
+		components.WithStrDefaultValue(""),
+	)
+}
@@ -0,0 +1,8 @@
+package model
+
+import "github.com/jfrog/jfrog-cli-platform-services/workerclient"
+
+type (
+	EditionOptions  = workerclient.EditionOptions
+	OptionsMetadata = workerclient.OptionsMetadata
+)
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+)
+
+const (
+	FlagTemplate = "template"
+	FlagQuery    = "query"
+)
+
+func GetTemplateFlag() components.StringFlag {
+	return components.NewStringFlag(
+		FlagTemplate,
+		"A Go template printed for each item of the output, e.g. '{{.Key}} {{.Enabled}}'. Implies --format template.",
+		components.WithStrDefaultValue(""),
+	)
+}
+
+func GetQueryFlag() components.StringFlag {
+	return components.NewStringFlag(
+		FlagQuery,
+		"A jq filter applied to the JSON output before it is printed, e.g. '.workers[] | select(.enabled == true) | .key'. The values of a filter yielding several of them are printed as an array.",
+		components.WithStrDefaultValue(""),
+	)
+}
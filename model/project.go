@@ -0,0 +1,6 @@
+package model
+
+import "github.com/jfrog/jfrog-cli-platform-services/workerclient"
+
+// Project is a JFrog Platform project, as returned by the Access service.
+type Project = workerclient.Project
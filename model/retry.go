@@ -0,0 +1,103 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+
+	"github.com/jfrog/jfrog-cli-platform-services/workerclient"
+)
+
+const (
+	FlagRetries            = "retries"
+	FlagRetryBackoff       = "retry-backoff-ms"
+	defaultRetries         = 2
+	defaultRetryBackoffMs  = 250
+	defaultMaxRetryBackoff = 5 * time.Second
+)
+
+var (
+	EnvKeyRetries      = "JFROG_WORKER_CLI_RETRIES"
+	EnvKeyRetryBackoff = "JFROG_WORKER_CLI_RETRY_BACKOFF_MS"
+	// EnvKeyRetryStatuses A comma separated list of the response statuses that trigger a retry.
+	EnvKeyRetryStatuses = "JFROG_WORKER_CLI_RETRY_STATUSES"
+)
+
+// DefaultRetryableStatuses are the statuses denoting a transient failure of the server or of a proxy in front of it.
+var DefaultRetryableStatuses = workerclient.DefaultRetryableStatuses
+
+// RetryPolicy describes how a failed request should be retried.
+type RetryPolicy = workerclient.RetryPolicy
+
+func GetRetriesFlag() components.StringFlag {
+	return components.NewStringFlag(
+		FlagRetries,
+		fmt.Sprintf("The number of times a failed request is retried (default %d). Can also be set with %s.", defaultRetries, EnvKeyRetries),
+	)
+}
+
+func GetRetryBackoffFlag() components.StringFlag {
+	return components.NewStringFlag(
+		FlagRetryBackoff,
+		fmt.Sprintf("The delay in milliseconds before the first retry, doubled at each attempt (default %d). Can also be set with %s.", defaultRetryBackoffMs, EnvKeyRetryBackoff),
+	)
+}
+
+// GetRetryPolicy reads the retry policy from the flags, then from the environment, then falls back to the defaults.
+func GetRetryPolicy(c IntFlagProvider) (*RetryPolicy, error) {
+	retries, err := getIntFlagOrEnv(c, FlagRetries, EnvKeyRetries, defaultRetries)
+	if err != nil || retries < 0 {
+		return nil, errors.New("invalid retries provided")
+	}
+
+	backoffMs, err := getIntFlagOrEnv(c, FlagRetryBackoff, EnvKeyRetryBackoff, defaultRetryBackoffMs)
+	if err != nil || backoffMs < 0 {
+		return nil, errors.New("invalid retry backoff provided")
+	}
+
+	statuses := DefaultRetryableStatuses
+	if value, isSet := os.LookupEnv(EnvKeyRetryStatuses); isSet && value != "" {
+		statuses = nil
+		for _, status := range strings.Split(value, ",") {
+			code, err := strconv.Atoi(strings.TrimSpace(status))
+			if err != nil {
+				log.Debug(fmt.Sprintf("Invalid retry status '%s': %+v", status, err))
+				return nil, fmt.Errorf("invalid %s provided", EnvKeyRetryStatuses)
+			}
+			statuses = append(statuses, code)
+		}
+	}
+
+	return &RetryPolicy{
+		MaxAttempts:       retries + 1,
+		InitialBackoff:    time.Duration(backoffMs) * time.Millisecond,
+		MaxBackoff:        defaultMaxRetryBackoff,
+		RetryableStatuses: statuses,
+	}, nil
+}
+
+func getIntFlagOrEnv(c IntFlagProvider, flagName string, envKey string, defaultValue int) (int, error) {
+	if c.IsFlagSet(flagName) {
+		value, err := c.GetIntFlagValue(flagName)
+		if err != nil {
+			log.Debug(fmt.Sprintf("Invalid %s: %+v", flagName, err))
+		}
+		return value, err
+	}
+
+	if value, isSet := os.LookupEnv(envKey); isSet && value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			log.Debug(fmt.Sprintf("Invalid %s: %+v", envKey, err))
+		}
+		return parsed, err
+	}
+
+	return defaultValue, nil
+}
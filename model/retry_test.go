@@ -0,0 +1,99 @@
+//go:build test
+// +build test
+
+package model
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name         string
+		flagProvider IntFlagProvider
+		env          map[string]string
+		want         *RetryPolicy
+		wantErr      string
+	}{
+		{
+			name:         "default",
+			flagProvider: intFlagProviderStub{},
+			want: &RetryPolicy{
+				MaxAttempts:       defaultRetries + 1,
+				InitialBackoff:    defaultRetryBackoffMs * time.Millisecond,
+				MaxBackoff:        defaultMaxRetryBackoff,
+				RetryableStatuses: DefaultRetryableStatuses,
+			},
+		},
+		{
+			name:         "from flags",
+			flagProvider: intFlagProviderStub{FlagRetries: {val: 0}, FlagRetryBackoff: {val: 10}},
+			env:          map[string]string{EnvKeyRetries: "5"},
+			want: &RetryPolicy{
+				MaxAttempts:       1,
+				InitialBackoff:    10 * time.Millisecond,
+				MaxBackoff:        defaultMaxRetryBackoff,
+				RetryableStatuses: DefaultRetryableStatuses,
+			},
+		},
+		{
+			name:         "from env",
+			flagProvider: intFlagProviderStub{},
+			env:          map[string]string{EnvKeyRetries: "5", EnvKeyRetryBackoff: "100", EnvKeyRetryStatuses: "500, 502"},
+			want: &RetryPolicy{
+				MaxAttempts:       6,
+				InitialBackoff:    100 * time.Millisecond,
+				MaxBackoff:        defaultMaxRetryBackoff,
+				RetryableStatuses: []int{http.StatusInternalServerError, http.StatusBadGateway},
+			},
+		},
+		{
+			name:         "invalid retries flag",
+			flagProvider: intFlagProviderStub{FlagRetries: {err: errors.New("parse error")}},
+			wantErr:      "invalid retries provided",
+		},
+		{
+			name:         "negative retries",
+			flagProvider: intFlagProviderStub{FlagRetries: {val: -1}},
+			wantErr:      "invalid retries provided",
+		},
+		{
+			name:         "invalid backoff env",
+			flagProvider: intFlagProviderStub{},
+			env:          map[string]string{EnvKeyRetryBackoff: "abc"},
+			wantErr:      "invalid retry backoff provided",
+		},
+		{
+			name:         "invalid statuses env",
+			flagProvider: intFlagProviderStub{},
+			env:          map[string]string{EnvKeyRetryStatuses: "502,abc"},
+			wantErr:      "invalid " + EnvKeyRetryStatuses + " provided",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				require.NoError(t, os.Setenv(key, value))
+				t.Cleanup(func() {
+					_ = os.Unsetenv(key)
+				})
+			}
+
+			got, err := GetRetryPolicy(tt.flagProvider)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package model
+
+import "github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+
+const (
+	FlagTraceHTTP          = "trace-http"
+	FlagTraceIncludeSource = "trace-include-source"
+)
+
+func GetTraceHTTPFlag() components.StringFlag {
+	return components.NewStringFlag(
+		FlagTraceHTTP,
+		"Record the requests sent to the server and their responses in this HAR file. The access token and the secret values are redacted.",
+		components.WithStrDefaultValue(""),
+	)
+}
+
+func GetTraceIncludeSourceFlag() components.BoolFlag {
+	return components.NewBoolFlag(
+		FlagTraceIncludeSource,
+		"Do not truncate the worker source code in the file recorded with --"+FlagTraceHTTP+".",
+		components.WithBoolDefaultValue(false),
+	)
+}
@@ -1,11 +1,5 @@
 package model
 
-type Version struct {
-	CommitSha   string `json:"commitSha"`
-	Description string `json:"description"`
-	Number      string `json:"versionNumber"`
-}
+import "github.com/jfrog/jfrog-cli-platform-services/workerclient"
 
-func (v *Version) IsEmpty() bool {
-	return v == nil || (v.CommitSha == "" && v.Description == "" && v.Number == "")
-}
+type Version = workerclient.Version
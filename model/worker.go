@@ -1,18 +1,12 @@
 package model
 
-type Secret struct {
-	Key              string `json:"key"`
-	Value            string `json:"value"`
-	MarkedForRemoval bool   `json:"markedForRemoval"`
-}
-type WorkerDetails struct {
-	Key            string          `json:"key"`
-	Description    string          `json:"description"`
-	Debug          bool            `json:"debug"`
-	Enabled        bool            `json:"enabled"`
-	SourceCode     string          `json:"sourceCode"`
-	Action         string          `json:"action"`
-	FilterCriteria *FilterCriteria `json:"filterCriteria,omitempty"`
-	Secrets        []*Secret       `json:"secrets"`
-	ProjectKey     string          `json:"projectKey"`
-}
+import "github.com/jfrog/jfrog-cli-platform-services/workerclient"
+
+// The types exchanged with the Worker Service are defined by the workerclient package.
+type (
+	Secret                = workerclient.Secret
+	WorkerDetails         = workerclient.WorkerDetails
+	WorkerRequest         = workerclient.WorkerRequest
+	TestRunRequest        = workerclient.TestRunRequest
+	ExecutionHistoryEntry = workerclient.ExecutionHistoryEntry
+)
@@ -3,7 +3,7 @@ package main
 import (
 	"github.com/jfrog/jfrog-cli-core/v2/plugins"
 	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
-	"github.com/jfrog/jfrog-cli-platform-services/commands"
+	"github.com/jfrog/jfrog-cli-platform-services/cli"
 )
 
 func main() {
@@ -20,16 +20,5 @@ func getApp() components.App {
 }
 
 func getCommands() []components.Command {
-	return []components.Command{
-		commands.GetInitCommand(),
-		commands.GetDryRunCommand(),
-		commands.GetDeployCommand(),
-		commands.GetExecuteCommand(),
-		commands.GetRemoveCommand(),
-		commands.GetListCommand(),
-		commands.GetAddSecretCommand(),
-		commands.GetListEventsCommand(),
-		commands.GetEditScheduleCommand(),
-		commands.GetShowExecutionHistoryCommand(),
-	}
+	return cli.GetWorkerCommands()
 }
@@ -0,0 +1,288 @@
+// Package workerclient provides a Go client for the JFrog Worker Service API.
+//
+// A Client is bound to a JFrog Platform URL and an access token:
+//
+//	client, err := workerclient.New("https://my.jfrog.io", token)
+//	if err != nil {
+//		return err
+//	}
+//	workers, err := client.ListWorkers(ctx, workerclient.ListWorkersOptions{ProjectKey: "my-project"})
+//
+// Every call is bound to the context it receives, and returns an *Error when the server answers with an unexpected status.
+package workerclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const defaultUserAgent = "jfrog-worker-client"
+
+type APIVersion int
+
+const (
+	APIVersionV1 APIVersion = iota + 1
+	APIVersionV2 APIVersion = 2
+)
+
+// Client calls the Worker Service API of a JFrog Platform instance.
+type Client struct {
+	serverURL   string
+	token       string
+	httpClient  *http.Client
+	retryPolicy *RetryPolicy
+	userAgent   string
+}
+
+type Option func(c *Client)
+
+// WithHTTPClient sets the HTTP client used to call the server. Defaults to http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetryPolicy sets how failed requests are retried. Requests are not retried by default.
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// New creates a client for the JFrog Platform located at serverURL, authenticated with the provided access token.
+func New(serverURL string, token string, options ...Option) (*Client, error) {
+	if serverURL == "" {
+		return nil, errors.New("missing server URL")
+	}
+
+	if _, err := url.Parse(serverURL); err != nil {
+		return nil, fmt.Errorf("invalid server URL: %w", err)
+	}
+
+	c := &Client{
+		serverURL:  strings.TrimSuffix(serverURL, "/") + "/",
+		token:      strings.TrimSpace(token),
+		httpClient: http.DefaultClient,
+		userAgent:  defaultUserAgent,
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	return c, nil
+}
+
+// Request is a raw call to the Worker Service API.
+type Request struct {
+	Method     string
+	Path       []string
+	Query      map[string]string
+	ProjectKey string
+	// Defaults to APIVersionV1
+	APIVersion APIVersion
+	Body       []byte
+	// The statuses considered as a success, any other status results in an *Error
+	OkStatuses []int
+	// Idempotent marks a request using a non-idempotent method (e.g. POST) as safe to retry.
+	Idempotent bool
+	// Additional headers, e.g. If-None-Match
+	Header http.Header
+	// A path relative to the server URL, used instead of the Worker Service API path, e.g. artifactory/api/system/version
+	Endpoint string
+}
+
+// Response is the outcome of a successful raw call.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// URL returns the endpoint called by the request.
+func (c *Client) URL(req *Request) string {
+	apiVersion := APIVersionV1
+	if req.APIVersion != 0 {
+		apiVersion = req.APIVersion
+	}
+
+	endpoint := fmt.Sprintf("%sworker/api/v%d/%s", c.serverURL, apiVersion, strings.Join(req.Path, "/"))
+	if req.Endpoint != "" {
+		endpoint = c.serverURL + strings.TrimPrefix(req.Endpoint, "/")
+	}
+
+	q := url.Values{}
+
+	if req.ProjectKey != "" {
+		q.Set("projectKey", req.ProjectKey)
+	}
+
+	for key, value := range req.Query {
+		q.Set(key, value)
+	}
+
+	if len(q) > 0 {
+		endpoint += "?" + q.Encode()
+	}
+
+	return endpoint
+}
+
+// Do sends a raw request, retrying it according to the retry policy of the client.
+// All the attempts share the deadline of ctx.
+func (c *Client) Do(ctx context.Context, req *Request) (*Response, error) {
+	endpoint := c.URL(req)
+
+	maxAttempts := 1
+	if c.retryPolicy != nil && (req.Idempotent || isIdempotentMethod(req.Method)) {
+		maxAttempts = max(c.retryPolicy.MaxAttempts, 1)
+	}
+
+	var res *http.Response
+	var err error
+
+	for attempt := 1; ; attempt++ {
+		res, err = c.send(ctx, req, endpoint)
+
+		if attempt >= maxAttempts || !c.shouldRetry(res, err) {
+			break
+		}
+
+		wait := c.retryDelay(res, attempt)
+		if deadline, hasDeadline := ctx.Deadline(); hasDeadline && time.Until(deadline) < wait {
+			// We will not be able to retry before the deadline, so we keep the current outcome
+			break
+		}
+
+		if res != nil {
+			log.Debug(fmt.Sprintf("%s %s returned %d, retrying in %s (attempt %d/%d)", req.Method, endpoint, res.StatusCode, wait, attempt+1, maxAttempts))
+			discardAndClose(res.Body)
+		} else {
+			log.Debug(fmt.Sprintf("%s %s failed, retrying in %s (attempt %d/%d): %+v", req.Method, endpoint, wait, attempt+1, maxAttempts, err))
+		}
+
+		if err = sleepContext(ctx, wait); err != nil {
+			res = nil
+			break
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer discardAndClose(res.Body)
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read response content: %w", err)
+	}
+
+	if !slices.Contains(req.OkStatuses, res.StatusCode) {
+		return nil, newError(req.Method, endpoint, res.StatusCode, body)
+	}
+
+	return &Response{StatusCode: res.StatusCode, Header: res.Header, Body: body}, nil
+}
+
+func (c *Client) send(ctx context.Context, req *Request, endpoint string) (*http.Response, error) {
+	var bodyReader io.Reader
+	if req.Body != nil {
+		bodyReader = bytes.NewReader(req.Body)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, endpoint, bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	for name, values := range req.Header {
+		for _, value := range values {
+			httpReq.Header.Add(name, value)
+		}
+	}
+
+	httpReq.Header.Set("Authorization", "Bearer "+c.token)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", c.userAgent)
+
+	return c.httpClient.Do(httpReq)
+}
+
+func (c *Client) shouldRetry(res *http.Response, err error) bool {
+	if err != nil {
+		// Only network errors are worth a retry, not the ones caused by the context
+		return !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, context.Canceled)
+	}
+	return slices.Contains(c.retryPolicy.RetryableStatuses, res.StatusCode)
+}
+
+// retryDelay honors the Retry-After header when present, otherwise it computes an exponential backoff with full jitter.
+func (c *Client) retryDelay(res *http.Response, attempt int) time.Duration {
+	if res != nil {
+		if retryAfter := res.Header.Get("Retry-After"); retryAfter != "" {
+			if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+				return time.Duration(seconds) * time.Second
+			}
+			if date, err := http.ParseTime(retryAfter); err == nil {
+				return max(time.Until(date), 0)
+			}
+		}
+	}
+
+	backoff := c.retryPolicy.InitialBackoff << (attempt - 1)
+	if backoff <= 0 || (c.retryPolicy.MaxBackoff > 0 && backoff > c.retryPolicy.MaxBackoff) {
+		backoff = c.retryPolicy.MaxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+
+	//#nosec G404 -- The jitter does not need a cryptographic random source.
+	return time.Duration(rand.Int64N(int64(backoff) + 1))
+}
+
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func sleepContext(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func discardAndClose(body io.ReadCloser) {
+	_, _ = io.Copy(io.Discard, body)
+	_ = body.Close()
+}
@@ -0,0 +1,304 @@
+//go:build test
+// +build test
+
+package workerclient
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedRequest struct {
+	method string
+	path   string
+	query  map[string]string
+	header http.Header
+	body   []byte
+}
+
+func newTestClient(t *testing.T, status int, response any, options ...Option) (*Client, *recordedRequest) {
+	recorded := &recordedRequest{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		recorded.method = req.Method
+		recorded.path = req.URL.Path
+		recorded.header = req.Header.Clone()
+		recorded.query = map[string]string{}
+		for key := range req.URL.Query() {
+			recorded.query[key] = req.URL.Query().Get(key)
+		}
+		recorded.body, _ = io.ReadAll(req.Body)
+
+		res.WriteHeader(status)
+		if response != nil {
+			_ = json.NewEncoder(res).Encode(response)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := New(server.URL, " my-token ", options...)
+	require.NoError(t, err)
+
+	return client, recorded
+}
+
+func TestNew(t *testing.T) {
+	_, err := New("", "token")
+	assert.EqualError(t, err, "missing server URL")
+
+	client, err := New("https://my.jfrog.io", "token")
+	require.NoError(t, err)
+	assert.Equal(t, "https://my.jfrog.io/worker/api/v2/actions?projectKey=proj", client.URL(&Request{Path: []string{"actions"}, APIVersion: APIVersionV2, ProjectKey: "proj"}))
+}
+
+func TestClient_ListWorkers(t *testing.T) {
+	workers := []*WorkerDetails{{Key: "wk-1", Action: "GENERIC_EVENT"}}
+	client, recorded := newTestClient(t, http.StatusOK, map[string]any{"workers": workers}, WithUserAgent("tests"))
+
+	got, err := client.ListWorkers(context.Background(), ListWorkersOptions{Action: "GENERIC_EVENT", ProjectKey: "proj"})
+	require.NoError(t, err)
+
+	assert.Equal(t, workers, got)
+	assert.Equal(t, http.MethodGet, recorded.method)
+	assert.Equal(t, "/worker/api/v1/workers", recorded.path)
+	assert.Equal(t, map[string]string{"action": "GENERIC_EVENT", "projectKey": "proj"}, recorded.query)
+	assert.Equal(t, "Bearer my-token", recorded.header.Get("Authorization"))
+	assert.Equal(t, "tests", recorded.header.Get("User-Agent"))
+}
+
+func TestClient_GetWorker(t *testing.T) {
+	client, recorded := newTestClient(t, http.StatusOK, &WorkerDetails{Key: "wk-1"})
+
+	got, err := client.GetWorker(context.Background(), "wk-1", "")
+	require.NoError(t, err)
+	assert.Equal(t, "wk-1", got.Key)
+	assert.Equal(t, "/worker/api/v1/workers/wk-1", recorded.path)
+
+	client, _ = newTestClient(t, http.StatusNotFound, nil)
+
+	_, err = client.GetWorker(context.Background(), "wk-1", "")
+	assert.True(t, IsNotFound(err))
+}
+
+func TestClient_SaveWorker(t *testing.T) {
+	request := &WorkerRequest{Key: "wk-1", SourceCode: "code", Action: Action{Name: "GENERIC_EVENT", Application: "worker"}}
+
+	tests := []struct {
+		name       string
+		status     int
+		save       func(c *Client) error
+		wantMethod string
+		wantErr    bool
+	}{
+		{
+			name:       "create",
+			status:     http.StatusCreated,
+			save:       func(c *Client) error { return c.CreateWorker(context.Background(), request) },
+			wantMethod: http.MethodPost,
+		},
+		{
+			name:       "update",
+			status:     http.StatusNoContent,
+			save:       func(c *Client) error { return c.UpdateWorker(context.Background(), request) },
+			wantMethod: http.MethodPut,
+		},
+		{
+			name:       "unexpected status",
+			status:     http.StatusOK,
+			save:       func(c *Client) error { return c.CreateWorker(context.Background(), request) },
+			wantMethod: http.MethodPost,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, recorded := newTestClient(t, tt.status, nil)
+
+			err := tt.save(client)
+			if tt.wantErr {
+				assert.True(t, HasStatus(err, tt.status))
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.wantMethod, recorded.method)
+			assert.Equal(t, "/worker/api/v2/workers", recorded.path)
+
+			got := &WorkerRequest{}
+			require.NoError(t, json.Unmarshal(recorded.body, got))
+			assert.Equal(t, request, got)
+		})
+	}
+}
+
+func TestClient_DeleteWorker(t *testing.T) {
+	client, recorded := newTestClient(t, http.StatusNoContent, nil)
+
+	require.NoError(t, client.DeleteWorker(context.Background(), "wk-1", "proj"))
+	assert.Equal(t, http.MethodDelete, recorded.method)
+	assert.Equal(t, "/worker/api/v1/workers/wk-1", recorded.path)
+	assert.Equal(t, map[string]string{"projectKey": "proj"}, recorded.query)
+}
+
+func TestClient_Execute(t *testing.T) {
+	client, recorded := newTestClient(t, http.StatusOK, map[string]any{"result": "ok"})
+
+	got, err := client.Execute(context.Background(), "wk-1", "", map[string]any{"hello": "world"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"result":"ok"}`, string(got))
+	assert.Equal(t, "/worker/api/v1/execute/wk-1", recorded.path)
+	assert.JSONEq(t, `{"hello":"world"}`, string(recorded.body))
+}
+
+func TestClient_TestRun(t *testing.T) {
+	client, recorded := newTestClient(t, http.StatusOK, map[string]any{"result": "ok"})
+
+	got, err := client.TestRun(context.Background(), "wk-1", &TestRunRequest{Code: "code", Action: "GENERIC_EVENT"}, TestRunOptions{Debug: true})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"result":"ok"}`, string(got))
+	assert.Equal(t, "/worker/api/v1/test/wk-1", recorded.path)
+	assert.Equal(t, map[string]string{"debug": "true"}, recorded.query)
+	assert.JSONEq(t, `{"code":"code","action":"GENERIC_EVENT","data":null}`, string(recorded.body))
+}
+
+func TestClient_ExecutionHistory(t *testing.T) {
+	entries := []*ExecutionHistoryEntry{{WorkerKey: "wk-1", ExecutionStatus: "STATUS_SUCCESS", TraceID: "trace"}}
+	client, recorded := newTestClient(t, http.StatusOK, entries)
+
+	got, err := client.ExecutionHistory(context.Background(), ExecutionHistoryOptions{WorkerKey: "wk-1", ShowTestRun: true})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.NotEmpty(t, got[0].Raw)
+	got[0].Raw = nil
+	assert.Equal(t, entries, got)
+	assert.Equal(t, "/worker/api/v1/execution_history", recorded.path)
+	assert.Equal(t, map[string]string{"workerKey": "wk-1", "showTestRun": "true"}, recorded.query)
+
+	_, err = client.ExecutionHistory(context.Background(), ExecutionHistoryOptions{WorkerKey: "wk-1", Limit: 50, Offset: 100})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"workerKey": "wk-1", "limit": "50", "offset": "100"}, recorded.query)
+
+	since, until := time.UnixMilli(1000), time.UnixMilli(2000)
+	_, err = client.ExecutionHistory(context.Background(), ExecutionHistoryOptions{WorkerKey: "wk-1", Since: since, Until: until, Statuses: []string{"STATUS_FAIL", "STATUS_TIMEOUT"}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"workerKey": "wk-1", "since": "1000", "until": "2000", "status": "STATUS_FAIL,STATUS_TIMEOUT"}, recorded.query)
+}
+
+func TestClient_Metadata(t *testing.T) {
+	actions := []*ActionMetadata{{Action: Action{Name: "GENERIC_EVENT", Application: "worker"}}}
+	client, recorded := newTestClient(t, http.StatusOK, actions)
+
+	gotActions, err := client.Actions(context.Background(), "proj")
+	require.NoError(t, err)
+	assert.Equal(t, actions, gotActions)
+	assert.Equal(t, "/worker/api/v2/actions", recorded.path)
+
+	options := &OptionsMetadata{IsHistoryEnabled: true}
+	client, recorded = newTestClient(t, http.StatusOK, options)
+
+	gotOptions, err := client.Options(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, options, gotOptions)
+	assert.Equal(t, "/worker/api/v1/options", recorded.path)
+
+	client, recorded = newTestClient(t, http.StatusOK, map[string]any{"version": "7.104.2", "revision": "80402900"})
+
+	gotVersion, err := client.ArtifactoryVersion(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "7.104.2", gotVersion)
+	assert.Equal(t, "/artifactory/api/system/version", recorded.path)
+}
+
+func TestClient_ListProjects(t *testing.T) {
+	projects := []*Project{{ProjectKey: "proj-1", DisplayName: "Project 1"}}
+	client, recorded := newTestClient(t, http.StatusOK, projects)
+
+	got, err := client.ListProjects(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, projects, got)
+	assert.Equal(t, "/access/api/v1/projects", recorded.path)
+}
+
+func TestClient_Do_Retry(t *testing.T) {
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if calls.Add(1) < 3 {
+			res.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		res.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	policy := &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond, RetryableStatuses: DefaultRetryableStatuses}
+
+	client, err := New(server.URL, "token", WithRetryPolicy(policy))
+	require.NoError(t, err)
+
+	_, err = client.Do(context.Background(), &Request{Method: http.MethodGet, Path: []string{"workers"}, OkStatuses: []int{http.StatusOK}})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), calls.Load())
+
+	// Non-idempotent requests are not retried
+	calls.Store(0)
+	_, err = client.Do(context.Background(), &Request{Method: http.MethodPost, Path: []string{"workers"}, OkStatuses: []int{http.StatusOK}})
+	assert.True(t, HasStatus(err, http.StatusServiceUnavailable))
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestClient_Do_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		response any
+		want     *Error
+	}{
+		{
+			name:     "flat document",
+			response: map[string]any{"code": 409, "message": "Already exists", "details": "key wk-1", "traceId": "abc"},
+			want:     &Error{Code: "409", Message: "Already exists", Details: []string{"key wk-1"}, TraceID: "abc"},
+		},
+		{
+			name:     "errors list",
+			response: map[string]any{"errors": []map[string]any{{"code": "FORBIDDEN", "message": "Missing permission"}, {"message": "Project proj"}}},
+			want:     &Error{Code: "FORBIDDEN", Message: "Missing permission", Details: []string{"Project proj"}},
+		},
+		{
+			name:     "details objects",
+			response: map[string]any{"message": "Invalid payload", "details": []map[string]any{{"message": "missing key"}, {"field": "action"}}},
+			want:     &Error{Message: "Invalid payload", Details: []string{"missing key", `{"field":"action"}`}},
+		},
+		{
+			name:     "not a document",
+			response: "oops",
+			want:     &Error{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := newTestClient(t, http.StatusConflict, tt.response)
+
+			_, err := client.Do(context.Background(), &Request{Method: http.MethodGet, Path: []string{"workers"}, OkStatuses: []int{http.StatusOK}})
+
+			var got *Error
+			require.ErrorAs(t, err, &got)
+			assert.Equal(t, http.StatusConflict, got.StatusCode)
+			assert.Equal(t, tt.want.Code, got.Code)
+			assert.Equal(t, tt.want.Message, got.Message)
+			assert.Equal(t, tt.want.Details, got.Details)
+			assert.Equal(t, tt.want.TraceID, got.TraceID)
+			assert.NotEmpty(t, got.Body)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package workerclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Error is returned when the server answers with an unexpected status.
+// When the response contains an error document, its content is exposed by Code, Message, Details and TraceID.
+type Error struct {
+	StatusCode int
+	Method     string
+	URL        string
+	// The error code sent by the server, if any
+	Code string
+	// The error message sent by the server, if any
+	Message string
+	// Additional information about the error, e.g. the validation errors of a payload
+	Details []string
+	// The trace ID to provide to the support, if any
+	TraceID string
+	// The raw content of the response, if any
+	Body []byte
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("%s %s returned an unexpected status code %d", e.Method, e.URL, e.StatusCode)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// IsNotFound tells whether err was caused by a 404 response.
+func IsNotFound(err error) bool {
+	return HasStatus(err, http.StatusNotFound)
+}
+
+// HasStatus tells whether err was caused by a response with the provided status.
+func HasStatus(err error, statusCode int) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
+
+// errorDocument covers the error formats returned by the platform services:
+//
+//	{"code": "...", "message": "...", "details": [...], "traceId": "..."}
+//	{"errors": [{"code": "...", "message": "..."}]}
+//	{"error": "...", "message": "..."}
+type errorDocument struct {
+	Code    json.RawMessage `json:"code"`
+	Message string          `json:"message"`
+	Error   json.RawMessage `json:"error"`
+	Details json.RawMessage `json:"details"`
+	TraceID string          `json:"traceId"`
+	Errors  []struct {
+		Code    json.RawMessage `json:"code"`
+		Message string          `json:"message"`
+	} `json:"errors"`
+}
+
+func newError(method string, endpoint string, statusCode int, body []byte) *Error {
+	e := &Error{StatusCode: statusCode, Method: method, URL: endpoint, Body: body}
+
+	var doc errorDocument
+	if len(body) == 0 || json.Unmarshal(body, &doc) != nil {
+		return e
+	}
+
+	e.Code = rawString(doc.Code)
+	e.Message = doc.Message
+	e.TraceID = doc.TraceID
+	e.Details = parseDetails(doc.Details)
+
+	if e.Message == "" {
+		e.Message = rawString(doc.Error)
+	} else if e.Code == "" {
+		e.Code = rawString(doc.Error)
+	}
+
+	for i, item := range doc.Errors {
+		if i == 0 && e.Message == "" {
+			e.Code = rawString(item.Code)
+			e.Message = item.Message
+			continue
+		}
+		e.Details = append(e.Details, item.Message)
+	}
+
+	return e
+}
+
+// rawString returns the content of a JSON string or number.
+func rawString(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var n json.Number
+	if json.Unmarshal(raw, &n) == nil {
+		return n.String()
+	}
+	return ""
+}
+
+func parseDetails(raw json.RawMessage) []string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+
+	if s := rawString(raw); s != "" {
+		return []string{s}
+	}
+
+	var items []json.RawMessage
+	if json.Unmarshal(raw, &items) != nil {
+		return []string{string(raw)}
+	}
+
+	details := make([]string, 0, len(items))
+	for _, item := range items {
+		if s := rawString(item); s != "" {
+			details = append(details, s)
+			continue
+		}
+		var withMessage struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(item, &withMessage) == nil && withMessage.Message != "" {
+			details = append(details, withMessage.Message)
+			continue
+		}
+		details = append(details, strings.TrimSpace(string(item)))
+	}
+
+	return details
+}
@@ -0,0 +1,116 @@
+package workerclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Execute triggers a deployed GENERIC_EVENT worker with the provided payload and returns the raw response of the worker.
+func (c *Client) Execute(ctx context.Context, workerKey string, projectKey string, payload any) (json.RawMessage, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.Do(ctx, &Request{
+		Method:     http.MethodPost,
+		Path:       []string{"execute", workerKey},
+		ProjectKey: projectKey,
+		Body:       body,
+		OkStatuses: []int{http.StatusOK},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Body, nil
+}
+
+type TestRunOptions struct {
+	ProjectKey string
+	// Whether the debug logs are returned
+	Debug bool
+}
+
+// TestRun runs a source code in the sandbox of the server, under the key of a worker that does not have to be deployed.
+func (c *Client) TestRun(ctx context.Context, workerKey string, request *TestRunRequest, options TestRunOptions) (json.RawMessage, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.Do(ctx, &Request{
+		Method:     http.MethodPost,
+		Path:       []string{"test", workerKey},
+		ProjectKey: options.ProjectKey,
+		Query:      map[string]string{"debug": fmt.Sprint(options.Debug)},
+		Body:       body,
+		OkStatuses: []int{http.StatusOK},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Body, nil
+}
+
+type ExecutionHistoryOptions struct {
+	WorkerKey  string
+	ProjectKey string
+	// Whether the test runs are included
+	ShowTestRun bool
+	// Limit and Offset select a page of the history, the servers without pagination ignore them and return the whole history
+	Limit  int
+	Offset int
+	// Since, Until and Statuses filter the history on the servers supporting them, the others ignore them.
+	// Since and Until bound the start time of the executions, Until excluded
+	Since    time.Time
+	Until    time.Time
+	Statuses []string
+}
+
+// ExecutionHistory returns the last executions of a worker, or a page of them with a limit.
+func (c *Client) ExecutionHistory(ctx context.Context, options ExecutionHistoryOptions) ([]*ExecutionHistoryEntry, error) {
+	query := map[string]string{"workerKey": options.WorkerKey}
+	if options.ShowTestRun {
+		query["showTestRun"] = "true"
+	}
+	if options.Limit > 0 {
+		query["limit"] = strconv.Itoa(options.Limit)
+	}
+	if options.Offset > 0 {
+		query["offset"] = strconv.Itoa(options.Offset)
+	}
+	if !options.Since.IsZero() {
+		query["since"] = strconv.FormatInt(options.Since.UnixMilli(), 10)
+	}
+	if !options.Until.IsZero() {
+		query["until"] = strconv.FormatInt(options.Until.UnixMilli(), 10)
+	}
+	if len(options.Statuses) > 0 {
+		query["status"] = strings.Join(options.Statuses, ",")
+	}
+
+	res, err := c.Do(ctx, &Request{
+		Method:     http.MethodGet,
+		Path:       []string{"execution_history"},
+		ProjectKey: options.ProjectKey,
+		Query:      query,
+		OkStatuses: []int{http.StatusOK},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*ExecutionHistoryEntry, 0)
+	if err = decode(res, &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
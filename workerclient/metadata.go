@@ -0,0 +1,46 @@
+package workerclient
+
+import (
+	"context"
+	"net/http"
+)
+
+// Actions returns the actions a worker can be bound to, on the platform or in a project.
+func (c *Client) Actions(ctx context.Context, projectKey string) ([]*ActionMetadata, error) {
+	res, err := c.Do(ctx, &Request{
+		Method:     http.MethodGet,
+		Path:       []string{"actions"},
+		ProjectKey: projectKey,
+		APIVersion: APIVersionV2,
+		OkStatuses: []int{http.StatusOK},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	actions := make([]*ActionMetadata, 0)
+	if err = decode(res, &actions); err != nil {
+		return nil, err
+	}
+
+	return actions, nil
+}
+
+// Options returns the features and limits of the Worker Service.
+func (c *Client) Options(ctx context.Context) (*OptionsMetadata, error) {
+	res, err := c.Do(ctx, &Request{
+		Method:     http.MethodGet,
+		Path:       []string{"options"},
+		OkStatuses: []int{http.StatusOK},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	options := new(OptionsMetadata)
+	if err = decode(res, options); err != nil {
+		return nil, err
+	}
+
+	return options, nil
+}
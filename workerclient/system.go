@@ -0,0 +1,52 @@
+package workerclient
+
+import (
+	"context"
+	"net/http"
+)
+
+// ArtifactoryVersionEndpoint is the endpoint returning the version of Artifactory, relative to the server URL.
+const ArtifactoryVersionEndpoint = "artifactory/api/system/version"
+
+// ArtifactoryVersion returns the version of the Artifactory instance of the platform, e.g. 7.104.2.
+func (c *Client) ArtifactoryVersion(ctx context.Context) (string, error) {
+	res, err := c.Do(ctx, &Request{
+		Method:     http.MethodGet,
+		Endpoint:   ArtifactoryVersionEndpoint,
+		OkStatuses: []int{http.StatusOK},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var version struct {
+		Version string `json:"version"`
+	}
+	if err = decode(res, &version); err != nil {
+		return "", err
+	}
+
+	return version.Version, nil
+}
+
+// ProjectsEndpoint is the endpoint listing the projects of the platform, relative to the server URL.
+const ProjectsEndpoint = "access/api/v1/projects"
+
+// ListProjects returns the projects visible to the token.
+func (c *Client) ListProjects(ctx context.Context) ([]*Project, error) {
+	res, err := c.Do(ctx, &Request{
+		Method:     http.MethodGet,
+		Endpoint:   ProjectsEndpoint,
+		OkStatuses: []int{http.StatusOK},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []*Project
+	if err = decode(res, &projects); err != nil {
+		return nil, err
+	}
+
+	return projects, nil
+}
@@ -0,0 +1,192 @@
+package workerclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// RetryPolicy describes how a failed request should be retried.
+type RetryPolicy struct {
+	// The total number of attempts, including the first one. A value lower than 2 disables the retries.
+	MaxAttempts int
+	// The delay before the first retry, it is doubled at every attempt and randomized.
+	InitialBackoff time.Duration
+	// The upper bound of the delay between two attempts.
+	MaxBackoff time.Duration
+	// The response statuses that trigger a retry.
+	RetryableStatuses []int
+}
+
+// DefaultRetryableStatuses are the statuses denoting a transient failure of the server or of a proxy in front of it.
+var DefaultRetryableStatuses = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+type ActionFilterType string
+
+const (
+	FilterTypeRepo     = "FILTER_REPO"
+	FilterTypeSchedule = "SCHEDULE"
+)
+
+type Action struct {
+	Application string `json:"application"`
+	Name        string `json:"name"`
+}
+
+type ActionMetadata struct {
+	Action               Action           `json:"action"`
+	Description          string           `json:"description"`
+	SamplePayload        string           `json:"samplePayload"`
+	SampleCode           string           `json:"sampleCode"`
+	TypesDefinitions     string           `json:"typesDefinitions"`
+	SupportProjects      bool             `json:"supportProjects"`
+	FilterType           ActionFilterType `json:"filterType"`
+	MandatoryFilter      bool             `json:"mandatoryFilter"`
+	WikiURL              string           `json:"wikiUrl"`
+	Async                bool             `json:"async"`
+	ExecutionRequestType string           `json:"executionRequestType"`
+}
+
+type EditionOptions struct {
+	MaxCodeChars               int `json:"maxCodeChars"`
+	MaxVersionNumberChars      int `json:"maxVersionNumberChars"`
+	MaxVersionCommitShaChars   int `json:"maxVersionCommitShaChars"`
+	MaxVersionDescriptionChars int `json:"maxVersionDescriptionChars"`
+}
+
+type OptionsMetadata struct {
+	Edition                                EditionOptions `json:"edition"`
+	MinArtifactoryVersionForProjectSupport string         `json:"minArtifactoryVersionForProjectSupport"`
+	IsTutorialAvailable                    bool           `json:"isTutorialAvailable"`
+	IsFeedbackEnabled                      bool           `json:"isFeedbackEnabled"`
+	IsHistoryEnabled                       bool           `json:"isHistoryEnabled"`
+	ShouldEncodeSourceCodeInBase64         *bool          `json:"shouldEncodeSourceCodeInBase64"`
+}
+
+// Project is a JFrog Platform project, as returned by the Access service.
+type Project struct {
+	ProjectKey  string `json:"project_key"`
+	DisplayName string `json:"display_name"`
+}
+
+type ArtifactFilterCriteria struct {
+	RepoKeys     []string `json:"repoKeys,omitempty"`
+	AnyLocal     bool     `json:"anyLocal,omitempty"`
+	AnyFederated bool     `json:"anyFederated,omitempty"`
+	AnyRemote    bool     `json:"anyRemote,omitempty"`
+}
+
+type ScheduleFilterCriteria struct {
+	Cron     string `json:"cron,omitempty"`
+	Timezone string `json:"timezone,omitempty"`
+}
+
+type FilterCriteria struct {
+	ArtifactFilterCriteria *ArtifactFilterCriteria `json:"artifactFilterCriteria,omitempty"`
+	Schedule               *ScheduleFilterCriteria `json:"schedule,omitempty"`
+}
+
+type Version struct {
+	CommitSha   string `json:"commitSha"`
+	Description string `json:"description"`
+	Number      string `json:"versionNumber"`
+}
+
+func (v *Version) IsEmpty() bool {
+	return v == nil || (v.CommitSha == "" && v.Description == "" && v.Number == "")
+}
+
+type Secret struct {
+	Key              string `json:"key"`
+	Value            string `json:"value"`
+	MarkedForRemoval bool   `json:"markedForRemoval"`
+}
+
+type WorkerDetails struct {
+	Key            string          `json:"key"`
+	Description    string          `json:"description"`
+	Debug          bool            `json:"debug"`
+	Enabled        bool            `json:"enabled"`
+	SourceCode     string          `json:"sourceCode"`
+	Action         string          `json:"action"`
+	Application    string          `json:"application,omitempty"`
+	FilterCriteria *FilterCriteria `json:"filterCriteria,omitempty"`
+	Secrets        []*Secret       `json:"secrets"`
+	ProjectKey     string          `json:"projectKey"`
+}
+
+// WorkerRequest is the payload used to create or update a worker.
+type WorkerRequest struct {
+	Key            string          `json:"key"`
+	Description    string          `json:"description"`
+	Enabled        bool            `json:"enabled"`
+	Debug          bool            `json:"debug"`
+	SourceCode     string          `json:"sourceCode"`
+	Action         Action          `json:"action"`
+	FilterCriteria *FilterCriteria `json:"filterCriteria,omitempty"`
+	Secrets        []*Secret       `json:"secrets"`
+	ProjectKey     string          `json:"projectKey"`
+	Version        *Version        `json:"version,omitempty"`
+}
+
+// TestRunRequest is the payload used to run a worker source code in the sandbox without deploying it.
+type TestRunRequest struct {
+	Code          string         `json:"code"`
+	Action        string         `json:"action"`
+	StagedSecrets []*Secret      `json:"stagedSecrets,omitempty"`
+	Data          map[string]any `json:"data"`
+}
+
+// The statuses of the executions that did not end yet, the other statuses are final.
+var pendingExecutionStatuses = []string{"", "STATUS_UNSPECIFIED", "STATUS_PENDING", "STATUS_RUNNING", "STATUS_IN_PROGRESS", "STATUS_STARTED"}
+
+type ExecutionHistoryEntry struct {
+	WorkerKey        string `json:"workerKey"`
+	WorkerType       string `json:"workerType"`
+	WorkerProjectKey string `json:"workerProjectKey"`
+	ExecutionStatus  string `json:"executionStatus"`
+	StartTimeMillis  int64  `json:"startTimeMillis"`
+	EndTimeMillis    int64  `json:"endTimeMillis"`
+	TriggeredBy      string `json:"triggeredBy"`
+	TestRun          bool   `json:"testRun"`
+	ExecutedVersion  string `json:"executedVersion"`
+	TraceID          string `json:"traceId"`
+	// Raw is the entry as returned by the server, it is marshalled as is so that the fields unknown to the CLI are kept
+	Raw json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON reads the known fields of the entry and keeps its raw JSON.
+func (e *ExecutionHistoryEntry) UnmarshalJSON(data []byte) error {
+	type entry ExecutionHistoryEntry
+	if err := json.Unmarshal(data, (*entry)(e)); err != nil {
+		return err
+	}
+	e.Raw = slices.Clone(data)
+	return nil
+}
+
+// MarshalJSON returns the raw JSON of an entry read from the server, the known fields of the others.
+func (e *ExecutionHistoryEntry) MarshalJSON() ([]byte, error) {
+	if e.Raw != nil {
+		return e.Raw, nil
+	}
+	type entry ExecutionHistoryEntry
+	return json.Marshal((*entry)(e))
+}
+
+// Completed tells whether the execution ended, successfully or not.
+func (e *ExecutionHistoryEntry) Completed() bool {
+	return !slices.Contains(pendingExecutionStatuses, strings.ToUpper(e.ExecutionStatus))
+}
+
+// Succeeded tells whether the execution ended successfully.
+func (e *ExecutionHistoryEntry) Succeeded() bool {
+	return strings.HasSuffix(strings.ToUpper(e.ExecutionStatus), "SUCCESS")
+}
@@ -0,0 +1,50 @@
+//go:build test
+// +build test
+
+package workerclient
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutionHistoryEntry_Status(t *testing.T) {
+	tests := []struct {
+		status    string
+		completed bool
+		succeeded bool
+	}{
+		{status: "STATUS_SUCCESS", completed: true, succeeded: true},
+		{status: "STATUS_FAIL", completed: true},
+		{status: "STATUS_TIMEOUT", completed: true},
+		{status: "STATUS_RUNNING"},
+		{status: "status_pending"},
+		{status: ""},
+	}
+	for _, tt := range tests {
+		entry := &ExecutionHistoryEntry{ExecutionStatus: tt.status}
+		assert.Equal(t, tt.completed, entry.Completed(), tt.status)
+		assert.Equal(t, tt.succeeded, entry.Succeeded(), tt.status)
+	}
+}
+
+func TestExecutionHistoryEntry_JSON(t *testing.T) {
+	raw := `{"workerKey":"wk-1","executionStatus":"STATUS_SUCCESS","traceId":"trace","region":"eu-west-1","logs":[]}`
+
+	var entry ExecutionHistoryEntry
+	require.NoError(t, json.Unmarshal([]byte(raw), &entry))
+	assert.Equal(t, "wk-1", entry.WorkerKey)
+	assert.Equal(t, "trace", entry.TraceID)
+
+	marshalled, err := json.Marshal(&entry)
+	require.NoError(t, err)
+	assert.JSONEq(t, raw, string(marshalled))
+
+	marshalled, err = json.Marshal(&ExecutionHistoryEntry{WorkerKey: "wk-2"})
+	require.NoError(t, err)
+	assert.Contains(t, string(marshalled), `"workerKey":"wk-2"`)
+	assert.NotContains(t, string(marshalled), "Raw")
+}
@@ -0,0 +1,111 @@
+package workerclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type ListWorkersOptions struct {
+	// Only return the workers of this action
+	Action     string
+	ProjectKey string
+}
+
+type listWorkersResponse struct {
+	Workers []*WorkerDetails `json:"workers"`
+}
+
+// ListWorkers returns the workers deployed on the platform, or in a project when a project key is provided.
+func (c *Client) ListWorkers(ctx context.Context, options ListWorkersOptions) ([]*WorkerDetails, error) {
+	req := &Request{
+		Method:     http.MethodGet,
+		Path:       []string{"workers"},
+		ProjectKey: options.ProjectKey,
+		OkStatuses: []int{http.StatusOK},
+	}
+	if options.Action != "" {
+		req.Query = map[string]string{"action": options.Action}
+	}
+
+	res, err := c.Do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	content := listWorkersResponse{Workers: make([]*WorkerDetails, 0)}
+	if err = decode(res, &content); err != nil {
+		return nil, err
+	}
+
+	return content.Workers, nil
+}
+
+// GetWorker returns the details of a worker. The error satisfies IsNotFound when the worker does not exist.
+func (c *Client) GetWorker(ctx context.Context, workerKey string, projectKey string) (*WorkerDetails, error) {
+	res, err := c.Do(ctx, &Request{
+		Method:     http.MethodGet,
+		Path:       []string{"workers", workerKey},
+		ProjectKey: projectKey,
+		OkStatuses: []int{http.StatusOK},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	details := new(WorkerDetails)
+	if err = decode(res, details); err != nil {
+		return nil, err
+	}
+
+	return details, nil
+}
+
+// CreateWorker deploys a new worker.
+func (c *Client) CreateWorker(ctx context.Context, worker *WorkerRequest) error {
+	return c.saveWorker(ctx, http.MethodPost, http.StatusCreated, worker)
+}
+
+// UpdateWorker replaces the definition of an existing worker.
+func (c *Client) UpdateWorker(ctx context.Context, worker *WorkerRequest) error {
+	return c.saveWorker(ctx, http.MethodPut, http.StatusNoContent, worker)
+}
+
+func (c *Client) saveWorker(ctx context.Context, method string, okStatus int, worker *WorkerRequest) error {
+	body, err := json.Marshal(worker)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.Do(ctx, &Request{
+		Method:     method,
+		Path:       []string{"workers"},
+		APIVersion: APIVersionV2,
+		Body:       body,
+		OkStatuses: []int{okStatus},
+	})
+
+	return err
+}
+
+// DeleteWorker undeploys a worker.
+func (c *Client) DeleteWorker(ctx context.Context, workerKey string, projectKey string) error {
+	_, err := c.Do(ctx, &Request{
+		Method:     http.MethodDelete,
+		Path:       []string{"workers", workerKey},
+		ProjectKey: projectKey,
+		OkStatuses: []int{http.StatusNoContent},
+	})
+	return err
+}
+
+func decode(res *Response, target any) error {
+	if len(res.Body) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(res.Body, target); err != nil {
+		return fmt.Errorf("cannot decode the response: %w", err)
+	}
+	return nil
+}